@@ -1,12 +1,128 @@
 package config
 
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/orchestra-mcp/mcp/src/auth"
+	"gopkg.in/yaml.v3"
+)
+
 // McpConfig holds configuration for the Orchestra MCP plugin.
 type McpConfig struct {
-	Enabled bool   `json:"enabled" yaml:"enabled"`
-	Binary  string `json:"binary" yaml:"binary"`
+	Enabled bool          `json:"enabled" yaml:"enabled"`
+	Binary  string        `json:"binary" yaml:"binary"`
+	Auth    AuthConfig    `json:"auth" yaml:"auth"`
+	Tracing TracingConfig `json:"tracing" yaml:"tracing"`
+	// Profile and Toolsets select the built-in tools like the binary's
+	// --profile and --toolsets flags, over the workspace's
+	// .orchestra/config.yaml.
+	Profile  string   `json:"profile" yaml:"profile"`
+	Toolsets []string `json:"toolsets" yaml:"toolsets"`
+	// RootDirs are the directories client roots must lie under. Roots
+	// name directories on the client's machine, so others are ignored.
+	RootDirs []string `json:"root_dirs" yaml:"root_dirs"`
+}
+
+// TracingConfig exports OpenTelemetry spans. Tracing is off while both
+// fields are empty, unless OTEL_EXPORTER_OTLP_ENDPOINT is set.
+type TracingConfig struct {
+	// OTLPEndpoint is an OTLP/gRPC collector address, e.g. "localhost:4317".
+	OTLPEndpoint string `json:"otlp_endpoint" yaml:"otlp_endpoint"`
+	// File receives spans as JSON lines for offline debugging.
+	File string `json:"file" yaml:"file"`
+}
+
+// AuthConfig protects the REST, SSE and Streamable HTTP routes. Auth is
+// off while Tokens is empty.
+type AuthConfig struct {
+	Tokens []TokenConfig `json:"tokens" yaml:"tokens"`
+	// ToolScopes overrides the scope a tool requires, e.g. {"create_project": "admin"}.
+	ToolScopes map[string]string `json:"tool_scopes" yaml:"tool_scopes"`
+}
+
+// LoadAuth reads an auth config file, as passed to the binary's
+// --auth-config flag: YAML for .yaml and .yml, JSON otherwise.
+func LoadAuth(path string) (AuthConfig, error) {
+	var cfg AuthConfig
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &cfg)
+	default:
+		err = json.Unmarshal(data, &cfg)
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("auth config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Authenticator builds the authenticator for the configured tokens.
+func (c AuthConfig) Authenticator() (*auth.Authenticator, error) {
+	tokens := make([]auth.Token, 0, len(c.Tokens))
+	for _, tc := range c.Tokens {
+		tok := auth.Token{Name: tc.Name, Secret: tc.Secret()}
+		for _, s := range tc.Scopes {
+			scope, err := auth.ParseScope(s)
+			if err != nil {
+				return nil, err
+			}
+			tok.Scopes = append(tok.Scopes, scope)
+		}
+		tokens = append(tokens, tok)
+	}
+	toolScopes := make(map[string]auth.Scope, len(c.ToolScopes))
+	for name, s := range c.ToolScopes {
+		scope, err := auth.ParseScope(s)
+		if err != nil {
+			return nil, err
+		}
+		toolScopes[name] = scope
+	}
+	return auth.New(tokens, toolScopes)
+}
+
+// TokenConfig is one API token. Set either Token or TokenEnv, the name of
+// an environment variable holding the token.
+type TokenConfig struct {
+	Name     string   `json:"name" yaml:"name"`
+	Token    string   `json:"token" yaml:"token"`
+	TokenEnv string   `json:"token_env" yaml:"token_env"`
+	Scopes   []string `json:"scopes" yaml:"scopes"`
+}
+
+// Secret returns the token value, reading TokenEnv when Token is empty.
+func (t TokenConfig) Secret() string {
+	if t.Token != "" {
+		return t.Token
+	}
+	if t.TokenEnv != "" {
+		return os.Getenv(t.TokenEnv)
+	}
+	return ""
 }
 
 // Default returns the default MCP configuration.
 func Default() McpConfig {
 	return McpConfig{Enabled: true, Binary: "orchestra-mcp"}
 }
+
+// FromMap decodes the plugin's config section over the defaults.
+func FromMap(m map[string]any) (McpConfig, error) {
+	cfg := Default()
+	data, err := json.Marshal(m)
+	if err != nil {
+		return cfg, fmt.Errorf("mcp config: %w", err)
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("mcp config: %w", err)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,39 @@
+package providers
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"github.com/orchestra-mcp/mcp/src/auth"
+)
+
+// principalKey stores the authenticated principal in fiber locals.
+const principalKey = "mcp.principal"
+
+// authMiddleware rejects requests without a valid bearer token or API key
+// once tokens are configured, and stores the principal for the handlers.
+func (p *McpPlugin) authMiddleware(c fiber.Ctx) error {
+	if !p.auth.Enabled() {
+		return c.Next()
+	}
+	principal, err := p.auth.Authenticate(auth.Credential(c.Get("Authorization"), c.Get("X-API-Key")))
+	if err != nil {
+		c.Set("WWW-Authenticate", `Bearer realm="orchestra-mcp"`)
+		return authError(c, err)
+	}
+	c.Locals(principalKey, principal)
+	return c.Next()
+}
+
+// principal returns the principal authMiddleware stored, or nil.
+func principal(c fiber.Ctx) *auth.Principal {
+	p, _ := c.Locals(principalKey).(*auth.Principal)
+	return p
+}
+
+// authError answers with 401 or 403 and the matching JSON-RPC code.
+func authError(c fiber.Ctx, err error) error {
+	status := fiber.StatusUnauthorized
+	if auth.Code(err) == auth.CodeForbidden {
+		status = fiber.StatusForbidden
+	}
+	return c.Status(status).JSON(fiber.Map{"error": err.Error(), "code": auth.Code(err)})
+}
@@ -1,17 +1,29 @@
 package providers
 
 import (
+	"context"
 	"fmt"
 	"os/exec"
 	"sync"
 
 	"github.com/orchestra-mcp/framework/app/plugins"
+	"github.com/orchestra-mcp/mcp/config"
+	"github.com/orchestra-mcp/mcp/src/auth"
+	"github.com/orchestra-mcp/mcp/src/logger"
+	"github.com/orchestra-mcp/mcp/src/toolsets"
+	"github.com/orchestra-mcp/mcp/src/tracing"
+	"github.com/orchestra-mcp/mcp/src/transport"
 	"github.com/orchestra-mcp/mcp/src/version"
+	"github.com/rs/zerolog"
 )
 
 // McpPlugin implements the Orchestra plugin interface for the MCP server.
-// Other plugins can push tools, resources, and prompts via Register* methods.
+// Other plugins can push tools, resources, and prompts via Register* methods;
+// once the SSE server is running they are also pushed into it so connected
+// clients receive list_changed notifications.
 type McpPlugin struct {
+	srvMu             sync.Mutex // orders server creation with external registration
+	server            *transport.MCPServer
 	mu                sync.RWMutex
 	active            bool
 	ctx               *plugins.PluginContext
@@ -19,6 +31,12 @@ type McpPlugin struct {
 	externalTools     []plugins.McpToolDefinition
 	externalResources []plugins.McpResourceDefinition
 	externalPrompts   []plugins.McpPromptDefinition
+	removeLogSink     func()
+	auth              *auth.Authenticator
+	middleware        []transport.Middleware
+	stopTracing       func(context.Context) error
+	selection         toolsets.Selection
+	rootDirs          []string
 }
 
 // NewMcpPlugin creates a new MCP plugin instance.
@@ -38,25 +56,108 @@ func (p *McpPlugin) DefaultConfig() map[string]any {
 
 func (p *McpPlugin) Activate(ctx *plugins.PluginContext) error {
 	p.ctx = ctx
-	p.active = true
 	if ws := ctx.GetConfigString("workspace"); ws != "" {
 		p.workspace = ws
 	}
+	cfg, err := config.FromMap(ctx.Config)
+	if err != nil {
+		return err
+	}
+	if p.auth, err = cfg.Auth.Authenticator(); err != nil {
+		return fmt.Errorf("mcp auth: %w", err)
+	}
+	ts, err := toolsets.Load(p.workspace)
+	if err != nil {
+		return fmt.Errorf("mcp toolsets: %w", err)
+	}
+	if p.selection, err = ts.Resolve(cfg.Profile, cfg.Toolsets); err != nil {
+		return fmt.Errorf("mcp toolsets: %w", err)
+	}
+	if err = p.selection.ValidateTools(p.builtinGroups()); err != nil {
+		return fmt.Errorf("mcp toolsets: %w", err)
+	}
+	p.rootDirs = cfg.RootDirs
+	if p.stopTracing == nil {
+		p.stopTracing, err = tracing.Setup(context.Background(), tracing.Config{
+			OTLPEndpoint: cfg.Tracing.OTLPEndpoint, File: cfg.Tracing.File, ServiceVersion: p.Version(),
+		})
+		if err != nil {
+			return fmt.Errorf("mcp tracing: %w", err)
+		}
+	}
+	p.active = true
+	if p.removeLogSink == nil {
+		p.removeLogSink = logger.Default().AddSink(zerologSink(ctx.Logger))
+	}
 	ctx.Logger.Info().Str("plugin", p.ID()).Msg("MCP plugin activated")
 	return nil
 }
 
 func (p *McpPlugin) Deactivate() error {
 	p.active = false
+	if p.removeLogSink != nil {
+		p.removeLogSink()
+		p.removeLogSink = nil
+	}
+	if p.stopTracing != nil {
+		err := p.stopTracing(context.Background())
+		p.stopTracing = nil
+		return err
+	}
 	return nil
 }
 
+// zerologSink forwards MCP log entries to the host's logger.
+func zerologSink(zl zerolog.Logger) logger.Sink {
+	return logger.SinkFunc(func(e logger.Entry) {
+		var ev *zerolog.Event
+		switch {
+		case e.Level >= logger.LevelError:
+			ev = zl.Error()
+		case e.Level == logger.LevelWarning:
+			ev = zl.Warn()
+		case e.Level == logger.LevelDebug:
+			ev = zl.Debug()
+		default:
+			ev = zl.Info()
+		}
+		ev.Str("component", e.Logger).Fields(e.Fields).Msg(e.Message)
+	})
+}
+
 // RegisterExternalTools allows other plugins to push tools into the MCP server.
 // These tools appear in stdio, REST, and CollectMcpTools responses.
 func (p *McpPlugin) RegisterExternalTools(tools []plugins.McpToolDefinition) {
+	p.srvMu.Lock()
 	p.mu.Lock()
-	defer p.mu.Unlock()
 	p.externalTools = append(p.externalTools, tools...)
+	p.mu.Unlock()
+	server := p.server
+	p.srvMu.Unlock()
+	if server != nil {
+		server.RegisterTools(toolsFromDefs(tools))
+	}
+}
+
+// UseToolMiddleware lets other plugins wrap every tool call, on SSE and
+// REST alike, with their own policies. Middleware runs inside the built-in
+// chain, in registration order.
+func (p *McpPlugin) UseToolMiddleware(mw ...transport.Middleware) {
+	p.srvMu.Lock()
+	p.mu.Lock()
+	p.middleware = append(p.middleware, mw...)
+	p.mu.Unlock()
+	server := p.server
+	p.srvMu.Unlock()
+	if server != nil {
+		server.Use(mw...)
+	}
+}
+
+func (p *McpPlugin) toolMiddleware() []transport.Middleware {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return append([]transport.Middleware(nil), p.middleware...)
 }
 
 // ExternalTools returns a copy of all registered external tools.
@@ -70,9 +171,15 @@ func (p *McpPlugin) ExternalTools() []plugins.McpToolDefinition {
 
 // RegisterExternalResources allows other plugins to push resources into MCP.
 func (p *McpPlugin) RegisterExternalResources(resources []plugins.McpResourceDefinition) {
+	p.srvMu.Lock()
 	p.mu.Lock()
-	defer p.mu.Unlock()
 	p.externalResources = append(p.externalResources, resources...)
+	p.mu.Unlock()
+	server := p.server
+	p.srvMu.Unlock()
+	if server != nil {
+		server.RegisterResources(resourcesFromDefs(resources))
+	}
 }
 
 // ExternalResources returns a copy of all registered external resources.
@@ -86,9 +193,15 @@ func (p *McpPlugin) ExternalResources() []plugins.McpResourceDefinition {
 
 // RegisterExternalPrompts allows other plugins to push prompts into MCP.
 func (p *McpPlugin) RegisterExternalPrompts(prompts []plugins.McpPromptDefinition) {
+	p.srvMu.Lock()
 	p.mu.Lock()
-	defer p.mu.Unlock()
 	p.externalPrompts = append(p.externalPrompts, prompts...)
+	p.mu.Unlock()
+	server := p.server
+	p.srvMu.Unlock()
+	if server != nil {
+		server.RegisterPrompts(promptsFromDefs(prompts))
+	}
 }
 
 // ExternalPrompts returns a copy of all registered external prompts.
@@ -5,7 +5,9 @@ import (
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/orchestra-mcp/framework/app/plugins"
+	h "github.com/orchestra-mcp/mcp/src/helpers"
 	"github.com/orchestra-mcp/mcp/src/tools"
+	"github.com/orchestra-mcp/mcp/src/transport"
 	t "github.com/orchestra-mcp/mcp/src/types"
 )
 
@@ -16,7 +18,11 @@ func (p *McpPlugin) builtinResources() []t.Resource {
 
 // externalAsResources converts external McpResourceDefinitions to internal types.
 func (p *McpPlugin) externalAsResources() []t.Resource {
-	ext := p.ExternalResources()
+	return resourcesFromDefs(p.ExternalResources())
+}
+
+// resourcesFromDefs converts plugin resource definitions to internal types.
+func resourcesFromDefs(ext []plugins.McpResourceDefinition) []t.Resource {
 	out := make([]t.Resource, len(ext))
 	for i, def := range ext {
 		handler := def.Handler
@@ -85,7 +91,11 @@ func (p *McpPlugin) builtinPrompts() []t.Prompt {
 
 // externalAsPrompts converts external McpPromptDefinitions to internal types.
 func (p *McpPlugin) externalAsPrompts() []t.Prompt {
-	ext := p.ExternalPrompts()
+	return promptsFromDefs(p.ExternalPrompts())
+}
+
+// promptsFromDefs converts plugin prompt definitions to internal types.
+func promptsFromDefs(ext []plugins.McpPromptDefinition) []t.Prompt {
 	out := make([]t.Prompt, len(ext))
 	for i, def := range ext {
 		handler := def.Handler
@@ -158,11 +168,11 @@ func (p *McpPlugin) registerResourcePromptRoutes(mcp fiber.Router) {
 	// GET /api/mcp/resources — list all resources.
 	mcp.Get("/resources", func(c fiber.Ctx) error {
 		resources := p.allResources()
-		defs := make([]t.ResourceDefinition, len(resources))
-		for i, r := range resources {
-			defs[i] = r.Definition
-		}
-		return c.JSON(fiber.Map{"resources": defs, "count": len(defs)})
+		defs := transport.ResourceDefinitions(resources)
+		return c.JSON(fiber.Map{
+			"resources": defs, "resourceTemplates": transport.ResourceTemplates(resources),
+			"count": len(defs),
+		})
 	})
 
 	// POST /api/mcp/resources/read — read a resource by URI.
@@ -174,7 +184,7 @@ func (p *McpPlugin) registerResourcePromptRoutes(mcp fiber.Router) {
 			return c.Status(400).JSON(fiber.Map{"error": "invalid request body"})
 		}
 		for _, r := range p.allResources() {
-			if r.Definition.URI == req.URI || matchResourceURI(r.Definition.URI, req.URI) {
+			if _, ok := h.MatchURITemplate(r.Definition.URI, req.URI); ok {
 				contents, err := r.Handler(req.URI)
 				if err != nil {
 					return c.Status(500).JSON(fiber.Map{"error": err.Error()})
@@ -216,44 +226,3 @@ func (p *McpPlugin) registerResourcePromptRoutes(mcp fiber.Router) {
 		return c.Status(404).JSON(fiber.Map{"error": fmt.Sprintf("unknown prompt: %s", req.Name)})
 	})
 }
-
-// matchResourceURI checks if a concrete URI matches a template pattern.
-func matchResourceURI(pattern, uri string) bool {
-	pp := split(pattern, "/")
-	up := split(uri, "/")
-	if len(pp) != len(up) {
-		return false
-	}
-	for i, seg := range pp {
-		if len(seg) > 2 && seg[0] == '{' && seg[len(seg)-1] == '}' {
-			continue
-		}
-		if seg != up[i] {
-			return false
-		}
-	}
-	return true
-}
-
-func split(s, sep string) []string {
-	var parts []string
-	for s != "" {
-		i := indexOf(s, sep)
-		if i < 0 {
-			parts = append(parts, s)
-			break
-		}
-		parts = append(parts, s[:i])
-		s = s[i+len(sep):]
-	}
-	return parts
-}
-
-func indexOf(s, sub string) int {
-	for i := 0; i <= len(s)-len(sub); i++ {
-		if s[i:i+len(sub)] == sub {
-			return i
-		}
-	}
-	return -1
-}
@@ -2,54 +2,53 @@ package providers
 
 import (
 	"bufio"
-	"encoding/json"
+	"bytes"
 	"fmt"
-	"time"
 
 	"github.com/gofiber/fiber/v3"
+	"github.com/orchestra-mcp/mcp/src/audit"
+	"github.com/orchestra-mcp/mcp/src/auth"
+	"github.com/orchestra-mcp/mcp/src/metrics"
 	"github.com/orchestra-mcp/mcp/src/transport"
-	"github.com/orchestra-mcp/mcp/src/types"
 )
 
 // registerSSERoutes adds SSE transport endpoints to the MCP router.
 func (p *McpPlugin) registerSSERoutes(mcp fiber.Router) {
 	mgr := transport.NewSSESessionManager()
+	metrics.SSESessions.SetFunc(func() float64 { return float64(mgr.Count()) }, "sse")
 
-	// GET /api/mcp/sse — SSE event stream connection.
+	// GET /api/mcp/sse — SSE event stream connection. Passing the
+	// sessionId of an unexpired session resumes it, replaying events after
+	// the Last-Event-ID header.
 	mcp.Get("/sse", func(c fiber.Ctx) error {
+		var sess *transport.SSESession
+		if id := c.Query("sessionId"); id != "" {
+			var ok bool
+			if sess, ok = mgr.Get(id); !ok {
+				return c.Status(404).JSON(fiber.Map{"error": "session not found"})
+			}
+			if owner := sess.State.Principal(); owner != nil && owner != principal(c) {
+				return authError(c, fmt.Errorf("%w: session belongs to another token", auth.ErrForbidden))
+			}
+		} else {
+			sess = mgr.Create()
+			sess.State.SetPrincipal(principal(c))
+		}
+		endpoint := fmt.Sprintf("/api/mcp/messages?sessionId=%s", sess.ID)
+		lastEventID := c.Get("Last-Event-ID")
+
 		c.Set("Content-Type", "text/event-stream")
 		c.Set("Cache-Control", "no-cache")
 		c.Set("Connection", "keep-alive")
 		c.Set("X-Accel-Buffering", "no")
 
-		sess := mgr.Create()
-		endpoint := fmt.Sprintf("/api/mcp/messages?sessionId=%s", sess.ID)
-
 		return c.SendStreamWriter(func(w *bufio.Writer) {
-			defer mgr.Remove(sess.ID)
-
 			// Send endpoint event per MCP SSE protocol.
 			fmt.Fprintf(w, "event: endpoint\ndata: %s\n\n", endpoint)
 			w.Flush()
 
-			ticker := time.NewTicker(15 * time.Second)
-			defer ticker.Stop()
-
-			for {
-				select {
-				case msg, ok := <-sess.Messages:
-					if !ok {
-						return
-					}
-					fmt.Fprintf(w, "event: message\ndata: %s\n\n", msg)
-					w.Flush()
-				case <-ticker.C:
-					fmt.Fprint(w, ": ping\n\n")
-					w.Flush()
-				case <-sess.Context().Done():
-					return
-				}
-			}
+			// The session outlives the stream until its idle TTL passes.
+			_ = sess.Stream(sess.Context(), w, w.Flush, lastEventID)
 		})
 	})
 
@@ -63,28 +62,43 @@ func (p *McpPlugin) registerSSERoutes(mcp fiber.Router) {
 		if !ok {
 			return c.Status(404).JSON(fiber.Map{"error": "session not found"})
 		}
-		var req types.JSONRPCRequest
-		if err := json.Unmarshal(c.Body(), &req); err != nil {
-			return c.Status(400).JSON(fiber.Map{"error": "invalid JSON-RPC request"})
+		// Only the token that opened the stream may post to it.
+		if owner := sess.State.Principal(); owner != nil && owner != principal(c) {
+			return authError(c, fmt.Errorf("%w: session belongs to another token", auth.ErrForbidden))
 		}
-		server := p.createMCPServer()
+		server := p.mcpServer()
 		writer := transport.NewSSEWriter(sess)
-		server.HandleRequest(&req, writer)
+		// Responses, including parse and invalid-request errors, travel over
+		// the event stream, so handle asynchronously and let
+		// notifications/cancelled reach in-flight calls.
+		body := bytes.Clone(c.Body()) // fiber reuses the body buffer
+		go server.HandleMessage(sess.Context(), body, writer)
 		return c.SendStatus(202)
 	})
 }
 
+// mcpServer returns the server shared by all SSE sessions, building it on
+// first use. Later external registrations are pushed into it directly.
+func (p *McpPlugin) mcpServer() *transport.MCPServer {
+	p.srvMu.Lock()
+	defer p.srvMu.Unlock()
+	if p.server == nil {
+		p.server = p.createMCPServer()
+	}
+	return p.server
+}
+
 // createMCPServer builds a fresh MCPServer with all registered tools/resources/prompts.
 func (p *McpPlugin) createMCPServer() *transport.MCPServer {
 	server := transport.New("orchestra-mcp", p.Version())
-	for _, tool := range p.allTools() {
-		server.RegisterTool(tool)
-	}
-	for _, r := range p.allResources() {
-		server.RegisterResource(r)
-	}
-	for _, pr := range p.allPrompts() {
-		server.RegisterPrompt(pr)
-	}
+	server.SetAuthenticator(p.auth)
+	server.SetRootDirs(p.rootDirs...)
+	server.Use(audit.Middleware(p.workspace))
+	server.OnReject(audit.Rejections(p.workspace))
+	server.Use(transport.DefaultMiddleware()...)
+	server.Use(p.toolMiddleware()...)
+	server.RegisterTools(p.allTools())
+	server.RegisterResources(p.allResources())
+	server.RegisterPrompts(p.allPrompts())
 	return server
 }
@@ -1,35 +1,62 @@
 package providers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/orchestra-mcp/framework/app/plugins"
+	"github.com/orchestra-mcp/mcp/src/auth"
+	h "github.com/orchestra-mcp/mcp/src/helpers"
+	"github.com/orchestra-mcp/mcp/src/metrics"
 	"github.com/orchestra-mcp/mcp/src/tools"
+	"github.com/orchestra-mcp/mcp/src/toolsets"
+	"github.com/orchestra-mcp/mcp/src/transport"
 	t "github.com/orchestra-mcp/mcp/src/types"
 )
 
-// allTools returns all built-in MCP tools for the configured workspace.
-func (p *McpPlugin) builtinTools() []t.Tool {
+// builtinGroups returns the plugin's built-in MCP tools for the configured
+// workspace, keyed by toolset.
+func (p *McpPlugin) builtinGroups() map[string][]t.Tool {
 	ws := p.workspace
+	return map[string][]t.Tool{
+		"project":   tools.Rooted(ws, tools.Project),
+		"epic":      tools.Rooted(ws, tools.Epic),
+		"story":     tools.Rooted(ws, tools.Story),
+		"task":      tools.Rooted(ws, tools.Task),
+		"workflow":  tools.Rooted(ws, tools.Workflow),
+		"prd":       tools.Rooted(ws, tools.Prd),
+		"bugfix":    tools.Rooted(ws, tools.Bugfix),
+		"usage":     tools.Rooted(ws, tools.Usage),
+		"readme":    tools.Rooted(ws, tools.Readme),
+		"artifacts": tools.Rooted(ws, tools.Artifacts),
+		"audit":     tools.Audit(ws),
+	}
+}
+
+// builtinTools returns the built-in MCP tools that the selected profile and
+// toolsets expose.
+func (p *McpPlugin) builtinTools() []t.Tool {
+	groups := p.builtinGroups()
 	var all []t.Tool
-	all = append(all, tools.Project(ws)...)
-	all = append(all, tools.Epic(ws)...)
-	all = append(all, tools.Story(ws)...)
-	all = append(all, tools.Task(ws)...)
-	all = append(all, tools.Workflow(ws)...)
-	all = append(all, tools.Prd(ws)...)
-	all = append(all, tools.Bugfix(ws)...)
-	all = append(all, tools.Usage(ws)...)
-	all = append(all, tools.Readme(ws)...)
-	all = append(all, tools.Artifacts(ws)...)
+	for _, group := range toolsets.Groups {
+		all = append(all, p.selection.Filter(group, groups[group])...)
+	}
 	return all
 }
 
 // externalAsTools converts external McpToolDefinitions to internal Tool structs.
 func (p *McpPlugin) externalAsTools() []t.Tool {
-	ext := p.ExternalTools()
+	return toolsFromDefs(p.ExternalTools())
+}
+
+// toolsFromDefs converts plugin tool definitions to internal Tool structs.
+// Plugin schemas are not guaranteed to list every argument, so extra
+// arguments are allowed.
+func toolsFromDefs(ext []plugins.McpToolDefinition) []t.Tool {
+	allowExtra := true
 	out := make([]t.Tool, len(ext))
 	for i, def := range ext {
 		handler := def.Handler
@@ -37,7 +64,7 @@ func (p *McpPlugin) externalAsTools() []t.Tool {
 			Definition: t.ToolDefinition{
 				Name:        def.Name,
 				Description: def.Description,
-				InputSchema: t.InputSchema{Type: "object", Properties: def.InputSchema},
+				InputSchema: t.InputSchema{Type: "object", Properties: def.InputSchema, AdditionalProperties: &allowExtra},
 			},
 			Handler: func(args map[string]any) (*t.ToolResult, error) {
 				res, err := handler(args)
@@ -64,26 +91,48 @@ func (p *McpPlugin) allTools() []t.Tool {
 }
 
 // McpTools bridges internal tools to the plugin system's McpToolDefinition.
+// Calls go through the shared server's CallTool, so authorization,
+// validation, middleware, audit and metrics apply as on every transport.
+// The host runs in the same process and is trusted, so calls carry
+// auth.Local and pass any configured token scopes.
 func (p *McpPlugin) McpTools() []plugins.McpToolDefinition {
 	internal := p.allTools()
 	defs := make([]plugins.McpToolDefinition, len(internal))
 	for i, tool := range internal {
-		handler := tool.Handler
+		name := tool.Definition.Name
 		defs[i] = plugins.McpToolDefinition{
-			Name:        tool.Definition.Name,
+			Name:        name,
 			Description: tool.Definition.Description,
 			InputSchema: toSchemaMap(tool.Definition.InputSchema),
 			Handler: func(input map[string]any) (any, error) {
-				return handler(input)
+				result, err := p.mcpServer().CallTool(context.Background(), &transport.ToolCall{Name: name, Arguments: input, Principal: auth.Local})
+				if err != nil {
+					return nil, err
+				}
+				return result, nil
 			},
 		}
 	}
 	return defs
 }
 
+// McpToolAnnotations returns the annotations of every tool that declares them,
+// keyed by tool name. plugins.McpToolDefinition has no annotations field, so
+// hosts that bridge McpTools look them up here.
+func (p *McpPlugin) McpToolAnnotations() map[string]*t.ToolAnnotations {
+	out := make(map[string]*t.ToolAnnotations)
+	for _, tool := range p.allTools() {
+		if a := tool.Definition.Annotations; a != nil {
+			out[tool.Definition.Name] = a
+		}
+	}
+	return out
+}
+
 // RegisterRoutes adds REST API endpoints for all MCP tools, resources, and prompts.
 func (p *McpPlugin) RegisterRoutes(router fiber.Router) {
 	mcp := router.Group("/mcp")
+	mcp.Use(p.authMiddleware)
 
 	// GET /api/mcp/tools — list all available tools.
 	mcp.Get("/tools", func(c fiber.Ctx) error {
@@ -104,21 +153,30 @@ func (p *McpPlugin) RegisterRoutes(router fiber.Router) {
 		if err := c.Bind().JSON(&req); err != nil {
 			return c.Status(400).JSON(fiber.Map{"error": "invalid request body"})
 		}
-		toolMap := make(map[string]t.Tool)
-		for _, tool := range p.allTools() {
-			toolMap[tool.Definition.Name] = tool
-		}
-		tool, ok := toolMap[req.Name]
-		if !ok {
-			return c.Status(404).JSON(fiber.Map{"error": "unknown tool: " + req.Name})
-		}
-		result, err := tool.Handler(req.Arguments)
-		if err != nil {
+		result, err := p.mcpServer().CallTool(c.Context(), &transport.ToolCall{
+			Name: req.Name, Arguments: req.Arguments, Principal: principal(c),
+		})
+		var invalid h.ValidationErrors
+		switch {
+		case errors.Is(err, transport.ErrUnknownTool):
+			return c.Status(404).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, auth.ErrUnauthorized), errors.Is(err, auth.ErrForbidden):
+			return authError(c, err)
+		case errors.As(err, &invalid):
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		case err != nil:
 			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
 		}
 		return c.JSON(result)
 	})
 
+	// GET /api/mcp/metrics — OpenMetrics exposition.
+	mcp.Get("/metrics", func(c fiber.Ctx) error {
+		c.Set("Content-Type", metrics.ContentType)
+		_, err := metrics.Default.WriteTo(c)
+		return err
+	})
+
 	// Register resource and prompt REST routes.
 	p.registerResourcePromptRoutes(mcp)
 
@@ -0,0 +1,188 @@
+// Package audit keeps an append-only JSONL log of every tool call so that
+// changes to the workspace can be traced back to a caller and session.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	h "github.com/orchestra-mcp/mcp/src/helpers"
+)
+
+// Entry is one recorded tool call.
+type Entry struct {
+	Time       time.Time      `json:"time"`
+	Tool       string         `json:"tool"`
+	Arguments  map[string]any `json:"arguments,omitempty"` // redacted
+	Caller     string         `json:"caller,omitempty"`    // token name, else client name
+	SessionID  string         `json:"session_id,omitempty"`
+	DurationMs int64          `json:"duration_ms"`
+	ErrorCode  int            `json:"error_code,omitempty"` // JSON-RPC code when the call failed
+	Error      string         `json:"error,omitempty"`
+	Issues     []string       `json:"issues,omitempty"`
+}
+
+// Dir returns the audit directory of a workspace. Entries go to one file
+// per UTC day, named YYYY-MM-DD.jsonl.
+func Dir(ws string) string {
+	return filepath.Join(h.ProjectsDir(ws), ".audit")
+}
+
+const dayLayout = "2006-01-02"
+
+// writeMu serializes writers inside one process. Each entry is written
+// with a single append, so lines from other processes do not interleave.
+var writeMu sync.Mutex
+
+// Append adds e to the workspace log. Files are only ever appended to.
+func Append(ws string, e Entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	dir := Dir(ws)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	writeMu.Lock()
+	defer writeMu.Unlock()
+	f, err := os.OpenFile(filepath.Join(dir, e.Time.UTC().Format(dayLayout)+".jsonl"), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Filter selects entries. Zero fields match everything; Limit keeps the
+// newest entries.
+type Filter struct {
+	Tool    string
+	Issue   string
+	Session string
+	Since   time.Time
+	Until   time.Time
+	Limit   int
+}
+
+func (f Filter) match(e Entry) bool {
+	if f.Tool != "" && e.Tool != f.Tool {
+		return false
+	}
+	if f.Session != "" && e.SessionID != f.Session {
+		return false
+	}
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && e.Time.After(f.Until) {
+		return false
+	}
+	if f.Issue != "" {
+		for _, id := range e.Issues {
+			if strings.EqualFold(id, f.Issue) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Query returns the matching entries, oldest first. Lines that cannot be
+// decoded are skipped.
+func Query(ws string, f Filter) ([]Entry, error) {
+	files, err := filepath.Glob(filepath.Join(Dir(ws), "*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	var out []Entry
+	for _, file := range files {
+		if !f.coversDay(strings.TrimSuffix(filepath.Base(file), ".jsonl")) {
+			continue
+		}
+		entries, err := readFile(file, f)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, entries...)
+	}
+	if f.Limit > 0 && len(out) > f.Limit {
+		out = out[len(out)-f.Limit:]
+	}
+	return out, nil
+}
+
+// coversDay reports whether the day a log file holds can overlap the
+// filter's time range.
+func (f Filter) coversDay(name string) bool {
+	day, err := time.Parse(dayLayout, name)
+	if err != nil {
+		return true
+	}
+	if !f.Since.IsZero() && day.Add(24*time.Hour).Before(f.Since) {
+		return false
+	}
+	return f.Until.IsZero() || !day.After(f.Until)
+}
+
+func readFile(path string, f Filter) ([]Entry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var out []Entry
+	sc := bufio.NewScanner(file)
+	sc.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	for sc.Scan() {
+		var e Entry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			continue
+		}
+		if f.match(e) {
+			out = append(out, e)
+		}
+	}
+	return out, sc.Err()
+}
+
+// ParseTime reads an RFC 3339 timestamp or a YYYY-MM-DD date (midnight
+// UTC). An empty string gives the zero time.
+func ParseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(dayLayout, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: want RFC 3339 or YYYY-MM-DD", s)
+}
+
+// ParseUntil reads an upper bound as ParseTime does, except that a
+// YYYY-MM-DD date covers the whole day: it gives the last instant before
+// the next midnight UTC.
+func ParseUntil(s string) (time.Time, error) {
+	t, err := ParseTime(s)
+	if err != nil || t.IsZero() {
+		return t, err
+	}
+	if _, dateErr := time.Parse(dayLayout, s); dateErr == nil {
+		t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return t, nil
+}
@@ -0,0 +1,111 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	h "github.com/orchestra-mcp/mcp/src/helpers"
+	"github.com/orchestra-mcp/mcp/src/logger"
+	"github.com/orchestra-mcp/mcp/src/transport"
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+// Middleware records every call that reaches the chain in the audit log
+// of ws, including calls rejected by validation. Arguments are recorded as
+// masked by any transport.Redact in the chain. Install Rejections as well
+// to record calls refused before the chain. Nothing is written until the
+// workspace has been initialized.
+func Middleware(ws string) transport.Middleware {
+	return func(next transport.ToolHandler) transport.ToolHandler {
+		return func(ctx context.Context, call *transport.ToolCall) (*types.ToolResult, error) {
+			start := time.Now()
+			result, err := next(ctx, call)
+			e := EntryFor(call, result, err)
+			e.Time = start.UTC()
+			e.DurationMs = time.Since(start).Milliseconds()
+			record(ws, e)
+			return result, err
+		}
+	}
+}
+
+// Rejections records calls the server refuses before the middleware
+// chain, unknown tools and failed authorization, in the audit log of ws.
+func Rejections(ws string) transport.RejectHook {
+	return func(_ context.Context, call *transport.ToolCall, err error) {
+		e := EntryFor(call, nil, err)
+		e.Time = time.Now().UTC()
+		record(ws, e)
+	}
+}
+
+func record(ws string, e Entry) {
+	if !h.FileExists(h.ProjectsDir(ws)) {
+		return
+	}
+	if err := Append(ws, e); err != nil {
+		logger.Warning("audit", "entry not written", "tool", e.Tool, "error", err)
+	}
+}
+
+// EntryFor describes a finished call. Time and DurationMs are left to the
+// caller.
+func EntryFor(call *transport.ToolCall, result *types.ToolResult, err error) Entry {
+	e := Entry{
+		Tool:      call.Name,
+		Arguments: call.RedactedArguments(),
+		Issues:    issueIDs(call.Arguments, result),
+	}
+	if call.Principal != nil {
+		e.Caller = call.Principal.Name
+	}
+	if sess := call.Session; sess != nil {
+		e.SessionID = sess.ID
+		if e.Caller == "" {
+			e.Caller = sess.ClientInfo().Name
+		}
+	}
+	switch {
+	case err != nil:
+		e.ErrorCode = transport.ErrorCode(err)
+		e.Error = err.Error()
+	case result != nil && result.IsError && len(result.Content) > 0:
+		e.Error = result.Content[0].Text
+	}
+	return e
+}
+
+// issueKeys are the arguments that name epics, stories, tasks and other
+// issues.
+var issueKeys = []string{"epic_id", "story_id", "task_id", "issue_id"}
+
+// issueIDs lists the issues a call names in its arguments or, for tools
+// that create one, returns as the "id" of its structured result.
+func issueIDs(args map[string]any, result *types.ToolResult) []string {
+	var ids []string
+	add := func(id string) {
+		if id == "" {
+			return
+		}
+		for _, seen := range ids {
+			if strings.EqualFold(seen, id) {
+				return
+			}
+		}
+		ids = append(ids, id)
+	}
+	for _, key := range issueKeys {
+		add(h.GetString(args, key))
+	}
+	if result != nil && result.StructuredContent != nil && !result.IsError {
+		var out struct {
+			ID string `json:"id"`
+		}
+		if data, err := json.Marshal(result.StructuredContent); err == nil && json.Unmarshal(data, &out) == nil {
+			add(out.ID)
+		}
+	}
+	return ids
+}
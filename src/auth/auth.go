@@ -0,0 +1,192 @@
+// Package auth authenticates API tokens and authorizes tool calls by scope.
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+
+	t "github.com/orchestra-mcp/mcp/src/types"
+)
+
+// Scope grants access to a group of tools. Scopes are ordered: admin
+// includes write, and write includes read.
+type Scope string
+
+const (
+	ScopeRead  Scope = "read"  // read-only tools, resources and prompts
+	ScopeWrite Scope = "write" // tools that add or update data
+	ScopeAdmin Scope = "admin" // destructive tools and unannotated external tools
+)
+
+var scopeRank = map[Scope]int{ScopeRead: 1, ScopeWrite: 2, ScopeAdmin: 3}
+
+// ParseScope validates a configured scope name.
+func ParseScope(s string) (Scope, error) {
+	if _, ok := scopeRank[Scope(s)]; !ok {
+		return "", fmt.Errorf("unknown scope %q (want read, write or admin)", s)
+	}
+	return Scope(s), nil
+}
+
+// JSON-RPC error codes for auth failures, in the implementation-defined
+// server error range.
+const (
+	CodeUnauthorized = -32001 // missing or invalid credentials
+	CodeForbidden    = -32003 // authenticated, but the scope is insufficient
+)
+
+var (
+	// ErrUnauthorized is returned for a missing or unknown token.
+	ErrUnauthorized = errors.New("unauthorized: missing or invalid token")
+	// ErrForbidden wraps every scope failure.
+	ErrForbidden = errors.New("forbidden")
+)
+
+// Principal is the identity behind an authenticated token.
+type Principal struct {
+	Name   string
+	Scopes []Scope
+}
+
+// Local is the principal of trusted in-process callers, such as a host
+// application calling tools through the plugin. It holds every scope.
+var Local = &Principal{Name: "local", Scopes: []Scope{ScopeAdmin}}
+
+// Has reports whether p holds s or a scope that includes it.
+func (p *Principal) Has(s Scope) bool {
+	if p == nil {
+		return false
+	}
+	for _, own := range p.Scopes {
+		if scopeRank[own] >= scopeRank[s] {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultToolScopes are the scopes of tools whose annotations understate
+// what they expose. Configured tool scopes override them.
+var DefaultToolScopes = map[string]Scope{
+	// Read-only, but shows every caller's arguments, sessions and errors.
+	"query_audit_log": ScopeAdmin,
+}
+
+// Token is a configured API token.
+type Token struct {
+	Name   string
+	Secret string
+	Scopes []Scope
+}
+
+// Authenticator checks tokens and tool scopes. A nil or empty
+// Authenticator allows everything, so auth stays opt-in.
+type Authenticator struct {
+	tokens     map[[sha256.Size]byte]*Principal
+	toolScopes map[string]Scope
+}
+
+// New builds an Authenticator. toolScopes overrides the scope derived from
+// a tool's annotations.
+func New(tokens []Token, toolScopes map[string]Scope) (*Authenticator, error) {
+	a := &Authenticator{tokens: map[[sha256.Size]byte]*Principal{}, toolScopes: toolScopes}
+	for _, tok := range tokens {
+		if tok.Secret == "" {
+			return nil, fmt.Errorf("token %q has no secret", tok.Name)
+		}
+		if len(tok.Scopes) == 0 {
+			return nil, fmt.Errorf("token %q has no scopes", tok.Name)
+		}
+		// Keyed by hash so lookups do not leak secrets through timing.
+		a.tokens[sha256.Sum256([]byte(tok.Secret))] = &Principal{Name: tok.Name, Scopes: tok.Scopes}
+	}
+	return a, nil
+}
+
+// Enabled reports whether any token is configured.
+func (a *Authenticator) Enabled() bool {
+	return a != nil && len(a.tokens) > 0
+}
+
+// Authenticate resolves a secret to its principal.
+func (a *Authenticator) Authenticate(secret string) (*Principal, error) {
+	if secret == "" {
+		return nil, ErrUnauthorized
+	}
+	sum := sha256.Sum256([]byte(secret))
+	for key, p := range a.tokens {
+		if subtle.ConstantTimeCompare(key[:], sum[:]) == 1 {
+			return p, nil
+		}
+	}
+	return nil, ErrUnauthorized
+}
+
+// Credential extracts the token from an Authorization header
+// ("Bearer <token>") or, failing that, an X-API-Key header value.
+func Credential(authorization, apiKey string) string {
+	if scheme, token, ok := strings.Cut(authorization, " "); ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+	return strings.TrimSpace(apiKey)
+}
+
+// ScopeFor returns the scope needed to call a tool: the configured
+// override, else its DefaultToolScopes entry, else read for read-only
+// tools, admin for destructive or unannotated tools, and write for the rest.
+func (a *Authenticator) ScopeFor(def t.ToolDefinition) Scope {
+	if a != nil {
+		if s, ok := a.toolScopes[def.Name]; ok {
+			return s
+		}
+	}
+	if s, ok := DefaultToolScopes[def.Name]; ok {
+		return s
+	}
+	ann := def.Annotations
+	switch {
+	case ann == nil || ann.ReadOnlyHint == nil:
+		return ScopeAdmin
+	case *ann.ReadOnlyHint:
+		return ScopeRead
+	case ann.DestructiveHint == nil || *ann.DestructiveHint:
+		return ScopeAdmin // the MCP default for destructiveHint is true
+	}
+	return ScopeWrite
+}
+
+// Require checks that p holds scope s.
+func (a *Authenticator) Require(p *Principal, s Scope) error {
+	if !a.Enabled() {
+		return nil
+	}
+	if p == nil {
+		return ErrUnauthorized
+	}
+	if !p.Has(s) {
+		return fmt.Errorf("%w: requires scope %s", ErrForbidden, s)
+	}
+	return nil
+}
+
+// AuthorizeTool checks that p may call the tool.
+func (a *Authenticator) AuthorizeTool(p *Principal, def t.ToolDefinition) error {
+	if err := a.Require(p, a.ScopeFor(def)); err != nil {
+		if errors.Is(err, ErrForbidden) {
+			return fmt.Errorf("%w: tool %s requires scope %s", ErrForbidden, def.Name, a.ScopeFor(def))
+		}
+		return err
+	}
+	return nil
+}
+
+// Code maps an auth error to its JSON-RPC error code.
+func Code(err error) int {
+	if errors.Is(err, ErrForbidden) {
+		return CodeForbidden
+	}
+	return CodeUnauthorized
+}
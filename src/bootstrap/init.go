@@ -54,13 +54,13 @@ func Run(workspaceRoot string) error {
 	claudeDir := filepath.Join(abs, ".claude")
 
 	// Install all bundled skills
-	skillCount, err := installEmbedDir(bundledSkills, "resources/skills", filepath.Join(claudeDir, "skills"))
+	skillCount, err := installEmbedDir(bundledSkills, "resources/skills", filepath.Join(claudeDir, "skills"), nil)
 	if err != nil {
 		return fmt.Errorf("install skills: %w", err)
 	}
 
 	// Install all bundled agents
-	agentCount, err := installEmbedDir(bundledAgents, "resources/agents", filepath.Join(claudeDir, "agents"))
+	agentCount, err := installEmbedDir(bundledAgents, "resources/agents", filepath.Join(claudeDir, "agents"), nil)
 	if err != nil {
 		return fmt.Errorf("install agents: %w", err)
 	}
@@ -93,12 +93,22 @@ func Run(workspaceRoot string) error {
 
 // InstallSkills installs bundled skills to the target directory.
 func InstallSkills(target string) (int, error) {
-	return installEmbedDir(bundledSkills, "resources/skills", target)
+	return installEmbedDir(bundledSkills, "resources/skills", target, nil)
+}
+
+// InstallSkillsProgress is InstallSkills with a callback after each file.
+func InstallSkillsProgress(target string, progress func(done, total int)) (int, error) {
+	return installEmbedDir(bundledSkills, "resources/skills", target, progress)
 }
 
 // InstallAgents installs bundled agents to the target directory.
 func InstallAgents(target string) (int, error) {
-	return installEmbedDir(bundledAgents, "resources/agents", target)
+	return installEmbedDir(bundledAgents, "resources/agents", target, nil)
+}
+
+// InstallAgentsProgress is InstallAgents with a callback after each file.
+func InstallAgentsProgress(target string, progress func(done, total int)) (int, error) {
+	return installEmbedDir(bundledAgents, "resources/agents", target, progress)
 }
 
 // InstallDocs installs bundled docs (CLAUDE.md, AGENTS.md, CONTEXT.md) to the workspace root.
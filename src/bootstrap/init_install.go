@@ -9,8 +9,17 @@ import (
 )
 
 // installEmbedDir walks an embed.FS and copies all files to the target directory.
-func installEmbedDir(fsys embed.FS, root, target string) (int, error) {
-	count := 0
+// If progress is non-nil it is called after each file with the running and total counts.
+func installEmbedDir(fsys embed.FS, root, target string, progress func(done, total int)) (int, error) {
+	count, total := 0, 0
+	if progress != nil {
+		_ = fs.WalkDir(fsys, root, func(_ string, d fs.DirEntry, err error) error {
+			if err == nil && !d.IsDir() {
+				total++
+			}
+			return err
+		})
+	}
 	err := fs.WalkDir(fsys, root, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
@@ -24,8 +33,14 @@ func installEmbedDir(fsys embed.FS, root, target string) (int, error) {
 		if err != nil {
 			return err
 		}
+		if err := os.WriteFile(dest, data, 0o644); err != nil {
+			return err
+		}
 		count++
-		return os.WriteFile(dest, data, 0o644)
+		if progress != nil {
+			progress(count, total)
+		}
+		return nil
 	})
 	return count, err
 }
@@ -0,0 +1,332 @@
+// Package client is an MCP client for upstream servers reached over stdio
+// or the Streamable HTTP transport.
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/orchestra-mcp/mcp/src/transport"
+	"github.com/orchestra-mcp/mcp/src/types"
+	"github.com/orchestra-mcp/mcp/src/version"
+)
+
+// ErrClosed is returned by calls made after the connection ended.
+var ErrClosed = errors.New("mcp client: connection closed")
+
+// Error is a JSON-RPC error returned by the upstream server.
+type Error struct {
+	Code    int
+	Message string
+}
+
+func (e *Error) Error() string { return e.Message }
+
+// JSONRPCCode lets the transport relay the upstream code unchanged.
+func (e *Error) JSONRPCCode() int { return e.Code }
+
+// NotificationHandler receives server notifications such as
+// notifications/tools/list_changed.
+type NotificationHandler func(method string, params json.RawMessage)
+
+// conn moves raw JSON-RPC messages. Incoming messages, including replies
+// to send, go to the deliver func given to start; fail ends the client.
+type conn interface {
+	start(deliver func([]byte), fail func(error))
+	send(ctx context.Context, msg []byte) error
+	close() error
+}
+
+// message is any JSON-RPC message read from the server.
+type message struct {
+	ID     json.RawMessage     `json:"id,omitempty"`
+	Method string              `json:"method,omitempty"`
+	Params json.RawMessage     `json:"params,omitempty"`
+	Result json.RawMessage     `json:"result,omitempty"`
+	Error  *types.JSONRPCError `json:"error,omitempty"`
+}
+
+// Client is a connection to one MCP server. Requests may run concurrently;
+// replies are matched by ID.
+type Client struct {
+	conn    conn
+	nextID  atomic.Int64
+	mu      sync.Mutex
+	pending map[string]chan *message
+	notify  NotificationHandler
+	info    types.InitializeResult
+	done    chan struct{}
+	err     error
+	once    sync.Once
+}
+
+func newClient(c conn) *Client {
+	cl := &Client{conn: c, pending: make(map[string]chan *message), done: make(chan struct{})}
+	c.start(cl.dispatch, cl.fail)
+	return cl
+}
+
+// OnNotification sets the handler for server notifications. Set it before
+// Initialize so no list_changed notification is missed.
+func (c *Client) OnNotification(fn NotificationHandler) {
+	c.mu.Lock()
+	c.notify = fn
+	c.mu.Unlock()
+}
+
+// Done is closed when the connection ends; Err then reports why.
+func (c *Client) Done() <-chan struct{} { return c.done }
+
+// Err returns the error that ended the connection, or nil while it is open.
+func (c *Client) Err() error {
+	select {
+	case <-c.done:
+		return c.err
+	default:
+		return nil
+	}
+}
+
+// Close ends the connection and fails pending requests.
+func (c *Client) Close() error {
+	err := c.conn.close()
+	c.fail(ErrClosed)
+	return err
+}
+
+// Server returns the initialize result, valid after Initialize.
+func (c *Client) Server() types.InitializeResult { return c.info }
+
+// Initialize performs the MCP handshake and sends notifications/initialized.
+func (c *Client) Initialize(ctx context.Context) (*types.InitializeResult, error) {
+	params := types.InitializeParams{
+		ProtocolVersion: transport.SupportedVersions[0],
+		Capabilities:    types.ClientCaps{Roots: &types.RootsCap{}},
+		ClientInfo:      types.ClientInfo{Name: "orchestra-mcp", Version: version.Version},
+	}
+	if err := c.call(ctx, "initialize", params, &c.info); err != nil {
+		return nil, err
+	}
+	if err := c.Notify(ctx, "notifications/initialized", nil); err != nil {
+		return nil, err
+	}
+	return &c.info, nil
+}
+
+// Ping checks that the server is responsive.
+func (c *Client) Ping(ctx context.Context) error {
+	return c.call(ctx, "ping", nil, nil)
+}
+
+// ListTools returns every tool, following pagination cursors.
+func (c *Client) ListTools(ctx context.Context) ([]types.ToolDefinition, error) {
+	var out []types.ToolDefinition
+	err := c.paginate(ctx, "tools/list", func(raw json.RawMessage) error {
+		var page types.ListToolsResult
+		err := json.Unmarshal(raw, &page)
+		out = append(out, page.Tools...)
+		return err
+	})
+	return out, err
+}
+
+// CallTool runs a tool on the server. A tool that fails reports it in the
+// result's IsError; the error return is for protocol failures.
+func (c *Client) CallTool(ctx context.Context, name string, args map[string]any) (*types.ToolResult, error) {
+	var result types.ToolResult
+	if err := c.call(ctx, "tools/call", types.CallToolParams{Name: name, Arguments: args}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListResources returns every concrete resource.
+func (c *Client) ListResources(ctx context.Context) ([]types.ResourceDefinition, error) {
+	var out []types.ResourceDefinition
+	err := c.paginate(ctx, "resources/list", func(raw json.RawMessage) error {
+		var page types.ListResourcesResult
+		err := json.Unmarshal(raw, &page)
+		out = append(out, page.Resources...)
+		return err
+	})
+	return out, err
+}
+
+// ListResourceTemplates returns every resource template.
+func (c *Client) ListResourceTemplates(ctx context.Context) ([]types.ResourceTemplate, error) {
+	var out []types.ResourceTemplate
+	err := c.paginate(ctx, "resources/templates/list", func(raw json.RawMessage) error {
+		var page types.ListResourceTemplatesResult
+		err := json.Unmarshal(raw, &page)
+		out = append(out, page.ResourceTemplates...)
+		return err
+	})
+	return out, err
+}
+
+// ReadResource reads one resource by URI.
+func (c *Client) ReadResource(ctx context.Context, uri string) ([]types.ResourceContent, error) {
+	var result types.ReadResourceResult
+	if err := c.call(ctx, "resources/read", types.ReadResourceParams{URI: uri}, &result); err != nil {
+		return nil, err
+	}
+	return result.Contents, nil
+}
+
+// ListPrompts returns every prompt.
+func (c *Client) ListPrompts(ctx context.Context) ([]types.PromptDefinition, error) {
+	var out []types.PromptDefinition
+	err := c.paginate(ctx, "prompts/list", func(raw json.RawMessage) error {
+		var page types.ListPromptsResult
+		err := json.Unmarshal(raw, &page)
+		out = append(out, page.Prompts...)
+		return err
+	})
+	return out, err
+}
+
+// GetPrompt renders a prompt with the given arguments.
+func (c *Client) GetPrompt(ctx context.Context, name string, args map[string]string) (*types.GetPromptResult, error) {
+	var result types.GetPromptResult
+	if err := c.call(ctx, "prompts/get", types.GetPromptParams{Name: name, Arguments: args}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Notify sends a notification to the server.
+func (c *Client) Notify(ctx context.Context, method string, params any) error {
+	data, err := json.Marshal(types.JSONRPCNotification{JSONRPC: "2.0", Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+	return c.conn.send(ctx, data)
+}
+
+// paginate calls a list method until the server stops returning a cursor.
+func (c *Client) paginate(ctx context.Context, method string, page func(json.RawMessage) error) error {
+	var cursor string
+	for {
+		var params any
+		if cursor != "" {
+			params = map[string]string{"cursor": cursor}
+		}
+		var raw json.RawMessage
+		if err := c.call(ctx, method, params, &raw); err != nil {
+			return err
+		}
+		if err := page(raw); err != nil {
+			return fmt.Errorf("%s: %w", method, err)
+		}
+		var next struct {
+			NextCursor string `json:"nextCursor"`
+		}
+		_ = json.Unmarshal(raw, &next)
+		if next.NextCursor == "" {
+			return nil
+		}
+		cursor = next.NextCursor
+	}
+}
+
+// call sends a request and decodes its result into out, which may be nil.
+// When ctx ends first the server is told to cancel the request.
+func (c *Client) call(ctx context.Context, method string, params, out any) error {
+	id := c.nextID.Add(1)
+	key := strconv.FormatInt(id, 10)
+	reply := make(chan *message, 1)
+	c.mu.Lock()
+	c.pending[key] = reply
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, key)
+		c.mu.Unlock()
+	}()
+
+	data, err := json.Marshal(types.JSONRPCOutgoingRequest{JSONRPC: "2.0", ID: id, Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+	if err := c.conn.send(ctx, data); err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	select {
+	case msg := <-reply:
+		if msg.Error != nil {
+			return &Error{Code: msg.Error.Code, Message: msg.Error.Message}
+		}
+		if out == nil || len(msg.Result) == 0 {
+			return nil
+		}
+		if err := json.Unmarshal(msg.Result, out); err != nil {
+			return fmt.Errorf("%s: decode result: %w", method, err)
+		}
+		return nil
+	case <-ctx.Done():
+		_ = c.Notify(context.Background(), "notifications/cancelled", types.CancelledParams{RequestID: id, Reason: ctx.Err().Error()})
+		return ctx.Err()
+	case <-c.done:
+		return c.err
+	}
+}
+
+// dispatch routes one incoming message: replies wake their request,
+// notifications go to the handler and server requests are answered.
+func (c *Client) dispatch(data []byte) {
+	var msg message
+	if json.Unmarshal(data, &msg) != nil {
+		return
+	}
+	hasID := len(msg.ID) > 0 && string(msg.ID) != "null"
+	switch {
+	case msg.Method == "" && hasID:
+		c.mu.Lock()
+		reply, ok := c.pending[string(msg.ID)]
+		c.mu.Unlock()
+		if ok {
+			reply <- &msg
+		}
+	case msg.Method == "":
+		// An error reply the server could not tie to a request.
+	case !hasID:
+		c.mu.Lock()
+		fn := c.notify
+		c.mu.Unlock()
+		if fn != nil {
+			fn(msg.Method, msg.Params)
+		}
+	default:
+		go c.answer(&msg)
+	}
+}
+
+// answer replies to a server-initiated request. The client offers no
+// sampling or elicitation and reports no roots.
+func (c *Client) answer(req *message) {
+	resp := types.JSONRPCResponse{JSONRPC: "2.0", ID: req.ID}
+	switch req.Method {
+	case "ping":
+		resp.Result = map[string]any{}
+	case "roots/list":
+		resp.Result = types.ListRootsResult{Roots: []types.Root{}}
+	default:
+		resp.Error = &types.JSONRPCError{Code: -32601, Message: "method not found: " + req.Method}
+	}
+	if data, err := json.Marshal(resp); err == nil {
+		_ = c.conn.send(context.Background(), data)
+	}
+}
+
+// fail ends the client once, waking every pending request.
+func (c *Client) fail(err error) {
+	c.once.Do(func() {
+		c.err = err
+		close(c.done)
+	})
+}
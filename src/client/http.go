@@ -0,0 +1,216 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/orchestra-mcp/mcp/src/transport"
+	"github.com/orchestra-mcp/mcp/src/types"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// reconnectDelay is the pause before reopening a dropped GET stream.
+const reconnectDelay = time.Second
+
+// NewHTTP connects to a Streamable HTTP endpoint such as
+// "http://localhost:8080/mcp". header is added to every request, e.g. an
+// Authorization token; it may be nil.
+func NewHTTP(url string, header http.Header) *Client {
+	ctx, cancel := context.WithCancel(context.Background())
+	return newClient(&httpConn{url: url, header: header, http: &http.Client{}, ctx: ctx, cancel: cancel})
+}
+
+// httpConn POSTs each message. Replies come back as a JSON body or an SSE
+// stream on the POST; server notifications arrive on a GET stream opened
+// once the server assigns a session.
+type httpConn struct {
+	url     string
+	header  http.Header
+	http    *http.Client
+	deliver func([]byte)
+	fail    func(error)
+	ctx     context.Context // ends the GET stream
+	cancel  context.CancelFunc
+	mu      sync.Mutex
+	session string
+}
+
+func (c *httpConn) start(deliver func([]byte), fail func(error)) {
+	c.deliver, c.fail = deliver, fail
+}
+
+func (c *httpConn) newRequest(ctx context.Context, method string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.url, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, vs := range c.header {
+		req.Header[k] = vs
+	}
+	c.mu.Lock()
+	if c.session != "" {
+		req.Header.Set(transport.SessionHeader, c.session)
+	}
+	c.mu.Unlock()
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+	return req, nil
+}
+
+func (c *httpConn) send(ctx context.Context, msg []byte) error {
+	req, err := c.newRequest(ctx, http.MethodPost, bytes.NewReader(msg))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if id := resp.Header.Get(transport.SessionHeader); id != "" {
+		c.mu.Lock()
+		first := c.session == ""
+		c.session = id
+		c.mu.Unlock()
+		if first {
+			go c.listen()
+		}
+	}
+
+	switch {
+	case resp.StatusCode >= 400:
+		return httpError(resp)
+	case resp.StatusCode == http.StatusAccepted:
+		return nil
+	case isEventStream(resp):
+		_, err := readEvents(resp.Body, c.deliver)
+		return err
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if body = bytes.TrimSpace(body); len(body) > 0 {
+		c.deliver(body)
+	}
+	return nil
+}
+
+// listen keeps the GET stream open, resuming after drops with
+// Last-Event-ID. A 404 means the server dropped the session, which ends
+// the client; a 405 means the server has no GET stream.
+func (c *httpConn) listen() {
+	var lastID string
+	for c.ctx.Err() == nil {
+		req, err := c.newRequest(c.ctx, http.MethodGet, nil)
+		if err != nil {
+			c.fail(err)
+			return
+		}
+		req.Header.Set("Accept", "text/event-stream")
+		if lastID != "" {
+			req.Header.Set("Last-Event-ID", lastID)
+		}
+		resp, err := c.http.Do(req)
+		if err == nil {
+			switch resp.StatusCode {
+			case http.StatusOK:
+				if id, _ := readEvents(resp.Body, c.deliver); id != "" {
+					lastID = id
+				}
+			case http.StatusNotFound:
+				resp.Body.Close()
+				c.fail(errors.New("mcp client: session expired"))
+				return
+			case http.StatusMethodNotAllowed:
+				resp.Body.Close()
+				return
+			}
+			resp.Body.Close()
+		}
+		select {
+		case <-c.ctx.Done():
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+// close ends the GET stream and deletes the session on the server.
+func (c *httpConn) close() error {
+	c.cancel()
+	c.mu.Lock()
+	session := c.session
+	c.mu.Unlock()
+	if session == "" {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), stopTimeout)
+	defer cancel()
+	req, err := c.newRequest(ctx, http.MethodDelete, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func isEventStream(resp *http.Response) bool {
+	mt, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	return mt == "text/event-stream"
+}
+
+// readEvents passes the data of each SSE event to deliver until r ends,
+// returning the last event ID seen.
+func readEvents(r io.Reader, deliver func([]byte)) (string, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	var lastID string
+	var data bytes.Buffer
+	for sc.Scan() {
+		line := sc.Text()
+		switch {
+		case line == "":
+			if data.Len() > 0 {
+				deliver(bytes.Clone(data.Bytes()))
+				data.Reset()
+			}
+		case strings.HasPrefix(line, "id:"):
+			lastID = strings.TrimSpace(line[len("id:"):])
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(line[len("data:"):], " "))
+		}
+	}
+	return lastID, sc.Err()
+}
+
+// httpError describes a failed POST, using the JSON-RPC error in the body
+// when there is one.
+func httpError(resp *http.Response) error {
+	var rpc struct {
+		Error *types.JSONRPCError `json:"error"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if json.Unmarshal(data, &rpc) == nil && rpc.Error != nil {
+		return &Error{Code: rpc.Error.Code, Message: fmt.Sprintf("%s: %s", resp.Status, rpc.Error.Message)}
+	}
+	return fmt.Errorf("mcp client: %s", resp.Status)
+}
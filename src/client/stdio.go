@@ -0,0 +1,101 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+const (
+	maxLineSize = 10 * 1024 * 1024 // matches the server's stdio limit
+	stopTimeout = 2 * time.Second
+)
+
+// NewStream speaks JSON Lines over r and w, e.g. the pipes of a process
+// started elsewhere. Closing the client closes w.
+func NewStream(r io.Reader, w io.WriteCloser) *Client {
+	return newClient(&streamConn{r: r, w: w})
+}
+
+// NewStdio starts command with args and talks to it over its stdin and
+// stdout. env entries ("KEY=value") are added to the current environment;
+// the server's stderr is passed through. Close stops the process.
+func NewStdio(command string, args []string, env []string) (*Client, error) {
+	cmd := exec.Command(command, args...)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start %s: %w", command, err)
+	}
+	return newClient(&streamConn{r: stdout, w: stdin, cmd: cmd, exited: make(chan struct{})}), nil
+}
+
+// streamConn writes one message per line and reads replies on its own
+// goroutine. With cmd set it owns the child process.
+type streamConn struct {
+	r      io.Reader
+	w      io.WriteCloser
+	mu     sync.Mutex
+	cmd    *exec.Cmd
+	exited chan struct{}
+}
+
+func (c *streamConn) start(deliver func([]byte), fail func(error)) {
+	go func() {
+		sc := bufio.NewScanner(c.r)
+		sc.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+		for sc.Scan() {
+			if line := bytes.TrimSpace(sc.Bytes()); len(line) > 0 {
+				deliver(bytes.Clone(line))
+			}
+		}
+		err := sc.Err()
+		if c.cmd != nil {
+			if werr := c.cmd.Wait(); err == nil && werr != nil {
+				err = fmt.Errorf("server exited: %w", werr)
+			}
+			close(c.exited)
+		}
+		if err == nil {
+			err = ErrClosed
+		}
+		fail(err)
+	}()
+}
+
+func (c *streamConn) send(_ context.Context, msg []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, err := c.w.Write(append(msg, '\n'))
+	return err
+}
+
+// close shuts stdin, which asks a stdio server to exit, and kills the
+// process if it is still running after stopTimeout.
+func (c *streamConn) close() error {
+	err := c.w.Close()
+	if c.cmd == nil {
+		return err
+	}
+	select {
+	case <-c.exited:
+	case <-time.After(stopTimeout):
+		_ = c.cmd.Process.Kill()
+		<-c.exited
+	}
+	return err
+}
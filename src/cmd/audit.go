@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/orchestra-mcp/mcp/src/audit"
+)
+
+// runAudit prints the audit log entries selected by the audit flags in
+// args, as a table or, with --json, as JSON lines.
+func runAudit(ws string, args []string) {
+	var f audit.Filter
+	var asJSON bool
+	for i := 0; i < len(args); i++ {
+		value := func() string {
+			if i+1 < len(args) {
+				i++
+				return args[i]
+			}
+			return ""
+		}
+		var err error
+		switch args[i] {
+		case "--tool":
+			f.Tool = value()
+		case "--issue":
+			f.Issue = value()
+		case "--session":
+			f.Session = value()
+		case "--since":
+			f.Since, err = audit.ParseTime(value())
+		case "--until":
+			f.Until, err = audit.ParseUntil(value())
+		case "--limit":
+			f.Limit, err = strconv.Atoi(value())
+		case "--json":
+			asJSON = true
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
+	}
+
+	entries, err := audit.Query(ws, f)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		for _, e := range entries {
+			_ = enc.Encode(e)
+		}
+		return
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tTOOL\tCALLER\tSESSION\tDURATION\tRESULT\tISSUES")
+	for _, e := range entries {
+		result := "ok"
+		if e.Error != "" {
+			result = e.Error
+			if e.ErrorCode != 0 {
+				result = fmt.Sprintf("%d %s", e.ErrorCode, e.Error)
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			e.Time.Format(time.RFC3339), e.Tool, dash(e.Caller), dash(e.SessionID),
+			time.Duration(e.DurationMs)*time.Millisecond, result, dash(strings.Join(e.Issues, ",")))
+	}
+	tw.Flush()
+}
+
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/orchestra-mcp/mcp/src/hooks"
+	"github.com/orchestra-mcp/mcp/src/logger"
+)
+
+// runHook stores the hook event on stdin. It always exits 0 so a failing
+// hook never blocks Claude Code; problems are reported on stderr.
+func runHook(ws string) {
+	if _, err := hooks.Ingest(ws, os.Stdin); err != nil {
+		fmt.Fprintf(os.Stderr, "orchestra-mcp hook: %s\n", err)
+	}
+}
+
+// runHookDaemon serves the hook socket in the foreground until interrupted,
+// for setups where no MCP server is running to host it.
+func runHookDaemon(ws string) {
+	logger.Default().AddSink(logger.NewWriterSink(os.Stderr, logger.LevelInfo))
+	d, err := hooks.Listen(ws)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	logger.Info("hooks", "daemon listening", "socket", hooks.SocketPath(ws))
+	if err := d.Serve(ctx); err != nil {
+		logger.Error("hooks", "daemon stopped", "error", err)
+	}
+}
+
+// startHookDaemon hosts the hook socket inside the MCP server. If another
+// server for the workspace already hosts it, hooks are sent there instead.
+func startHookDaemon(ws string) func() {
+	d, err := hooks.Listen(ws)
+	if err != nil {
+		if !errors.Is(err, hooks.ErrDaemonRunning) {
+			logger.Warning("hooks", "daemon not started, hooks write directly", "error", err)
+		}
+		return nil
+	}
+	go func() {
+		if err := d.Serve(context.Background()); err != nil {
+			logger.Error("hooks", "daemon stopped", "error", err)
+		}
+	}()
+	logger.Debug("hooks", "daemon listening", "socket", hooks.SocketPath(ws))
+	return func() { d.Close() }
+}
@@ -1,27 +1,46 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"net"
+	"net/http"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/orchestra-mcp/discord/src/notifier"
+	"github.com/orchestra-mcp/mcp/config"
+	"github.com/orchestra-mcp/mcp/src/audit"
+	"github.com/orchestra-mcp/mcp/src/auth"
 	"github.com/orchestra-mcp/mcp/src/bootstrap"
 	"github.com/orchestra-mcp/mcp/src/engine"
 	h "github.com/orchestra-mcp/mcp/src/helpers"
+	"github.com/orchestra-mcp/mcp/src/logger"
+	"github.com/orchestra-mcp/mcp/src/metrics"
+	"github.com/orchestra-mcp/mcp/src/proxy"
 	"github.com/orchestra-mcp/mcp/src/tools"
+	"github.com/orchestra-mcp/mcp/src/toolsets"
 	"github.com/orchestra-mcp/mcp/src/toon"
+	"github.com/orchestra-mcp/mcp/src/tracing"
 	"github.com/orchestra-mcp/mcp/src/transport"
 	t "github.com/orchestra-mcp/mcp/src/types"
 	"github.com/orchestra-mcp/mcp/src/version"
 	"github.com/orchestra-mcp/mcp/src/workflow"
 )
 
-const cmdInit = "init"
+const (
+	cmdInit  = "init"
+	cmdServe = "serve"
+	cmdHook  = "hook"
+	cmdAudit = "audit"
+)
 
 func main() {
 	ws := "."
-	var cmd string
+	var cmd, httpAddr, metricsAddr, otlpEndpoint, traceFile, proxyConfig, profile, authConfig string
+	var toolsetFlag, rootDirs, origins []string
+	var wsSet, daemon bool
 
 	args := os.Args[1:]
 	for i := 0; i < len(args); i++ {
@@ -36,13 +55,87 @@ func main() {
 		case "--workspace":
 			if i+1 < len(args) {
 				ws = args[i+1]
+				wsSet = true
+				i++
+			}
+		case "--http":
+			if i+1 < len(args) {
+				httpAddr = args[i+1]
+				i++
+			}
+		case "--metrics-addr":
+			if i+1 < len(args) {
+				metricsAddr = args[i+1]
+				i++
+			}
+		case "--otlp-endpoint":
+			if i+1 < len(args) {
+				otlpEndpoint = args[i+1]
+				i++
+			}
+		case "--trace-file":
+			if i+1 < len(args) {
+				traceFile = args[i+1]
+				i++
+			}
+		case "--proxy":
+			if i+1 < len(args) {
+				proxyConfig = args[i+1]
+				i++
+			}
+		case "--toolsets":
+			if i+1 < len(args) {
+				toolsetFlag = toolsets.ParseList(args[i+1])
+				i++
+			}
+		case "--root-dir":
+			if i+1 < len(args) {
+				rootDirs = append(rootDirs, args[i+1])
+				i++
+			}
+		case "--profile":
+			if i+1 < len(args) {
+				profile = args[i+1]
 				i++
 			}
-		case cmdInit:
-			cmd = cmdInit
+		case "--auth-config":
+			if i+1 < len(args) {
+				authConfig = args[i+1]
+				i++
+			}
+		case "--allow-origin":
+			if i+1 < len(args) {
+				origins = append(origins, args[i+1])
+				i++
+			}
+		case "--daemon":
+			daemon = true
+		case "--tool", "--issue", "--session", "--since", "--until", "--limit":
+			i++ // audit flags; runAudit reads their values
+		case cmdInit, cmdServe, cmdHook, cmdAudit:
+			if cmd == "" { // later ones are arguments
+				cmd = args[i]
+			}
 		}
 	}
 
+	if cmd == cmdHook {
+		if dir := os.Getenv("CLAUDE_PROJECT_DIR"); !wsSet && dir != "" {
+			ws = dir
+		}
+		if daemon {
+			runHookDaemon(ws)
+		} else {
+			runHook(ws)
+		}
+		return
+	}
+
+	if cmd == cmdAudit {
+		runAudit(ws, args)
+		return
+	}
+
 	if cmd == cmdInit {
 		if err := bootstrap.Run(ws); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
@@ -51,12 +144,39 @@ func main() {
 		return
 	}
 
+	sel, err := selectTools(ws, profile, toolsetFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+	authn, err := loadAuthenticator(authConfig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+
+	if closeLog := setupLogging(ws); closeLog != nil {
+		defer closeLog()
+	}
+	stopTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		OTLPEndpoint: otlpEndpoint, File: traceFile, ServiceVersion: version.Version,
+	})
+	if err != nil {
+		logger.Warning("tracing", "not started", "error", err)
+	}
+	defer stopTracing(context.Background())
+	if h.FileExists(h.ProjectsDir(ws)) {
+		if stopHooks := startHookDaemon(ws); stopHooks != nil {
+			defer stopHooks()
+		}
+	}
+
 	// Start Rust engine (non-fatal if binary missing)
 	mgr := engine.NewManager()
 	if err := mgr.Start(ws); err != nil {
-		fmt.Fprintf(os.Stderr, "[Orchestra MCP] Engine: %v (using TOON fallback)\n", err)
+		logger.Warning("engine", "not started, using TOON fallback", "error", err)
 	} else {
-		fmt.Fprintf(os.Stderr, "[Orchestra MCP] Engine: running on %s\n", mgr.Addr())
+		logger.Info("engine", "running", "addr", mgr.Addr())
 	}
 	defer mgr.Stop()
 
@@ -65,7 +185,7 @@ func main() {
 	if mgr.IsRunning() {
 		c, err := engine.Dial(mgr.Addr())
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "[Orchestra MCP] Engine dial failed: %v\n", err)
+			logger.Error("engine", "dial failed", "error", err)
 		} else {
 			client = c
 		}
@@ -74,42 +194,165 @@ func main() {
 		defer client.Close()
 	}
 	bridge := engine.NewBridge(client, ws)
+	metrics.EngineUp.SetFunc(func() float64 { return metrics.Bool(mgr.IsRunning()) }, "process")
+	metrics.EngineUp.SetFunc(func() float64 { return metrics.Bool(bridge.UsingEngine()) }, "grpc")
+	if metricsAddr != "" {
+		go serveMetrics(metricsAddr)
+	}
 
 	s := transport.New("orchestra-mcp", version.Version)
-	s.RegisterTools(tools.Project(ws))
-	s.RegisterTools(tools.Epic(ws))
-	s.RegisterTools(tools.Story(ws))
-	s.RegisterTools(tools.Task(ws))
-	s.RegisterTools(tools.Workflow(ws))
-	s.RegisterTools(tools.Prd(ws))
-	s.RegisterTools(tools.Bugfix(ws))
-	s.RegisterTools(tools.Usage(ws))
-	s.RegisterTools(tools.Readme(ws))
-	s.RegisterTools(tools.Artifacts(ws))
-	s.RegisterTools(tools.Lifecycle(ws))
-	s.RegisterTools(tools.Claude(ws))
-	s.RegisterTools(tools.Memory(ws, bridge))
+	s.SetRootDirs(rootDirs...)
+	s.SetAllowedOrigins(origins...)
+	s.SetAuthenticator(authn)
+	s.Use(audit.Middleware(ws))
+	s.OnReject(audit.Rejections(ws))
+	s.Use(transport.DefaultMiddleware()...)
+	builtin := map[string][]t.Tool{
+		"project":   tools.Rooted(ws, tools.Project),
+		"epic":      tools.Rooted(ws, tools.Epic),
+		"story":     tools.Rooted(ws, tools.Story),
+		"task":      tools.Rooted(ws, tools.Task),
+		"workflow":  tools.Rooted(ws, tools.Workflow),
+		"prd":       tools.Rooted(ws, tools.Prd),
+		"bugfix":    tools.Rooted(ws, tools.Bugfix),
+		"usage":     tools.Rooted(ws, tools.Usage),
+		"readme":    tools.Rooted(ws, tools.Readme),
+		"artifacts": tools.Rooted(ws, tools.Artifacts),
+		"lifecycle": tools.Rooted(ws, tools.Lifecycle),
+		"claude":    tools.Rooted(ws, tools.Claude),
+		"audit":     tools.Audit(ws),
+		"memory": tools.Rooted(ws, func(root string) []t.Tool {
+			if root == ws {
+				return tools.Memory(ws, bridge)
+			}
+			return tools.Memory(root, engine.NewBridge(nil, root)) // the engine indexes the launch workspace only
+		}),
+	}
+	if err := sel.ValidateTools(builtin); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+	for _, group := range toolsets.Groups {
+		s.RegisterTools(sel.Filter(group, builtin[group]))
+	}
 	s.RegisterResources(tools.Resources(ws))
 	s.RegisterPrompts(tools.Prompts(ws))
 
+	// Mount upstream MCP servers; failures are logged per upstream
+	if proxyConfig != "" {
+		if cfg, err := proxy.Load(proxyConfig); err != nil {
+			logger.Error("proxy", "config not loaded", "error", err)
+		} else {
+			px := proxy.New(s)
+			px.SetFilter(sel.Filter)
+			ctx, cancel := context.WithTimeout(context.Background(), proxyStartTimeout)
+			_ = px.Start(ctx, cfg)
+			cancel()
+			defer px.Close()
+		}
+	}
+
 	// Register Discord notifier for workflow transitions
 	if dn := notifier.New(); dn != nil {
 		workflow.RegisterListener(workflow.TransitionListenerFunc(func(e workflow.TransitionEvent) {
 			ne := enrichEvent(ws, e)
 			dn.OnTransition(ne)
 		}))
-		fmt.Fprintf(os.Stderr, "[Orchestra MCP] Discord notifier: enabled\n")
+		logger.Info("discord", "notifier enabled")
 	}
 
 	memMode := "TOON fallback"
 	if bridge.UsingEngine() {
 		memMode = fmt.Sprintf("Rust engine (gRPC on %s)", mgr.Addr())
 	}
-	fmt.Fprintf(os.Stderr, "[Orchestra MCP] Server v%s running with %d tools, %d resources, %d prompts | Memory: %s\n",
-		version.Version, len(s.GetTools()), len(s.GetResources()), len(s.GetPrompts()), memMode)
+	logger.Info("server", "running", "version", version.Version,
+		"tools", len(s.GetTools()), "resources", len(s.GetResources()), "prompts", len(s.GetPrompts()), "memory", memMode)
+
+	if cmd == cmdServe && httpAddr != "" {
+		logger.Info("http", "Streamable HTTP listening", "addr", httpAddr+transport.HTTPEndpoint)
+		if !authn.Enabled() && !loopback(httpAddr) {
+			logger.Warning("http", "listening beyond localhost without tokens; pass --auth-config", "addr", httpAddr)
+		}
+		if err := s.ListenAndServeHTTP(httpAddr); err != nil {
+			logger.Error("http", "server stopped", "error", err)
+		}
+		return
+	}
 	s.Run()
 }
 
+// serveMetrics exposes the OpenMetrics endpoint at /metrics on addr.
+func serveMetrics(addr string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Default.Handler())
+	logger.Info("metrics", "listening", "addr", addr+"/metrics")
+	srv := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	if err := srv.ListenAndServe(); err != nil {
+		logger.Error("metrics", "server stopped", "error", err)
+	}
+}
+
+// selectTools resolves the exposed tools from .orchestra/config.yaml and
+// the --profile and --toolsets flags.
+func selectTools(ws, profile string, toolsetFlag []string) (toolsets.Selection, error) {
+	cfg, err := toolsets.Load(ws)
+	if err != nil {
+		return toolsets.Selection{}, err
+	}
+	return cfg.Resolve(profile, toolsetFlag)
+}
+
+// loadAuthenticator builds the authenticator from the --auth-config file,
+// which has the layout of the plugin's auth section. Without a file auth
+// stays off.
+func loadAuthenticator(path string) (*auth.Authenticator, error) {
+	if path == "" {
+		return nil, nil
+	}
+	cfg, err := config.LoadAuth(path)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Authenticator()
+}
+
+// loopback reports whether a listen address only accepts local connections.
+func loopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	ip := net.ParseIP(host)
+	return host == "localhost" || ip != nil && ip.IsLoopback()
+}
+
+// proxyStartTimeout bounds starting and initializing the proxy upstreams.
+const proxyStartTimeout = 30 * time.Second
+
+// Log rotation limits for .projects/.logs/mcp.log.
+const (
+	logMaxSize    = 5 << 20 // 5MB
+	logMaxBackups = 3
+)
+
+// setupLogging sends info and above to stderr and, once the workspace has
+// been initialized, to a rotating JSON log in .projects/.logs/. The returned
+// func closes the log file.
+func setupLogging(ws string) func() {
+	logger.Default().AddSink(logger.NewWriterSink(os.Stderr, logger.LevelInfo))
+	if !h.FileExists(h.ProjectsDir(ws)) {
+		return nil
+	}
+	path := filepath.Join(h.ProjectsDir(ws), ".logs", "mcp.log")
+	fs, err := logger.NewFileSink(path, logger.LevelDebug, logMaxSize, logMaxBackups)
+	if err != nil {
+		logger.Warning("server", "file logging disabled", "error", err)
+		return nil
+	}
+	logger.Default().AddSink(fs)
+	return func() { fs.Close() }
+}
+
 func enrichEvent(ws string, e workflow.TransitionEvent) notifier.TransitionEvent {
 	ne := notifier.TransitionEvent{
 		Project: e.Project, EpicID: e.EpicID, StoryID: e.StoryID,
@@ -168,19 +411,65 @@ func printUsage() {
 Usage:
   orchestra-mcp [flags]
   orchestra-mcp init [--workspace <path>]
+  orchestra-mcp serve [--http <addr>] [--metrics-addr <addr>] [--workspace <path>]
+                      [--otlp-endpoint <host:port>] [--trace-file <path>]
+                      [--proxy <file>] [--profile <name>] [--toolsets <list>]
+                      [--root-dir <path>]... [--auth-config <file>]
+                      [--allow-origin <origin>]...
+  orchestra-mcp hook [--daemon] [--workspace <path>]
+  orchestra-mcp audit [--tool <name>] [--issue <id>] [--session <id>]
+                      [--since <time>] [--until <time>] [--limit <n>] [--json]
 
 Commands:
   init              Initialize MCP workspace (.mcp.json, .projects/)
+  serve             Start the MCP server (stdio, or Streamable HTTP with --http)
+  hook              Store the Claude Code hook event on stdin (via the running
+                    server's hook socket, or directly if none is running)
+  audit             Print tool calls from the audit log in .projects/.audit/
 
 Flags:
   --workspace <path>  Set workspace directory (default: ".")
+  --http <addr>       Serve Streamable HTTP on addr (e.g. "127.0.0.1:8080")
+                      instead of stdio; bind to localhost unless tokens are set
+  --auth-config <file>
+                      With --http: require the API tokens in file (the
+                      plugin's auth section: tokens, tool_scopes; JSON or
+                      YAML). The stdio client is always trusted
+  --allow-origin <origin>
+                      With --http: accept browser requests from origin
+                      besides localhost (repeatable)
+  --metrics-addr <addr>
+                      Serve OpenMetrics at http://<addr>/metrics
+  --otlp-endpoint <host:port>
+                      Export OpenTelemetry spans over OTLP/gRPC
+  --trace-file <path> Append spans as JSON lines to path
+  --proxy <file>      Mount the upstream MCP servers listed in file (the
+                      "mcpServers" layout of .mcp.json; JSON or YAML)
+  --root-dir <path>   With --http: honour client roots under path (repeatable);
+                      remote roots elsewhere are ignored
+  --profile <name>    Tool profile: admin (default), agent, readonly, or one
+                      defined in .orchestra/config.yaml
+  --toolsets <list>   Only expose these tool groups, comma-separated: project,
+                      epic, story, task, workflow, lifecycle, prd, bugfix,
+                      memory, usage, artifacts, readme, claude, audit
+  --daemon            With hook: serve the hook socket in the foreground
+  --since, --until    With audit: time range, RFC 3339 or YYYY-MM-DD; a
+                      date-only --until includes that whole day
+  --json              With audit: print entries as JSON lines
   --version, -v       Print version and exit
   --help, -h          Print this help message
 
 Examples:
   orchestra-mcp                          Start MCP server (stdio JSON-RPC)
   orchestra-mcp --workspace /my/project  Start with custom workspace
+  orchestra-mcp serve --http 127.0.0.1:8080
+                                         Share one server over HTTP at /mcp
+  orchestra-mcp --proxy proxy.json       Add upstream tools as <server>.<tool>
+  orchestra-mcp --toolsets workflow,memory
+                                         Expose only the workflow and memory tools
   orchestra-mcp init                     Initialize workspace in current dir
   orchestra-mcp init --workspace /path   Initialize workspace at path
+  orchestra-mcp hook < event.json        Record a hook event
+  orchestra-mcp audit --issue OM-3       Show every call that touched OM-3
 `)
 }
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	pb "github.com/orchestra-mcp/mcp/src/gen/memoryv1"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
@@ -15,10 +16,12 @@ type Client struct {
 	memory pb.MemoryServiceClient
 }
 
-// Dial connects to the engine at the given address.
+// Dial connects to the engine at the given address. Each call gets a
+// client span, and the trace context travels in the gRPC metadata.
 func Dial(addr string) (*Client, error) {
 	conn, err := grpc.NewClient(addr,
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
 	)
 	if err != nil {
 		return nil, err
@@ -33,13 +36,16 @@ func (c *Client) Close() {
 	}
 }
 
-func ctx() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), 5*time.Second)
+// callTimeout bounds every gRPC call; the caller's ctx may cancel it sooner.
+const callTimeout = 5 * time.Second
+
+func withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, callTimeout)
 }
 
 // StoreChunk stores a memory chunk via gRPC.
-func (c *Client) StoreChunk(project, source, sourceID, summary, content string, tags []string) (*pb.StoreChunkResponse, error) {
-	cx, cancel := ctx()
+func (c *Client) StoreChunk(ctx context.Context, project, source, sourceID, summary, content string, tags []string) (*pb.StoreChunkResponse, error) {
+	cx, cancel := withTimeout(ctx)
 	defer cancel()
 	return c.memory.StoreChunk(cx, &pb.StoreChunkRequest{
 		Project: project, Source: source, SourceId: sourceID,
@@ -48,8 +54,8 @@ func (c *Client) StoreChunk(project, source, sourceID, summary, content string,
 }
 
 // SearchMemory searches memory via gRPC.
-func (c *Client) SearchMemory(project, query string, limit int32) (*pb.SearchResponse, error) {
-	cx, cancel := ctx()
+func (c *Client) SearchMemory(ctx context.Context, project, query string, limit int32) (*pb.SearchResponse, error) {
+	cx, cancel := withTimeout(ctx)
 	defer cancel()
 	return c.memory.SearchMemory(cx, &pb.SearchRequest{
 		Project: project, Query: query, Limit: limit,
@@ -57,8 +63,8 @@ func (c *Client) SearchMemory(project, query string, limit int32) (*pb.SearchRes
 }
 
 // GetContext returns relevant context chunks via gRPC.
-func (c *Client) GetContext(project, query string, limit int32) (*pb.ContextResponse, error) {
-	cx, cancel := ctx()
+func (c *Client) GetContext(ctx context.Context, project, query string, limit int32) (*pb.ContextResponse, error) {
+	cx, cancel := withTimeout(ctx)
 	defer cancel()
 	return c.memory.GetContext(cx, &pb.ContextRequest{
 		Project: project, Query: query, Limit: limit,
@@ -66,8 +72,8 @@ func (c *Client) GetContext(project, query string, limit int32) (*pb.ContextResp
 }
 
 // StoreSession stores a session log via gRPC.
-func (c *Client) StoreSession(project, sessionID, summary string, events []*pb.SessionEvent) (*pb.StoreSessionResponse, error) {
-	cx, cancel := ctx()
+func (c *Client) StoreSession(ctx context.Context, project, sessionID, summary string, events []*pb.SessionEvent) (*pb.StoreSessionResponse, error) {
+	cx, cancel := withTimeout(ctx)
 	defer cancel()
 	return c.memory.StoreSession(cx, &pb.StoreSessionRequest{
 		Project: project, SessionId: sessionID, Summary: summary, Events: events,
@@ -75,8 +81,8 @@ func (c *Client) StoreSession(project, sessionID, summary string, events []*pb.S
 }
 
 // ListSessions returns recent sessions via gRPC.
-func (c *Client) ListSessions(project string, limit int32) (*pb.ListSessionsResponse, error) {
-	cx, cancel := ctx()
+func (c *Client) ListSessions(ctx context.Context, project string, limit int32) (*pb.ListSessionsResponse, error) {
+	cx, cancel := withTimeout(ctx)
 	defer cancel()
 	return c.memory.ListSessions(cx, &pb.ListSessionsRequest{
 		Project: project, Limit: limit,
@@ -84,8 +90,8 @@ func (c *Client) ListSessions(project string, limit int32) (*pb.ListSessionsResp
 }
 
 // GetSession retrieves a full session via gRPC.
-func (c *Client) GetSession(project, sessionID string) (*pb.GetSessionResponse, error) {
-	cx, cancel := ctx()
+func (c *Client) GetSession(ctx context.Context, project, sessionID string) (*pb.GetSessionResponse, error) {
+	cx, cancel := withTimeout(ctx)
 	defer cancel()
 	return c.memory.GetSession(cx, &pb.GetSessionRequest{
 		Project: project, SessionId: sessionID,
@@ -33,6 +33,16 @@ func GetFloat64(args map[string]any, key string) float64 {
 	return 0
 }
 
+// GetBool extracts a boolean argument.
+func GetBool(args map[string]any, key string) bool {
+	if v, ok := args[key]; ok {
+		if b, ok := v.(bool); ok {
+			return b
+		}
+	}
+	return false
+}
+
 // Has checks if a key exists in the args map.
 func Has(args map[string]any, key string) bool {
 	_, ok := args[key]
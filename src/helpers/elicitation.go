@@ -0,0 +1,38 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+// ErrElicitationUnsupported is returned by Elicit when the client did not
+// declare the elicitation capability.
+var ErrElicitationUnsupported = errors.New("client does not support elicitation")
+
+// ElicitFunc sends elicitation/create to the client and waits for the user's answer.
+type ElicitFunc func(ctx context.Context, params types.ElicitParams) (*types.ElicitResult, error)
+
+type elicitorKey struct{}
+
+// WithElicitor returns a context whose tool handlers can elicit through fn.
+func WithElicitor(ctx context.Context, fn ElicitFunc) context.Context {
+	return context.WithValue(ctx, elicitorKey{}, fn)
+}
+
+// CanElicit reports whether the client behind ctx accepts elicitation requests.
+func CanElicit(ctx context.Context) bool {
+	fn, ok := ctx.Value(elicitorKey{}).(ElicitFunc)
+	return ok && fn != nil
+}
+
+// Elicit asks the user for input matching params.RequestedSchema. A declined
+// or cancelled form is not an error; check ElicitResult.Action.
+func Elicit(ctx context.Context, params types.ElicitParams) (*types.ElicitResult, error) {
+	fn, ok := ctx.Value(elicitorKey{}).(ElicitFunc)
+	if !ok || fn == nil {
+		return nil, ErrElicitationUnsupported
+	}
+	return fn(ctx, params)
+}
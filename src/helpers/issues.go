@@ -1,12 +1,17 @@
 package helpers
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/orchestra-mcp/mcp/src/metrics"
 	"github.com/orchestra-mcp/mcp/src/toon"
+	"github.com/orchestra-mcp/mcp/src/tracing"
 	"github.com/orchestra-mcp/mcp/src/types"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // UpdateProjectStatus adds or updates an issue in the project status.
@@ -142,11 +147,29 @@ func ScanAllTasks(workspaceRoot, slug string) []ScannedTask {
 
 // ScanAllIssues walks the project directory to find all issues.
 func ScanAllIssues(workspaceRoot, slug string) []ScannedIssue {
-	var issues []ScannedIssue
+	return ScanAllIssuesContext(context.Background(), workspaceRoot, slug)
+}
+
+// ScanAllIssuesContext is ScanAllIssues with progress reported per epic.
+// It stops early and returns what it has when ctx is cancelled.
+func ScanAllIssuesContext(ctx context.Context, workspaceRoot, slug string) (issues []ScannedIssue) {
+	start := time.Now()
+	ctx, span := tracing.Start(ctx, "ScanAllIssues", attribute.String("project", slug))
+	defer func() {
+		metrics.ScanDuration.Observe(time.Since(start).Seconds())
+		metrics.ScannedIssues.Add(float64(len(issues)))
+		span.SetAttributes(attribute.Int("issues", len(issues)))
+		span.End()
+	}()
 	projectDir := ProjectDir(workspaceRoot, slug)
 	epicsDir := filepath.Join(projectDir, "epics")
 	epics, _ := os.ReadDir(epicsDir)
-	for _, epicEntry := range epics {
+	total := float64(len(epics))
+	for n, epicEntry := range epics {
+		if ctx.Err() != nil {
+			break
+		}
+		ReportProgress(ctx, float64(n+1), total, "scanning "+epicEntry.Name())
 		if !epicEntry.IsDir() {
 			continue
 		}
@@ -3,6 +3,7 @@ package helpers
 import (
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 // ProjectsDir returns the .projects directory path.
@@ -15,6 +16,19 @@ func ProjectDir(workspaceRoot, slug string) string {
 	return filepath.Join(workspaceRoot, ".projects", slug)
 }
 
+// ProjectSlugs returns the slugs of all project directories in the workspace.
+// Hidden directories such as .logs are skipped.
+func ProjectSlugs(workspaceRoot string) []string {
+	entries, _ := os.ReadDir(ProjectsDir(workspaceRoot))
+	var slugs []string
+	for _, e := range entries {
+		if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+			slugs = append(slugs, e.Name())
+		}
+	}
+	return slugs
+}
+
 // FileExists checks whether a file or directory exists.
 func FileExists(path string) bool {
 	_, err := os.Stat(path)
@@ -0,0 +1,22 @@
+package helpers
+
+import "context"
+
+// ProgressFunc receives progress updates from a running tool.
+// total is zero when the amount of work is unknown.
+type ProgressFunc func(progress, total float64, message string)
+
+type progressKey struct{}
+
+// WithProgress returns a context whose tool handlers report progress to fn.
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// ReportProgress sends a progress update if the caller asked for one.
+// It is a no-op when the request carried no progress token.
+func ReportProgress(ctx context.Context, progress, total float64, message string) {
+	if fn, ok := ctx.Value(progressKey{}).(ProgressFunc); ok && fn != nil {
+		fn(progress, total, message)
+	}
+}
@@ -17,6 +17,32 @@ func JSONResult(v any) *types.ToolResult {
 	return TextResult(string(data))
 }
 
+// StructuredResult returns v as JSON text and as structuredContent.
+// v must encode as a JSON object; use ListResult for slices.
+func StructuredResult(v any) *types.ToolResult {
+	r := JSONResult(v)
+	r.StructuredContent = v
+	return r
+}
+
+// ListResult returns items as a JSON array in the text block, as before,
+// and as {key: items} in structuredContent, which must be an object.
+func ListResult[T any](key string, items []T) *types.ToolResult {
+	r := JSONResult(items)
+	if items == nil {
+		items = []T{}
+	}
+	r.StructuredContent = map[string]any{key: items}
+	return r
+}
+
+// MessageResult returns a plain text result that also carries {"message": text}.
+func MessageResult(text string) *types.ToolResult {
+	r := TextResult(text)
+	r.StructuredContent = map[string]any{"message": text}
+	return r
+}
+
 // ErrorResult creates an error tool result.
 func ErrorResult(msg string) *types.ToolResult {
 	return &types.ToolResult{
@@ -0,0 +1,53 @@
+package helpers
+
+import (
+	"context"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+type rootsKey struct{}
+
+// WithRoots returns a context carrying the roots the client exposed.
+func WithRoots(ctx context.Context, roots []types.Root) context.Context {
+	return context.WithValue(ctx, rootsKey{}, roots)
+}
+
+// Roots returns the client's roots attached to ctx, or nil.
+func Roots(ctx context.Context) []types.Root {
+	roots, _ := ctx.Value(rootsKey{}).([]types.Root)
+	return roots
+}
+
+// RootPath converts a file:// root URI to a local directory path.
+// Other schemes are not served and report false.
+func RootPath(r types.Root) (string, bool) {
+	u, err := url.Parse(r.URI)
+	if err != nil || u.Scheme != "file" || u.Path == "" {
+		return "", false
+	}
+	return filepath.Clean(filepath.FromSlash(u.Path)), true
+}
+
+// WithinDir reports whether path is dir or lies below it. Symlinks are
+// resolved in both where they exist, so a link cannot lead out of dir.
+func WithinDir(dir, path string) bool {
+	rel, err := filepath.Rel(realPath(dir), realPath(path))
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
+
+func realPath(p string) string {
+	if abs, err := filepath.Abs(p); err == nil {
+		p = abs
+	}
+	if real, err := filepath.EvalSymlinks(p); err == nil {
+		return real
+	}
+	return filepath.Clean(p)
+}
@@ -0,0 +1,38 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+// ErrSamplingUnsupported is returned by Sample when the client did not
+// declare the sampling capability.
+var ErrSamplingUnsupported = errors.New("client does not support sampling")
+
+// SampleFunc sends sampling/createMessage to the client and waits for its answer.
+type SampleFunc func(ctx context.Context, params types.CreateMessageParams) (*types.CreateMessageResult, error)
+
+type samplerKey struct{}
+
+// WithSampler returns a context whose tool handlers can sample through fn.
+func WithSampler(ctx context.Context, fn SampleFunc) context.Context {
+	return context.WithValue(ctx, samplerKey{}, fn)
+}
+
+// CanSample reports whether the client behind ctx accepts sampling requests.
+func CanSample(ctx context.Context) bool {
+	fn, ok := ctx.Value(samplerKey{}).(SampleFunc)
+	return ok && fn != nil
+}
+
+// Sample asks the client's model to generate a message. Tools should treat
+// ErrSamplingUnsupported and other errors as a cue to fall back.
+func Sample(ctx context.Context, params types.CreateMessageParams) (*types.CreateMessageResult, error) {
+	fn, ok := ctx.Value(samplerKey{}).(SampleFunc)
+	if !ok || fn == nil {
+		return nil, ErrSamplingUnsupported
+	}
+	return fn(ctx, params)
+}
@@ -0,0 +1,93 @@
+package helpers
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+// SchemaOf derives a JSON Schema from the type of v using its json tags.
+// Struct fields without omitempty are listed as required.
+func SchemaOf(v any) map[string]any {
+	return schemaOfType(reflect.TypeOf(v))
+}
+
+// OutputSchemaOf returns an object output schema for a struct value.
+func OutputSchemaOf(v any) *types.OutputSchema {
+	s := SchemaOf(v)
+	props, _ := s["properties"].(map[string]any)
+	req, _ := s["required"].([]string)
+	return &types.OutputSchema{Type: "object", Properties: props, Required: req}
+}
+
+// ListOutputSchema returns an object schema whose key holds an array of item.
+// It pairs with ListResult.
+func ListOutputSchema(key string, item any) *types.OutputSchema {
+	var items map[string]any
+	if m, ok := item.(map[string]any); ok {
+		items = m
+	} else {
+		items = SchemaOf(item)
+	}
+	return &types.OutputSchema{
+		Type:       "object",
+		Properties: map[string]any{key: map[string]any{"type": "array", "items": items}},
+		Required:   []string{key},
+	}
+}
+
+func schemaOfType(t reflect.Type) map[string]any {
+	if t == nil {
+		return map[string]any{}
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": schemaOfType(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object"}
+	case reflect.Struct:
+		props := map[string]any{}
+		var required []string
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			fs := schemaOfType(f.Type)
+			if !strings.Contains(opts, "omitempty") {
+				required = append(required, name)
+				typ, ok := fs["type"].(string)
+				if k := f.Type.Kind(); ok && (k == reflect.Slice || k == reflect.Map || k == reflect.Pointer) {
+					fs["type"] = []string{typ, "null"} // nil encodes as null
+				}
+			}
+			props[name] = fs
+		}
+		s := map[string]any{"type": "object", "properties": props}
+		if len(required) > 0 {
+			s["required"] = required
+		}
+		return s
+	}
+	return map[string]any{}
+}
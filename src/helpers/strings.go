@@ -11,6 +11,11 @@ var issueIDRegex = regexp.MustCompile(`^[A-Z][A-Z0-9]*-\d+$`)
 // IsIssueID checks if a string matches the issue ID pattern.
 func IsIssueID(name string) bool { return issueIDRegex.MatchString(name) }
 
+var slugRegex = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// IsSlug checks if a string is a slug as Slugify makes them.
+func IsSlug(s string) bool { return slugRegex.MatchString(s) }
+
 // Now returns the current UTC time as RFC3339.
 func Now() string { return time.Now().UTC().Format(time.RFC3339) }
 
@@ -0,0 +1,77 @@
+package helpers
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// URI templates here are RFC 6570 level 1: simple {var} expansions whose
+// values never contain "/" and are never "." or "..", so a value used as a
+// path segment cannot leave its directory.
+
+var templateCache sync.Map // template -> *compiledTemplate
+
+type compiledTemplate struct {
+	re    *regexp.Regexp
+	names []string
+}
+
+// IsURITemplate reports whether uri contains {var} placeholders.
+func IsURITemplate(uri string) bool {
+	return strings.Contains(uri, "{") && strings.Contains(uri, "}")
+}
+
+// MatchURITemplate matches uri against template and returns the variables.
+// A template without placeholders only matches itself, and a variable
+// matching "." or ".." fails the match.
+func MatchURITemplate(template, uri string) (map[string]string, bool) {
+	ct := compileTemplate(template)
+	m := ct.re.FindStringSubmatch(uri)
+	if m == nil {
+		return nil, false
+	}
+	vars := make(map[string]string, len(ct.names))
+	for i, name := range ct.names {
+		if v := m[i+1]; v == "." || v == ".." {
+			return nil, false
+		}
+		vars[name] = m[i+1]
+	}
+	return vars, true
+}
+
+// ExpandURITemplate substitutes vars into template.
+func ExpandURITemplate(template string, vars map[string]string) string {
+	out := template
+	for name, val := range vars {
+		out = strings.ReplaceAll(out, "{"+name+"}", val)
+	}
+	return out
+}
+
+func compileTemplate(template string) *compiledTemplate {
+	if ct, ok := templateCache.Load(template); ok {
+		return ct.(*compiledTemplate)
+	}
+	var pattern strings.Builder
+	var names []string
+	pattern.WriteString("^")
+	rest := template
+	for {
+		open := strings.Index(rest, "{")
+		end := strings.Index(rest, "}")
+		if open < 0 || end < open {
+			pattern.WriteString(regexp.QuoteMeta(rest))
+			break
+		}
+		pattern.WriteString(regexp.QuoteMeta(rest[:open]))
+		pattern.WriteString("([^/]+)")
+		names = append(names, rest[open+1:end])
+		rest = rest[end+1:]
+	}
+	pattern.WriteString("$")
+	ct := &compiledTemplate{re: regexp.MustCompile(pattern.String()), names: names}
+	templateCache.Store(template, ct)
+	return ct
+}
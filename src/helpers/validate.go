@@ -1,53 +1,359 @@
 package helpers
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 
-// ValidateArgs checks that required fields are present and types match.
-func ValidateArgs(args, props map[string]any, required []string) error {
-	for _, req := range required {
-		if _, ok := args[req]; !ok {
-			return fmt.Errorf("missing required parameter: %s", req)
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+// ValidationError is one schema violation. Path locates the value, e.g.
+// "events[2].type"; it is empty for the arguments object itself.
+type ValidationError struct {
+	Path    string
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+// ValidationErrors lists every violation found in one value.
+type ValidationErrors []ValidationError
+
+func (es ValidationErrors) Error() string {
+	msgs := make([]string, len(es))
+	for i, e := range es {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// ValidateInput checks tool arguments against the tool's input schema.
+// Unknown top-level arguments are rejected unless the schema sets
+// AdditionalProperties; nested objects follow JSON Schema defaults.
+func ValidateInput(args map[string]any, s types.InputSchema) error {
+	schema := map[string]any{"type": "object", "properties": s.Properties, "required": s.Required}
+	if s.AdditionalProperties == nil || !*s.AdditionalProperties {
+		schema["additionalProperties"] = false
+	}
+	if args == nil {
+		args = map[string]any{}
+	}
+	return ValidateSchema(schema, args)
+}
+
+// ValidateSchema checks v against a JSON Schema held as a map. It supports
+// the draft 2020-12 keywords tools use: type (including "integer", "null"
+// and type arrays), enum, const, properties, required,
+// additionalProperties, items, prefixItems, minItems, maxItems,
+// uniqueItems, minLength, maxLength, pattern, minimum, maximum,
+// exclusiveMinimum, exclusiveMaximum, multipleOf, allOf, anyOf, oneOf and
+// not. Other keywords are ignored.
+func ValidateSchema(schema map[string]any, v any) error {
+	var errs ValidationErrors
+	validate(schema, v, "", &errs)
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func validate(schema map[string]any, v any, path string, errs *ValidationErrors) {
+	fail := func(format string, args ...any) {
+		*errs = append(*errs, ValidationError{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+	if t, ok := schema["type"]; ok {
+		want := schemaList(t)
+		if !anyType(v, want) {
+			fail("must be %s", joinWords(want, "or"))
+			return
+		}
+	}
+	if enum, ok := schema["enum"]; ok {
+		opts := schemaList(enum)
+		if !containsValue(opts, v) {
+			fail("must be one of %s", joinWords(opts, ","))
 		}
 	}
-	for name, prop := range props {
-		val, ok := args[name]
-		if !ok {
+	if c, ok := schema["const"]; ok && !equalValues(c, v) {
+		fail("must be %v", c)
+	}
+
+	switch x := v.(type) {
+	case string:
+		validateString(schema, x, fail)
+	case map[string]any:
+		validateObject(schema, x, path, errs)
+	case []any:
+		validateArray(schema, x, path, errs)
+	default:
+		if n, ok := toFloat(v); ok {
+			validateNumber(schema, n, fail)
+		}
+	}
+	validateCombinators(schema, v, path, errs, fail)
+}
+
+func validateString(schema map[string]any, s string, fail func(string, ...any)) {
+	n := len([]rune(s))
+	if min, ok := schemaInt(schema, "minLength"); ok && n < min {
+		fail("must be at least %d characters", min)
+	}
+	if max, ok := schemaInt(schema, "maxLength"); ok && n > max {
+		fail("must be at most %d characters", max)
+	}
+	if p, ok := schema["pattern"].(string); ok {
+		if re, err := regexp.Compile(p); err == nil && !re.MatchString(s) {
+			fail("must match pattern %s", p)
+		}
+	}
+}
+
+func validateNumber(schema map[string]any, n float64, fail func(string, ...any)) {
+	if min, ok := toFloat(schema["minimum"]); ok && n < min {
+		fail("must be >= %v", min)
+	}
+	if max, ok := toFloat(schema["maximum"]); ok && n > max {
+		fail("must be <= %v", max)
+	}
+	if min, ok := toFloat(schema["exclusiveMinimum"]); ok && n <= min {
+		fail("must be > %v", min)
+	}
+	if max, ok := toFloat(schema["exclusiveMaximum"]); ok && n >= max {
+		fail("must be < %v", max)
+	}
+	if m, ok := toFloat(schema["multipleOf"]); ok && m > 0 {
+		if q := n / m; math.Abs(q-math.Round(q)) > 1e-9 {
+			fail("must be a multiple of %v", m)
+		}
+	}
+}
+
+func validateObject(schema map[string]any, obj map[string]any, path string, errs *ValidationErrors) {
+	props, _ := schema["properties"].(map[string]any)
+	for _, name := range schemaList(schema["required"]) {
+		key, _ := name.(string)
+		if _, ok := obj[key]; !ok {
+			*errs = append(*errs, ValidationError{Path: joinPath(path, key), Message: "is required"})
+		}
+	}
+	for _, key := range sortedKeys(obj) {
+		if sub, ok := props[key].(map[string]any); ok {
+			validate(sub, obj[key], joinPath(path, key), errs)
 			continue
 		}
-		propMap, ok := prop.(map[string]any)
-		if !ok {
+		if _, declared := props[key]; declared {
 			continue
 		}
-		expected, _ := propMap["type"].(string)
-		if err := checkType(name, val, expected); err != nil {
-			return err
+		switch extra := schema["additionalProperties"].(type) {
+		case bool:
+			if !extra {
+				*errs = append(*errs, ValidationError{Path: joinPath(path, key), Message: "unknown property"})
+			}
+		case map[string]any:
+			validate(extra, obj[key], joinPath(path, key), errs)
 		}
 	}
-	return nil
 }
 
-func checkType(name string, val any, expected string) error {
-	switch expected {
-	case "string":
-		if _, ok := val.(string); !ok {
-			return fmt.Errorf("parameter %s must be a string", name)
+func validateArray(schema map[string]any, arr []any, path string, errs *ValidationErrors) {
+	fail := func(format string, args ...any) {
+		*errs = append(*errs, ValidationError{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+	if min, ok := schemaInt(schema, "minItems"); ok && len(arr) < min {
+		fail("must have at least %d items", min)
+	}
+	if max, ok := schemaInt(schema, "maxItems"); ok && len(arr) > max {
+		fail("must have at most %d items", max)
+	}
+	if unique, _ := schema["uniqueItems"].(bool); unique {
+		for i := range arr {
+			for j := i + 1; j < len(arr); j++ {
+				if equalValues(arr[i], arr[j]) {
+					fail("items %d and %d are equal", i, j)
+				}
+			}
 		}
-	case "number":
-		if _, ok := val.(float64); !ok {
-			return fmt.Errorf("parameter %s must be a number", name)
+	}
+	prefix := schemaList(schema["prefixItems"])
+	for i, item := range arr {
+		itemPath := path + "[" + strconv.Itoa(i) + "]"
+		if i < len(prefix) {
+			if sub, ok := prefix[i].(map[string]any); ok {
+				validate(sub, item, itemPath, errs)
+			}
+			continue
 		}
-	case "boolean":
-		if _, ok := val.(bool); !ok {
-			return fmt.Errorf("parameter %s must be a boolean", name)
+		if sub, ok := schema["items"].(map[string]any); ok {
+			validate(sub, item, itemPath, errs)
 		}
-	case "array":
-		if _, ok := val.([]any); !ok {
-			return fmt.Errorf("parameter %s must be an array", name)
+	}
+}
+
+func validateCombinators(schema map[string]any, v any, path string, errs *ValidationErrors, fail func(string, ...any)) {
+	for _, sub := range subSchemas(schema["allOf"]) {
+		validate(sub, v, path, errs)
+	}
+	if subs := subSchemas(schema["anyOf"]); len(subs) > 0 && countMatches(subs, v) == 0 {
+		fail("must match at least one allowed schema")
+	}
+	if subs := subSchemas(schema["oneOf"]); len(subs) > 0 {
+		if n := countMatches(subs, v); n != 1 {
+			fail("must match exactly one allowed schema, matched %d", n)
+		}
+	}
+	if sub, ok := schema["not"].(map[string]any); ok && ValidateSchema(sub, v) == nil {
+		fail("must not match the excluded schema")
+	}
+}
+
+func countMatches(subs []map[string]any, v any) int {
+	n := 0
+	for _, sub := range subs {
+		if ValidateSchema(sub, v) == nil {
+			n++
+		}
+	}
+	return n
+}
+
+func subSchemas(v any) []map[string]any {
+	var out []map[string]any
+	for _, s := range schemaList(v) {
+		if m, ok := s.(map[string]any); ok {
+			out = append(out, m)
 		}
+	}
+	return out
+}
+
+// anyType reports whether v has one of the JSON types named in want.
+func anyType(v any, want []any) bool {
+	for _, w := range want {
+		if name, _ := w.(string); isType(v, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func isType(v any, name string) bool {
+	switch name {
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "null":
+		return v == nil
 	case "object":
-		if _, ok := val.(map[string]any); !ok {
-			return fmt.Errorf("parameter %s must be an object", name)
+		_, ok := v.(map[string]any)
+		return ok
+	case "array":
+		_, ok := v.([]any)
+		return ok
+	case "number":
+		_, ok := toFloat(v)
+		return ok
+	case "integer":
+		n, ok := toFloat(v)
+		return ok && n == math.Trunc(n)
+	}
+	return true // unknown type names do not constrain
+}
+
+// toFloat reads JSON numbers (float64) and the Go integers schemas are
+// often written with.
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+func schemaInt(schema map[string]any, key string) (int, bool) {
+	n, ok := toFloat(schema[key])
+	return int(n), ok
+}
+
+// schemaList turns a schema keyword value into a list, accepting the typed
+// slices Go schema literals use (e.g. []string for enum) as well as []any.
+// A single value becomes a one-element list.
+func schemaList(v any) []any {
+	if v == nil {
+		return nil
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return []any{v}
+	}
+	out := make([]any, rv.Len())
+	for i := range out {
+		out[i] = rv.Index(i).Interface()
+	}
+	return out
+}
+
+func containsValue(opts []any, v any) bool {
+	for _, o := range opts {
+		if equalValues(o, v) {
+			return true
 		}
 	}
-	return nil
+	return false
+}
+
+// equalValues compares JSON values, treating numbers of any Go type equally.
+func equalValues(a, b any) bool {
+	if x, ok := toFloat(a); ok {
+		y, ok := toFloat(b)
+		return ok && x == y
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func joinWords(vals []any, sep string) string {
+	words := make([]string, len(vals))
+	for i, v := range vals {
+		words[i] = fmt.Sprint(v)
+	}
+	if sep == "," {
+		return strings.Join(words, ", ")
+	}
+	return strings.Join(words, " "+sep+" ")
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
 }
@@ -0,0 +1,130 @@
+package hooks
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	h "github.com/orchestra-mcp/mcp/src/helpers"
+	"github.com/orchestra-mcp/mcp/src/logger"
+)
+
+// maxPayload bounds one hook event on the socket and on stdin.
+const maxPayload = 1 << 20
+
+// maxSocketPath is the longest socket path every platform accepts; Unix
+// socket paths are limited to about 100 bytes.
+const maxSocketPath = 100
+
+// SocketPath returns the daemon socket for a workspace. With
+// $XDG_RUNTIME_DIR set it lives there, keyed by the absolute workspace
+// path; otherwise in .projects/.run. Listen keeps the directory private to
+// the user, so other local users can neither connect nor pre-bind it.
+func SocketPath(ws string) string {
+	abs, err := filepath.Abs(ws)
+	if err != nil {
+		abs = ws
+	}
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		sum := sha256.Sum256([]byte(abs))
+		return filepath.Join(dir, "orchestra-mcp", "hook-"+hex.EncodeToString(sum[:6])+".sock")
+	}
+	return filepath.Join(h.ProjectsDir(abs), ".run", "hook.sock")
+}
+
+// ErrDaemonRunning is returned by Listen when another process already
+// serves the workspace socket.
+var ErrDaemonRunning = errors.New("hook daemon already running")
+
+// Daemon accepts hook events on a Unix socket and appends them to the
+// workspace log one at a time. Each connection sends JSON payloads, one
+// per line, and gets "ok" or "error: ..." back for each.
+type Daemon struct {
+	ws string
+	ln net.Listener
+}
+
+// Listen opens the workspace socket, replacing a stale one left by a
+// crashed process. The socket's directory is made 0700 and the socket 0600.
+func Listen(ws string) (*Daemon, error) {
+	path := SocketPath(ws)
+	if len(path) > maxSocketPath {
+		return nil, fmt.Errorf("hook socket path %s is too long; set XDG_RUNTIME_DIR", path)
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(dir, 0o700); err != nil {
+		return nil, err
+	}
+	if conn, err := net.DialTimeout("unix", path, 100*time.Millisecond); err == nil {
+		conn.Close()
+		return nil, ErrDaemonRunning
+	}
+	_ = os.Remove(path)
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		ln.Close()
+		return nil, err
+	}
+	return &Daemon{ws: ws, ln: ln}, nil
+}
+
+// Serve accepts connections until ctx is done or Close is called.
+func (d *Daemon) Serve(ctx context.Context) error {
+	go func() {
+		<-ctx.Done()
+		d.Close()
+	}()
+	for {
+		conn, err := d.ln.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		go d.handle(conn)
+	}
+}
+
+// Close stops the daemon and removes its socket.
+func (d *Daemon) Close() error {
+	return d.ln.Close() // a unix listener unlinks its socket on close
+}
+
+func (d *Daemon) handle(conn net.Conn) {
+	defer conn.Close()
+	sc := bufio.NewScanner(conn)
+	sc.Buffer(make([]byte, 64*1024), maxPayload)
+	for sc.Scan() {
+		reply := "ok\n"
+		if err := d.ingest(sc.Bytes()); err != nil {
+			logger.Warning("hooks", "event rejected", "error", err)
+			reply = "error: " + err.Error() + "\n"
+		}
+		if _, err := conn.Write([]byte(reply)); err != nil {
+			return
+		}
+	}
+}
+
+func (d *Daemon) ingest(line []byte) error {
+	var payload map[string]any
+	if err := json.Unmarshal(line, &payload); err != nil {
+		return err
+	}
+	return Append(d.ws, EventFromPayload(payload))
+}
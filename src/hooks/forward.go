@@ -0,0 +1,68 @@
+package hooks
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// ForwardTimeout bounds the whole exchange with the daemon, so a hook
+// never waits long before falling back.
+var ForwardTimeout = 500 * time.Millisecond
+
+// ErrNoDaemon wraps the error returned by Forward when the payload never
+// reached the daemon, so storing the event directly cannot duplicate it.
+var ErrNoDaemon = errors.New("hook daemon unreachable")
+
+// Forward sends one hook payload to the workspace daemon and returns nil
+// once the daemon has stored the event. Errors after the payload was sent
+// (a late or failed reply) do not wrap ErrNoDaemon, since the daemon may
+// already have appended the event.
+func Forward(ws string, payload []byte) error {
+	conn, err := net.DialTimeout("unix", SocketPath(ws), ForwardTimeout)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrNoDaemon, err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(ForwardTimeout))
+	if _, err := conn.Write(append(bytes.TrimSpace(payload), '\n')); err != nil {
+		return fmt.Errorf("%w: %v", ErrNoDaemon, err)
+	}
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return err
+	}
+	if reply = strings.TrimSpace(reply); reply != "ok" {
+		return fmt.Errorf("daemon: %s", reply)
+	}
+	return nil
+}
+
+// Ingest reads one hook payload from r and stores it, through the daemon
+// when one is running and by writing the log directly otherwise. It
+// returns which path was taken: "daemon" or "direct". Once the payload has
+// reached the daemon it is never written again, even if the reply fails.
+func Ingest(ws string, r io.Reader) (string, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxPayload))
+	if err != nil {
+		return "", err
+	}
+	data = bytes.TrimSpace(data)
+	var payload map[string]any
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return "", fmt.Errorf("invalid hook payload: %w", err)
+	}
+	// Compact so the payload fits on one line of the socket protocol.
+	var line bytes.Buffer
+	_ = json.Compact(&line, data)
+	if err := Forward(ws, line.Bytes()); !errors.Is(err, ErrNoDaemon) {
+		return "daemon", err
+	}
+	return "direct", Append(ws, EventFromPayload(payload))
+}
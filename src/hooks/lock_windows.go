@@ -0,0 +1,9 @@
+//go:build windows
+
+package hooks
+
+// lockFile is a no-op on Windows; writes are still serialized in-process
+// and by the daemon.
+func lockFile(path string) (func(), error) {
+	return func() {}, nil
+}
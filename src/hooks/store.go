@@ -0,0 +1,73 @@
+// Package hooks stores Claude Code hook events and runs the local daemon
+// that ingests them without starting a full MCP server per event.
+package hooks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	h "github.com/orchestra-mcp/mcp/src/helpers"
+	"github.com/orchestra-mcp/mcp/src/toon"
+	t "github.com/orchestra-mcp/mcp/src/types"
+)
+
+// MaxEvents is how many events the rolling log keeps.
+const MaxEvents = 100
+
+// LogPath returns the hook event log of a workspace.
+func LogPath(ws string) string {
+	return filepath.Join(h.ProjectsDir(ws), ".events", "hook-events.toon")
+}
+
+// writeMu serializes writers inside one process; lockFile covers writers
+// in other processes, such as hooks falling back to direct writes.
+var writeMu sync.Mutex
+
+// Append adds an event to the workspace log, keeping the last MaxEvents.
+func Append(ws string, event t.HookEvent) error {
+	path := LogPath(ws)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	writeMu.Lock()
+	defer writeMu.Unlock()
+	unlock, err := lockFile(path + ".lock")
+	if err != nil {
+		return fmt.Errorf("lock hook log: %w", err)
+	}
+	defer unlock()
+
+	var log t.HookEventLog
+	_ = toon.ParseFile(path, &log) // ignore if not exists
+	log.Events = append(log.Events, event)
+	if len(log.Events) > MaxEvents {
+		log.Events = log.Events[len(log.Events)-MaxEvents:]
+	}
+	return toon.WriteFile(path, &log)
+}
+
+// Read returns the events in the workspace log, oldest first.
+func Read(ws string) []t.HookEvent {
+	var log t.HookEventLog
+	_ = toon.ParseFile(LogPath(ws), &log) // ignore error: file may not exist yet
+	return log.Events
+}
+
+// EventFromPayload builds an event from the JSON Claude Code passes to hooks.
+// The whole payload is kept as Data.
+func EventFromPayload(payload map[string]any) t.HookEvent {
+	event := t.HookEvent{
+		EventType: h.GetString(payload, "hook_event_name"),
+		SessionID: h.GetString(payload, "session_id"),
+		ToolName:  h.GetString(payload, "tool_name"),
+		AgentType: h.GetString(payload, "agent_type"),
+		Data:      payload,
+		Timestamp: h.Now(),
+	}
+	if event.EventType == "" {
+		event.EventType = "unknown"
+	}
+	return event
+}
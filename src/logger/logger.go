@@ -0,0 +1,151 @@
+// Package logger provides leveled, structured logging fanned out to sinks:
+// stderr, a rotating JSON file, and MCP clients via notifications/message.
+package logger
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Level is a syslog severity (RFC 5424), as used by MCP logging.
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelNotice
+	LevelWarning
+	LevelError
+	LevelCritical
+	LevelAlert
+	LevelEmergency
+)
+
+var levelNames = [...]string{"debug", "info", "notice", "warning", "error", "critical", "alert", "emergency"}
+
+func (l Level) String() string {
+	if l < LevelDebug || l > LevelEmergency {
+		return fmt.Sprintf("level(%d)", int(l))
+	}
+	return levelNames[l]
+}
+
+// MarshalText encodes the level by name.
+func (l Level) MarshalText() ([]byte, error) { return []byte(l.String()), nil }
+
+// ParseLevel converts an MCP level name such as "warning" to a Level.
+func ParseLevel(s string) (Level, error) {
+	for i, name := range levelNames {
+		if name == s {
+			return Level(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown log level %q", s)
+}
+
+// Entry is a single log record.
+type Entry struct {
+	Time    time.Time      `json:"time"`
+	Level   Level          `json:"level"`
+	Logger  string         `json:"logger,omitempty"`
+	Message string         `json:"message"`
+	Fields  map[string]any `json:"fields,omitempty"`
+}
+
+// Data returns the message and fields as one object, the payload of
+// notifications/message.
+func (e Entry) Data() map[string]any {
+	data := map[string]any{"message": e.Message}
+	for k, v := range e.Fields {
+		data[k] = v
+	}
+	return data
+}
+
+// Sink receives every entry logged; it filters by level itself.
+type Sink interface {
+	Write(Entry)
+}
+
+// SinkFunc adapts a function to a Sink.
+type SinkFunc func(Entry)
+
+func (f SinkFunc) Write(e Entry) { f(e) }
+
+// Logger fans entries out to its sinks. The zero value is not usable; use New.
+type Logger struct {
+	mu    sync.RWMutex
+	sinks map[int]Sink
+	next  int
+}
+
+// New creates a logger without sinks; entries are dropped until one is added.
+func New() *Logger {
+	return &Logger{sinks: make(map[int]Sink)}
+}
+
+var std = New()
+
+// Default returns the process-wide logger used by the package functions.
+func Default() *Logger { return std }
+
+// AddSink attaches s and returns a func that detaches it.
+func (l *Logger) AddSink(s Sink) (remove func()) {
+	l.mu.Lock()
+	id := l.next
+	l.next++
+	l.sinks[id] = s
+	l.mu.Unlock()
+	return func() {
+		l.mu.Lock()
+		delete(l.sinks, id)
+		l.mu.Unlock()
+	}
+}
+
+// Log records msg from the named component. kv are alternating key/value
+// pairs; error values are stored as their message.
+func (l *Logger) Log(level Level, name, msg string, kv ...any) {
+	e := Entry{Time: time.Now().UTC(), Level: level, Logger: name, Message: msg}
+	if len(kv) > 0 {
+		e.Fields = make(map[string]any, len(kv)/2)
+		for i := 0; i+1 < len(kv); i += 2 {
+			v := kv[i+1]
+			if err, ok := v.(error); ok {
+				v = err.Error()
+			}
+			e.Fields[fmt.Sprint(kv[i])] = v
+		}
+	}
+	l.mu.RLock()
+	ids := make([]int, 0, len(l.sinks))
+	for id := range l.sinks {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	sinks := make([]Sink, len(ids))
+	for i, id := range ids {
+		sinks[i] = l.sinks[id]
+	}
+	l.mu.RUnlock()
+	for _, s := range sinks {
+		s.Write(e)
+	}
+}
+
+// Debug logs to the default logger at debug level.
+func Debug(name, msg string, kv ...any) { std.Log(LevelDebug, name, msg, kv...) }
+
+// Info logs to the default logger at info level.
+func Info(name, msg string, kv ...any) { std.Log(LevelInfo, name, msg, kv...) }
+
+// Notice logs to the default logger at notice level.
+func Notice(name, msg string, kv ...any) { std.Log(LevelNotice, name, msg, kv...) }
+
+// Warning logs to the default logger at warning level.
+func Warning(name, msg string, kv ...any) { std.Log(LevelWarning, name, msg, kv...) }
+
+// Error logs to the default logger at error level.
+func Error(name, msg string, kv ...any) { std.Log(LevelError, name, msg, kv...) }
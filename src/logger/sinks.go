@@ -0,0 +1,138 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// WriterSink writes entries at or above min as one readable line each,
+// e.g. "[Orchestra MCP] warning memory: gRPC failed error=...".
+type WriterSink struct {
+	mu  sync.Mutex
+	w   io.Writer
+	min Level
+}
+
+// NewWriterSink creates a line sink on w, typically os.Stderr.
+func NewWriterSink(w io.Writer, min Level) *WriterSink {
+	return &WriterSink{w: w, min: min}
+}
+
+func (s *WriterSink) Write(e Entry) {
+	if e.Level < s.min {
+		return
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "[Orchestra MCP] %s", e.Level)
+	if e.Logger != "" {
+		fmt.Fprintf(&b, " %s", e.Logger)
+	}
+	fmt.Fprintf(&b, ": %s", e.Message)
+	keys := make([]string, 0, len(e.Fields))
+	for k := range e.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, " %s=%v", k, e.Fields[k])
+	}
+	b.WriteByte('\n')
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, _ = io.WriteString(s.w, b.String())
+}
+
+// FileSink appends entries at or above min as JSON lines. When the file
+// would grow past maxSize it is rotated to path.1, path.2, ... and only
+// backups old files are kept.
+type FileSink struct {
+	mu      sync.Mutex
+	path    string
+	min     Level
+	maxSize int64
+	backups int
+	f       *os.File
+	size    int64
+}
+
+// NewFileSink opens (or creates) path for appending, creating its directory.
+func NewFileSink(path string, min Level, maxSize int64, backups int) (*FileSink, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	s := &FileSink{path: path, min: min, maxSize: maxSize, backups: backups}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *FileSink) open() error {
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	s.f, s.size = f, info.Size()
+	return nil
+}
+
+func (s *FileSink) Write(e Entry) {
+	if e.Level < s.min {
+		return
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	line = append(line, '\n')
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.f == nil {
+		return
+	}
+	if s.maxSize > 0 && s.size > 0 && s.size+int64(len(line)) > s.maxSize {
+		if s.rotate() != nil {
+			return
+		}
+	}
+	n, _ := s.f.Write(line)
+	s.size += int64(n)
+}
+
+// rotate shifts path.N-1 to path.N, ..., path to path.1 and reopens path.
+func (s *FileSink) rotate() error {
+	s.f.Close()
+	s.f = nil
+	if s.backups > 0 {
+		for i := s.backups - 1; i >= 1; i-- {
+			_ = os.Rename(fmt.Sprintf("%s.%d", s.path, i), fmt.Sprintf("%s.%d", s.path, i+1))
+		}
+		_ = os.Rename(s.path, s.path+".1")
+	} else {
+		_ = os.Remove(s.path)
+	}
+	return s.open()
+}
+
+// Close closes the log file; later writes are dropped.
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.f == nil {
+		return nil
+	}
+	err := s.f.Close()
+	s.f = nil
+	return err
+}
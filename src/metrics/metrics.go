@@ -0,0 +1,32 @@
+package metrics
+
+// Default is the registry the server's metrics live in.
+var Default = NewRegistry()
+
+// Server metrics.
+var (
+	ToolCalls = Default.NewCounter("orchestra_mcp_tool_calls",
+		"Tool calls by tool.", "tool")
+	ToolDuration = Default.NewHistogram("orchestra_mcp_tool_call_duration_seconds",
+		"Tool call latency by tool.", DefaultBuckets, "tool")
+	ToolErrors = Default.NewCounter("orchestra_mcp_tool_errors",
+		"Failed tool calls by JSON-RPC error code.", "code")
+	SSESessions = Default.NewGauge("orchestra_mcp_sse_sessions",
+		"Open SSE sessions by transport.", "transport")
+	BridgeFallbacks = Default.NewCounter("orchestra_mcp_bridge_fallbacks",
+		"Memory operations served from TOON instead of the engine, by operation and reason.", "operation", "reason")
+	EngineUp = Default.NewGauge("orchestra_mcp_engine_up",
+		"1 when the Rust engine is running (process) or connected (grpc).", "check")
+	ScanDuration = Default.NewHistogram("orchestra_mcp_scan_duration_seconds",
+		"Time taken by ScanAllIssues to walk a project.", DefaultBuckets)
+	ScannedIssues = Default.NewCounter("orchestra_mcp_scanned_issues",
+		"Issues read by ScanAllIssues.")
+)
+
+// Bool reports b as 1 or 0 for gauges.
+func Bool(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
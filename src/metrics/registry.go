@@ -0,0 +1,185 @@
+// Package metrics is a small OpenMetrics exporter for the server's
+// counters, gauges and histograms. Metrics register in a Registry, and
+// Handler serves the registry in the OpenMetrics text format.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType is the OpenMetrics text exposition media type.
+const ContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// DefaultBuckets are latency buckets in seconds, from 1ms to 10s.
+var DefaultBuckets = []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// family is one metric family: a counter, gauge or histogram.
+type family interface {
+	name() string
+	write(w *bufio.Writer)
+}
+
+// Registry holds metric families in registration order.
+type Registry struct {
+	mu       sync.RWMutex
+	families []family
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry { return &Registry{} }
+
+func (r *Registry) register(f family) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.families {
+		if existing.name() == f.name() {
+			panic("metrics: duplicate metric " + f.name())
+		}
+	}
+	r.families = append(r.families, f)
+}
+
+// WriteTo writes every family in the OpenMetrics text format.
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	r.mu.RLock()
+	families := append([]family(nil), r.families...)
+	r.mu.RUnlock()
+	for _, f := range families {
+		f.write(bw)
+	}
+	bw.WriteString("# EOF\n")
+	err := bw.Flush()
+	return cw.n, err
+}
+
+// Handler serves the registry.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		_, _ = r.WriteTo(w)
+	})
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// vec stores one value per label combination.
+type vec[T any] struct {
+	mu     sync.Mutex
+	labels []string
+	series map[string]*series[T]
+}
+
+type series[T any] struct {
+	values []string
+	v      T
+}
+
+func newVec[T any](labels []string) vec[T] {
+	return vec[T]{labels: labels, series: make(map[string]*series[T])}
+}
+
+// with returns the series for values, creating it with init. The caller
+// must hold v.mu.
+func (v *vec[T]) with(values []string, init func() T) *series[T] {
+	if len(values) != len(v.labels) {
+		panic(fmt.Sprintf("metrics: got %d label values, want %d", len(values), len(v.labels)))
+	}
+	key := strings.Join(values, "\xff")
+	s, ok := v.series[key]
+	if !ok {
+		s = &series[T]{values: append([]string(nil), values...), v: init()}
+		v.series[key] = s
+	}
+	return s
+}
+
+// get returns the series for values without creating it. The caller must
+// hold v.mu.
+func (v *vec[T]) get(values []string) (T, bool) {
+	s, ok := v.series[strings.Join(values, "\xff")]
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return s.v, true
+}
+
+// sorted returns the series ordered by label values. The caller must hold
+// v.mu.
+func (v *vec[T]) sorted() []*series[T] {
+	keys := make([]string, 0, len(v.series))
+	for k := range v.series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	out := make([]*series[T], len(keys))
+	for i, k := range keys {
+		out[i] = v.series[k]
+	}
+	return out
+}
+
+// labelSet renders {a="x",b="y"}, with extra appended as name, value pairs.
+func labelSet(names, values []string, extra ...string) string {
+	if len(names) == 0 && len(extra) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteByte('{')
+	pair := func(n, v string) {
+		if b.Len() > 1 {
+			b.WriteByte(',')
+		}
+		b.WriteString(n)
+		b.WriteString(`="`)
+		b.WriteString(escape(v))
+		b.WriteByte('"')
+	}
+	for i, n := range names {
+		pair(n, values[i])
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		pair(extra[i], extra[i+1])
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escape(s string) string { return escaper.Replace(s) }
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	case math.IsNaN(f):
+		return "NaN"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func writeHeader(w *bufio.Writer, name, typ, help string) {
+	fmt.Fprintf(w, "# TYPE %s %s\n# HELP %s %s\n", name, typ, name, escape(help))
+}
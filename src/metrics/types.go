@@ -0,0 +1,190 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"sort"
+)
+
+// Counter is a monotonically increasing value per label combination. Its
+// samples are exposed with the _total suffix.
+type Counter struct {
+	fname, help string
+	vec[float64]
+}
+
+// NewCounter registers a counter. name excludes the _total suffix.
+func (r *Registry) NewCounter(name, help string, labels ...string) *Counter {
+	c := &Counter{fname: name, help: help, vec: newVec[float64](labels)}
+	r.register(c)
+	return c
+}
+
+// Inc adds one to the series with the given label values.
+func (c *Counter) Inc(values ...string) { c.Add(1, values...) }
+
+// Add adds n, which must not be negative, to the series.
+func (c *Counter) Add(n float64, values ...string) {
+	if n < 0 {
+		panic("metrics: counter decreased")
+	}
+	c.mu.Lock()
+	c.with(values, func() float64 { return 0 }).v += n
+	c.mu.Unlock()
+}
+
+// Value returns the current value of the series.
+func (c *Counter) Value(values ...string) float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, _ := c.get(values)
+	return v
+}
+
+func (c *Counter) name() string { return c.fname }
+
+func (c *Counter) write(w *bufio.Writer) {
+	writeHeader(w, c.fname, "counter", c.help)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, s := range c.sorted() {
+		fmt.Fprintf(w, "%s_total%s %s\n", c.fname, labelSet(c.labels, s.values), formatFloat(s.v))
+	}
+}
+
+// Gauge is a value that can go up and down, or be read from a function at
+// scrape time.
+type Gauge struct {
+	fname, help string
+	vec[gaugeValue]
+}
+
+type gaugeValue struct {
+	v  float64
+	fn func() float64
+}
+
+// NewGauge registers a gauge.
+func (r *Registry) NewGauge(name, help string, labels ...string) *Gauge {
+	g := &Gauge{fname: name, help: help, vec: newVec[gaugeValue](labels)}
+	r.register(g)
+	return g
+}
+
+// Set stores v for the series.
+func (g *Gauge) Set(v float64, values ...string) {
+	g.mu.Lock()
+	g.with(values, func() gaugeValue { return gaugeValue{} }).v = gaugeValue{v: v}
+	g.mu.Unlock()
+}
+
+// SetFunc makes the series report fn's result at each scrape.
+func (g *Gauge) SetFunc(fn func() float64, values ...string) {
+	g.mu.Lock()
+	g.with(values, func() gaugeValue { return gaugeValue{} }).v = gaugeValue{fn: fn}
+	g.mu.Unlock()
+}
+
+// Value returns the current value of the series.
+func (g *Gauge) Value(values ...string) float64 {
+	g.mu.Lock()
+	gv, _ := g.get(values)
+	g.mu.Unlock()
+	return gv.value()
+}
+
+func (gv gaugeValue) value() float64 {
+	if gv.fn != nil {
+		return gv.fn()
+	}
+	return gv.v
+}
+
+func (g *Gauge) name() string { return g.fname }
+
+func (g *Gauge) write(w *bufio.Writer) {
+	writeHeader(w, g.fname, "gauge", g.help)
+	g.mu.Lock()
+	all := g.sorted()
+	labels := make([]string, len(all))
+	vals := make([]gaugeValue, len(all))
+	for i, s := range all {
+		labels[i], vals[i] = labelSet(g.labels, s.values), s.v
+	}
+	g.mu.Unlock()
+	for i := range all {
+		// Functions run unlocked: they may take other locks.
+		fmt.Fprintf(w, "%s%s %s\n", g.fname, labels[i], formatFloat(vals[i].value()))
+	}
+}
+
+// Histogram counts observations in cumulative buckets per label
+// combination.
+type Histogram struct {
+	fname, help string
+	buckets     []float64
+	vec[*histogramData]
+}
+
+type histogramData struct {
+	counts []uint64 // per bucket, not cumulative
+	count  uint64
+	sum    float64
+}
+
+// NewHistogram registers a histogram with the given upper bounds, which
+// are sorted; the +Inf bucket is implicit.
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	b := append([]float64(nil), buckets...)
+	sort.Float64s(b)
+	hg := &Histogram{fname: name, help: help, buckets: b, vec: newVec[*histogramData](labels)}
+	r.register(hg)
+	return hg
+}
+
+// Observe records v in the series.
+func (hg *Histogram) Observe(v float64, values ...string) {
+	hg.mu.Lock()
+	defer hg.mu.Unlock()
+	d := hg.with(values, hg.newData).v
+	for i, ub := range hg.buckets {
+		if v <= ub {
+			d.counts[i]++
+			break
+		}
+	}
+	d.count++
+	d.sum += v
+}
+
+// Count returns how many observations the series holds.
+func (hg *Histogram) Count(values ...string) uint64 {
+	hg.mu.Lock()
+	defer hg.mu.Unlock()
+	if d, ok := hg.get(values); ok {
+		return d.count
+	}
+	return 0
+}
+
+func (hg *Histogram) newData() *histogramData {
+	return &histogramData{counts: make([]uint64, len(hg.buckets))}
+}
+
+func (hg *Histogram) name() string { return hg.fname }
+
+func (hg *Histogram) write(w *bufio.Writer) {
+	writeHeader(w, hg.fname, "histogram", hg.help)
+	hg.mu.Lock()
+	defer hg.mu.Unlock()
+	for _, s := range hg.sorted() {
+		var cum uint64
+		for i, ub := range hg.buckets {
+			cum += s.v.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", hg.fname, labelSet(hg.labels, s.values, "le", formatFloat(ub)), cum)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", hg.fname, labelSet(hg.labels, s.values, "le", "+Inf"), s.v.count)
+		fmt.Fprintf(w, "%s_count%s %d\n", hg.fname, labelSet(hg.labels, s.values), s.v.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", hg.fname, labelSet(hg.labels, s.values), formatFloat(s.v.sum))
+	}
+}
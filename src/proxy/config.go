@@ -0,0 +1,101 @@
+package proxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/orchestra-mcp/mcp/src/client"
+	"gopkg.in/yaml.v3"
+)
+
+// Config lists the upstream servers to mount. It uses the "mcpServers"
+// layout of .mcp.json, so an existing client config can be reused.
+type Config struct {
+	Servers map[string]ServerConfig `json:"mcpServers" yaml:"mcpServers"`
+}
+
+// ServerConfig describes one upstream. Set Command for a stdio server or
+// URL for a Streamable HTTP one. Env and Headers values expand ${VAR}
+// references from the environment, so tokens can stay out of the file.
+type ServerConfig struct {
+	Command  string            `json:"command" yaml:"command"`
+	Args     []string          `json:"args" yaml:"args"`
+	Env      map[string]string `json:"env" yaml:"env"`
+	URL      string            `json:"url" yaml:"url"`
+	Headers  map[string]string `json:"headers" yaml:"headers"`
+	Disabled bool              `json:"disabled" yaml:"disabled"`
+}
+
+// Load reads a proxy config file: YAML for .yaml and .yml, JSON otherwise.
+func Load(path string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &cfg)
+	default:
+		err = json.Unmarshal(data, &cfg)
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("proxy config %s: %w", path, err)
+	}
+	return cfg, cfg.Validate()
+}
+
+// Validate checks every server has a usable name and exactly one of
+// Command and URL.
+func (c Config) Validate() error {
+	for _, name := range c.Names() {
+		if err := validName(name); err != nil {
+			return err
+		}
+		s := c.Servers[name]
+		if (s.Command == "") == (s.URL == "") {
+			return fmt.Errorf("proxy server %q: set either command or url", name)
+		}
+	}
+	return nil
+}
+
+// Names returns the configured server names in order.
+func (c Config) Names() []string {
+	names := make([]string, 0, len(c.Servers))
+	for name := range c.Servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Dial starts or connects to the server. The client is not yet initialized.
+func (s ServerConfig) Dial() (*client.Client, error) {
+	if s.URL != "" {
+		header := http.Header{}
+		for k, v := range s.Headers {
+			header.Set(k, os.ExpandEnv(v))
+		}
+		return client.NewHTTP(s.URL, header), nil
+	}
+	env := make([]string, 0, len(s.Env))
+	for k, v := range s.Env {
+		env = append(env, k+"="+os.ExpandEnv(v))
+	}
+	return client.NewStdio(s.Command, s.Args, env)
+}
+
+// validName rejects names that would make ambiguous "<name>.<tool>" names
+// or "<name>+<uri>" URIs.
+func validName(name string) error {
+	if name == "" || strings.ContainsAny(name, ".+ /") {
+		return fmt.Errorf("proxy server name %q: must be non-empty without dots, plus signs, slashes or spaces", name)
+	}
+	return nil
+}
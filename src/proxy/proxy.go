@@ -0,0 +1,369 @@
+// Package proxy mounts upstream MCP servers into a local server. Each
+// upstream's tools and prompts appear as "<name>.<original>", its resource
+// URIs as "<name>+<original>", and calls are forwarded over an MCP client.
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/orchestra-mcp/mcp/src/client"
+	"github.com/orchestra-mcp/mcp/src/logger"
+	"github.com/orchestra-mcp/mcp/src/toolsets"
+	"github.com/orchestra-mcp/mcp/src/transport"
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+// requestTimeout bounds list syncs and the resource and prompt forwards,
+// whose handlers have no caller context. Tool calls use the caller's.
+const requestTimeout = 30 * time.Second
+
+// Filter picks which tools of a group are exposed, as
+// toolsets.Selection.Filter does.
+type Filter func(group string, tools []types.Tool) []types.Tool
+
+// Proxy tracks the upstreams mounted into one server.
+type Proxy struct {
+	server    *transport.MCPServer
+	filter    Filter
+	mu        sync.Mutex
+	upstreams map[string]*upstream
+}
+
+// New creates a proxy that registers upstream entries on s.
+func New(s *transport.MCPServer) *Proxy {
+	return &Proxy{server: s, upstreams: make(map[string]*upstream)}
+}
+
+// SetFilter applies fn to each upstream's tools under the group
+// toolsets.ProxyGroup(name) before they are registered. Set it before
+// mounting anything.
+func (p *Proxy) SetFilter(fn Filter) {
+	p.filter = fn
+}
+
+// Start dials and mounts every enabled server in cfg. A server that fails
+// is logged and skipped; the joined errors are returned.
+func (p *Proxy) Start(ctx context.Context, cfg Config) error {
+	var errs []error
+	for _, name := range cfg.Names() {
+		sc := cfg.Servers[name]
+		if sc.Disabled {
+			continue
+		}
+		c, err := sc.Dial()
+		if err == nil {
+			if err = p.Mount(ctx, name, c); err != nil {
+				c.Close()
+			}
+		}
+		if err != nil {
+			logger.Warning("proxy", "upstream not mounted", "server", name, "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Mount initializes c and mirrors its tools, resources and prompts under
+// name. The proxy owns c from then on and follows its list_changed
+// notifications. Mounting a name twice replaces the first upstream.
+func (p *Proxy) Mount(ctx context.Context, name string, c *client.Client) error {
+	if err := validName(name); err != nil {
+		return err
+	}
+	p.Unmount(name)
+	u := &upstream{name: name, client: c, server: p.server, filter: p.filter}
+	c.OnNotification(u.notified)
+	info, err := c.Initialize(ctx)
+	if err != nil {
+		return fmt.Errorf("initialize: %w", err)
+	}
+	u.caps = info.Capabilities
+	if err := u.syncAll(ctx); err != nil {
+		u.unregister()
+		return err
+	}
+
+	p.mu.Lock()
+	p.upstreams[name] = u
+	p.mu.Unlock()
+	u.mu.Lock()
+	logger.Info("proxy", "mounted", "server", name, "upstream", info.ServerInfo.Name,
+		"tools", len(u.tools), "resources", len(u.resources), "prompts", len(u.prompts))
+	u.mu.Unlock()
+	go p.watch(u)
+	return nil
+}
+
+// Unmount removes an upstream's entries and closes its connection.
+func (p *Proxy) Unmount(name string) {
+	p.mu.Lock()
+	u := p.upstreams[name]
+	delete(p.upstreams, name)
+	p.mu.Unlock()
+	if u != nil {
+		u.stop()
+	}
+}
+
+// Names returns the mounted upstream names.
+func (p *Proxy) Names() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	names := make([]string, 0, len(p.upstreams))
+	for name := range p.upstreams {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Close unmounts every upstream.
+func (p *Proxy) Close() {
+	for _, name := range p.Names() {
+		p.Unmount(name)
+	}
+}
+
+// watch unmounts an upstream whose connection ends on its own, e.g. when
+// its process exits.
+func (p *Proxy) watch(u *upstream) {
+	<-u.client.Done()
+	p.mu.Lock()
+	current := p.upstreams[u.name] == u
+	if current {
+		delete(p.upstreams, u.name)
+	}
+	p.mu.Unlock()
+	if current {
+		logger.Warning("proxy", "upstream disconnected", "server", u.name, "error", u.client.Err())
+		u.unregister()
+	}
+}
+
+// upstream is one mounted server and the registry keys it owns.
+type upstream struct {
+	name      string
+	client    *client.Client
+	server    *transport.MCPServer
+	filter    Filter
+	caps      types.ServerCaps
+	mu        sync.Mutex // serializes syncs
+	tools     []string
+	resources []string
+	prompts   []string
+}
+
+// notified resyncs a list when the upstream says it changed; the local
+// server then sends its own list_changed. The sync runs on its own
+// goroutine because its replies arrive on the goroutine delivering this
+// notification.
+func (u *upstream) notified(method string, _ json.RawMessage) {
+	var resync func(context.Context) error
+	switch method {
+	case "notifications/tools/list_changed":
+		resync = u.syncTools
+	case "notifications/resources/list_changed":
+		resync = u.syncResources
+	case "notifications/prompts/list_changed":
+		resync = u.syncPrompts
+	default:
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+		defer cancel()
+		if err := resync(ctx); err != nil {
+			logger.Warning("proxy", "resync failed", "server", u.name, "notification", method, "error", err)
+		}
+	}()
+}
+
+func (u *upstream) syncAll(ctx context.Context) error {
+	if u.caps.Tools != nil {
+		if err := u.syncTools(ctx); err != nil {
+			return err
+		}
+	}
+	if u.caps.Resources != nil {
+		if err := u.syncResources(ctx); err != nil {
+			return err
+		}
+	}
+	if u.caps.Prompts != nil {
+		return u.syncPrompts(ctx)
+	}
+	return nil
+}
+
+func (u *upstream) syncTools(ctx context.Context) error {
+	defs, err := u.client.ListTools(ctx)
+	if err != nil {
+		return fmt.Errorf("list tools: %w", err)
+	}
+	tools := make([]types.Tool, len(defs))
+	for i, def := range defs {
+		tools[i] = u.tool(def)
+	}
+	if u.filter != nil {
+		tools = u.filter(toolsets.ProxyGroup(u.name), tools)
+	}
+	names := make([]string, len(tools))
+	for i, tool := range tools {
+		names[i] = tool.Definition.Name
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.server.UnregisterTools(stale(u.tools, names))
+	if len(tools) > 0 {
+		u.server.RegisterTools(tools)
+	}
+	u.tools = names
+	return nil
+}
+
+// tool mirrors an upstream tool. Arguments the schema does not declare are
+// let through, as JSON Schema allows, and left to the upstream to judge.
+// The upstream's behaviour hints are untrusted and dropped, so its tools
+// count as destructive: they need the admin scope and are left out by
+// read-only and non-destructive selections.
+func (u *upstream) tool(def types.ToolDefinition) types.Tool {
+	remote := def.Name
+	def.Name = u.name + "." + remote
+	if def.Annotations != nil && def.Annotations.Title != "" {
+		def.Annotations = &types.ToolAnnotations{Title: def.Annotations.Title}
+	} else {
+		def.Annotations = nil
+	}
+	if def.InputSchema.Type == "" {
+		def.InputSchema.Type = "object"
+	}
+	if def.InputSchema.AdditionalProperties == nil {
+		allow := true
+		def.InputSchema.AdditionalProperties = &allow
+	}
+	return types.NewContextTool(def, func(ctx context.Context, args map[string]any) (*types.ToolResult, error) {
+		return u.client.CallTool(ctx, remote, args)
+	})
+}
+
+func (u *upstream) syncResources(ctx context.Context) error {
+	defs, err := u.client.ListResources(ctx)
+	if err != nil {
+		return fmt.Errorf("list resources: %w", err)
+	}
+	templates, err := u.client.ListResourceTemplates(ctx)
+	if err != nil {
+		var rpcErr *client.Error
+		if !errors.As(err, &rpcErr) || rpcErr.Code != -32601 {
+			return fmt.Errorf("list resource templates: %w", err)
+		}
+		templates = nil // the upstream predates resource templates
+	}
+	for _, t := range templates {
+		defs = append(defs, types.ResourceDefinition{
+			URI: t.URITemplate, Name: t.Name, Title: t.Title,
+			Description: t.Description, MimeType: t.MimeType,
+		})
+	}
+	resources := make([]types.Resource, len(defs))
+	uris := make([]string, len(defs))
+	for i, def := range defs {
+		def.Name = u.name + "." + def.Name
+		def.URI = u.localURI(def.URI)
+		resources[i] = types.Resource{Definition: def, Handler: u.readResource}
+		uris[i] = def.URI
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.server.UnregisterResources(stale(u.resources, uris))
+	if len(resources) > 0 {
+		u.server.RegisterResources(resources)
+	}
+	u.resources = uris
+	return nil
+}
+
+// localURI scopes an upstream URI or template to this upstream, so two
+// upstreams, or an upstream and the local toon:// resources, never claim
+// the same URI: "gh://repo/{name}" from "github" becomes
+// "github+gh://repo/{name}".
+func (u *upstream) localURI(uri string) string {
+	return u.name + "+" + uri
+}
+
+func (u *upstream) readResource(uri string) ([]types.ResourceContent, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	contents, err := u.client.ReadResource(ctx, strings.TrimPrefix(uri, u.name+"+"))
+	for i := range contents {
+		contents[i].URI = u.localURI(contents[i].URI)
+	}
+	return contents, err
+}
+
+func (u *upstream) syncPrompts(ctx context.Context) error {
+	defs, err := u.client.ListPrompts(ctx)
+	if err != nil {
+		return fmt.Errorf("list prompts: %w", err)
+	}
+	prompts := make([]types.Prompt, len(defs))
+	names := make([]string, len(defs))
+	for i, def := range defs {
+		remote := def.Name
+		def.Name = u.name + "." + remote
+		prompts[i] = types.Prompt{Definition: def, Handler: func(args map[string]string) (string, []types.PromptMessage, error) {
+			ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+			defer cancel()
+			result, err := u.client.GetPrompt(ctx, remote, args)
+			if err != nil {
+				return "", nil, err
+			}
+			return result.Description, result.Messages, nil
+		}}
+		names[i] = def.Name
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.server.UnregisterPrompts(stale(u.prompts, names))
+	if len(prompts) > 0 {
+		u.server.RegisterPrompts(prompts)
+	}
+	u.prompts = names
+	return nil
+}
+
+// unregister removes everything the upstream registered.
+func (u *upstream) unregister() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.server.UnregisterTools(u.tools)
+	u.server.UnregisterResources(u.resources)
+	u.server.UnregisterPrompts(u.prompts)
+	u.tools, u.resources, u.prompts = nil, nil, nil
+}
+
+func (u *upstream) stop() {
+	u.unregister()
+	_ = u.client.Close()
+}
+
+// stale returns the entries of old missing from current.
+func stale(old, current []string) []string {
+	keep := make(map[string]bool, len(current))
+	for _, k := range current {
+		keep[k] = true
+	}
+	var out []string
+	for _, k := range old {
+		if !keep[k] {
+			out = append(out, k)
+		}
+	}
+	return out
+}
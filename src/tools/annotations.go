@@ -0,0 +1,39 @@
+package tools
+
+import t "github.com/orchestra-mcp/mcp/src/types"
+
+// Annotation presets. Every built-in tool works on the local workspace only,
+// so openWorldHint is always false.
+
+// readOnly marks a tool that never modifies the workspace.
+func readOnly(title string) *t.ToolAnnotations {
+	return annotate(title, true, false, false)
+}
+
+// additive marks a tool that adds data; repeating it adds more.
+func additive(title string) *t.ToolAnnotations {
+	return annotate(title, false, false, false)
+}
+
+// idempotent marks a tool whose repeated calls have no further effect.
+func idempotent(title string) *t.ToolAnnotations {
+	return annotate(title, false, false, true)
+}
+
+// destructive marks a tool that deletes or overwrites existing data.
+func destructive(title string) *t.ToolAnnotations {
+	return annotate(title, false, true, true)
+}
+
+func annotate(title string, readOnly, destructive, idempotent bool) *t.ToolAnnotations {
+	a := &t.ToolAnnotations{
+		Title:         title,
+		ReadOnlyHint:  &readOnly,
+		OpenWorldHint: new(bool),
+	}
+	if !readOnly {
+		a.DestructiveHint = &destructive
+		a.IdempotentHint = &idempotent
+	}
+	return a
+}
@@ -30,6 +30,8 @@ func savePlan(ws string) t.Tool {
 				"content":  map[string]any{"type": "string", "description": "Markdown content"},
 				"issue_id": map[string]any{"type": "string", "description": "Related issue ID"},
 			}, Required: []string{"project", "title", "content"}},
+			OutputSchema: objectOutput(map[string]any{"file": strType, "path": strType}, "file", "path"),
+			Annotations:  destructive("Save Plan"),
 		},
 		Handler: func(a map[string]any) (*t.ToolResult, error) {
 			slug := h.GetString(a, "project")
@@ -50,11 +52,19 @@ func savePlan(ws string) t.Tool {
 			if err := os.WriteFile(p, []byte(header+content), 0o644); err != nil {
 				return h.ErrorResult(err.Error()), nil
 			}
-			return h.JSONResult(map[string]any{"file": filename, "path": p}), nil
+			return h.StructuredResult(map[string]any{"file": filename, "path": p}), nil
 		},
 	}
 }
 
+// planInfo summarizes a plan document from its front matter.
+type planInfo struct {
+	File    string `json:"file"`
+	Title   string `json:"title"`
+	IssueID string `json:"issue_id,omitempty"`
+	Created string `json:"created,omitempty"`
+}
+
 func listPlans(ws string) t.Tool {
 	return t.Tool{
 		Definition: t.ToolDefinition{
@@ -62,6 +72,8 @@ func listPlans(ws string) t.Tool {
 			InputSchema: t.InputSchema{Type: "object", Properties: map[string]any{
 				"project": map[string]any{"type": "string", "description": "Project slug"},
 			}, Required: []string{"project"}},
+			OutputSchema: h.ListOutputSchema("plans", planInfo{}),
+			Annotations:  readOnly("List Plans"),
 		},
 		Handler: func(a map[string]any) (*t.ToolResult, error) {
 			slug := h.GetString(a, "project")
@@ -69,16 +81,10 @@ func listPlans(ws string) t.Tool {
 			entries, err := os.ReadDir(dir)
 			if err != nil {
 				if os.IsNotExist(err) {
-					return h.JSONResult([]any{}), nil
+					return h.ListResult("plans", []planInfo{}), nil
 				}
 				return h.ErrorResult(err.Error()), nil
 			}
-			type planInfo struct {
-				File    string `json:"file"`
-				Title   string `json:"title"`
-				IssueID string `json:"issue_id,omitempty"`
-				Created string `json:"created,omitempty"`
-			}
 			var plans []planInfo
 			for _, e := range entries {
 				if e.IsDir() || !strings.HasSuffix(e.Name(), ".md") {
@@ -113,7 +119,7 @@ func listPlans(ws string) t.Tool {
 				f.Close()
 				plans = append(plans, p)
 			}
-			return h.JSONResult(plans), nil
+			return h.ListResult("plans", plans), nil
 		},
 	}
 }
@@ -0,0 +1,61 @@
+package tools
+
+import (
+	"github.com/orchestra-mcp/mcp/src/audit"
+	h "github.com/orchestra-mcp/mcp/src/helpers"
+	t "github.com/orchestra-mcp/mcp/src/types"
+)
+
+const defaultAuditLimit = 50
+
+var auditOutput = objectOutput(map[string]any{
+	"entries": map[string]any{"type": "array", "items": map[string]any{"type": "object"}},
+}, "entries")
+
+// Audit returns the audit log query tool. The log belongs to the launch
+// workspace, so the tool is not routed by project.
+func Audit(ws string) []t.Tool {
+	return []t.Tool{queryAuditLog(ws)}
+}
+
+func queryAuditLog(ws string) t.Tool {
+	return t.Tool{
+		Definition: t.ToolDefinition{
+			Name:        "query_audit_log",
+			Description: "Query the audit log of tool calls by tool, issue, session and time range",
+			InputSchema: t.InputSchema{Type: "object", Properties: map[string]any{
+				"tool":    map[string]any{"type": "string", "description": "Tool name"},
+				"issue":   map[string]any{"type": "string", "description": "Issue ID the call touched"},
+				"session": map[string]any{"type": "string", "description": "Session ID"},
+				"since":   map[string]any{"type": "string", "description": "Start time, RFC 3339 or YYYY-MM-DD"},
+				"until":   map[string]any{"type": "string", "description": "End time, RFC 3339 or YYYY-MM-DD (the whole day)"},
+				"limit":   map[string]any{"type": "integer", "minimum": 1, "description": "Newest entries to return (default 50)"},
+			}},
+			OutputSchema: auditOutput,
+			Annotations:  readOnly("Query Audit Log"),
+		},
+		Handler: func(args map[string]any) (*t.ToolResult, error) {
+			f := audit.Filter{
+				Tool:    h.GetString(args, "tool"),
+				Issue:   h.GetString(args, "issue"),
+				Session: h.GetString(args, "session"),
+				Limit:   h.GetInt(args, "limit"),
+			}
+			if f.Limit == 0 {
+				f.Limit = defaultAuditLimit
+			}
+			var err error
+			if f.Since, err = audit.ParseTime(h.GetString(args, "since")); err != nil {
+				return h.ErrorResult(err.Error()), nil
+			}
+			if f.Until, err = audit.ParseUntil(h.GetString(args, "until")); err != nil {
+				return h.ErrorResult(err.Error()), nil
+			}
+			entries, err := audit.Query(ws, f)
+			if err != nil {
+				return h.ErrorResult(err.Error()), nil
+			}
+			return h.ListResult("entries", entries), nil
+		},
+	}
+}
@@ -28,6 +28,8 @@ func reportBug(ws string) t.Tool {
 				"expected": map[string]any{"type": "string"},
 				"actual":   map[string]any{"type": "string"},
 			}, Required: []string{"project", "story_id", "title", "severity"}},
+			OutputSchema: objectOutput(map[string]any{"id": strType, "status": strType}, "id", "status"),
+			Annotations:  additive("Report Bug"),
 		},
 		Handler: func(a map[string]any) (*t.ToolResult, error) {
 			slug := h.GetString(a, "project")
@@ -76,7 +78,7 @@ func reportBug(ws string) t.Tool {
 				ps.UpdatedAt = h.Now()
 				_ = toon.WriteFile(statusPath, &ps)
 			}
-			return h.JSONResult(map[string]any{"id": bugID, "status": "created"}), nil
+			return h.StructuredResult(map[string]any{"id": bugID, "status": "created"}), nil
 		},
 	}
 }
@@ -90,6 +92,8 @@ func logRequest(ws string) t.Tool {
 				"type":        map[string]any{"type": "string", "enum": []string{"feature", "bug", "improvement", "question"}},
 				"description": map[string]any{"type": "string"},
 			}, Required: []string{"project", "type", "description"}},
+			OutputSchema: objectOutput(map[string]any{"status": strType, "count": intType}, "status", "count"),
+			Annotations:  additive("Log Feature Request"),
 		},
 		Handler: func(a map[string]any) (*t.ToolResult, error) {
 			slug := h.GetString(a, "project")
@@ -104,7 +108,7 @@ func logRequest(ws string) t.Tool {
 			if err := toon.WriteFile(p, &log); err != nil {
 				return h.ErrorResult(err.Error()), nil
 			}
-			return h.JSONResult(map[string]any{"status": "logged", "count": len(log.Requests)}), nil
+			return h.StructuredResult(map[string]any{"status": "logged", "count": len(log.Requests)}), nil
 		},
 	}
 }
@@ -2,17 +2,22 @@ package tools
 
 import (
 	"bufio"
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/orchestra-mcp/mcp/src/bootstrap"
 	h "github.com/orchestra-mcp/mcp/src/helpers"
-	"github.com/orchestra-mcp/mcp/src/toon"
+	"github.com/orchestra-mcp/mcp/src/hooks"
 	t "github.com/orchestra-mcp/mcp/src/types"
 )
 
-const maxHookEvents = 100
+// claudeItem describes an installed skill or agent.
+type claudeItem struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
 
 // Claude returns Claude Code awareness tools.
 func Claude(ws string) []t.Tool {
@@ -27,31 +32,29 @@ func listSkills(ws string) t.Tool {
 	return t.Tool{
 		Definition: t.ToolDefinition{
 			Name: "list_skills", Description: "List available skills in the project",
-			InputSchema: t.InputSchema{Type: "object", Properties: map[string]any{}, Required: []string{}},
+			InputSchema:  t.InputSchema{Type: "object", Properties: map[string]any{}, Required: []string{}},
+			OutputSchema: h.ListOutputSchema("skills", claudeItem{}),
+			Annotations:  readOnly("List Skills"),
 		},
 		Handler: func(args map[string]any) (*t.ToolResult, error) {
 			skillsDir := filepath.Join(ws, ".claude", "skills")
 			entries, err := os.ReadDir(skillsDir)
 			if err != nil {
 				if os.IsNotExist(err) {
-					return h.JSONResult([]any{}), nil
+					return h.ListResult("skills", []claudeItem{}), nil
 				}
 				return h.ErrorResult(err.Error()), nil
 			}
-			type skillInfo struct {
-				Name        string `json:"name"`
-				Description string `json:"description"`
-			}
-			var skills []skillInfo
+			var skills []claudeItem
 			for _, e := range entries {
 				if !e.IsDir() {
 					continue
 				}
 				md := filepath.Join(skillsDir, e.Name(), "SKILL.md")
 				desc := readFirstContentLine(md)
-				skills = append(skills, skillInfo{Name: e.Name(), Description: desc})
+				skills = append(skills, claudeItem{Name: e.Name(), Description: desc})
 			}
-			return h.JSONResult(skills), nil
+			return h.ListResult("skills", skills), nil
 		},
 	}
 }
@@ -60,31 +63,29 @@ func listAgents(ws string) t.Tool {
 	return t.Tool{
 		Definition: t.ToolDefinition{
 			Name: "list_agents", Description: "List available agents in the project",
-			InputSchema: t.InputSchema{Type: "object", Properties: map[string]any{}, Required: []string{}},
+			InputSchema:  t.InputSchema{Type: "object", Properties: map[string]any{}, Required: []string{}},
+			OutputSchema: h.ListOutputSchema("agents", claudeItem{}),
+			Annotations:  readOnly("List Agents"),
 		},
 		Handler: func(args map[string]any) (*t.ToolResult, error) {
 			agentsDir := filepath.Join(ws, ".claude", "agents")
 			entries, err := os.ReadDir(agentsDir)
 			if err != nil {
 				if os.IsNotExist(err) {
-					return h.JSONResult([]any{}), nil
+					return h.ListResult("agents", []claudeItem{}), nil
 				}
 				return h.ErrorResult(err.Error()), nil
 			}
-			type agentInfo struct {
-				Name        string `json:"name"`
-				Description string `json:"description"`
-			}
-			var agents []agentInfo
+			var agents []claudeItem
 			for _, e := range entries {
 				if e.IsDir() || !strings.HasSuffix(e.Name(), ".md") {
 					continue
 				}
 				name := strings.TrimSuffix(e.Name(), ".md")
 				desc := readFirstContentLine(filepath.Join(agentsDir, e.Name()))
-				agents = append(agents, agentInfo{Name: name, Description: desc})
+				agents = append(agents, claudeItem{Name: name, Description: desc})
 			}
-			return h.JSONResult(agents), nil
+			return h.ListResult("agents", agents), nil
 		},
 	}
 }
@@ -100,6 +101,8 @@ func receiveHookEvent(ws string) t.Tool {
 				"agent_type": map[string]any{"type": "string"},
 				"data":       map[string]any{"type": "object"},
 			}, Required: []string{"event_type"}},
+			OutputSchema: objectOutput(map[string]any{"stored": boolType, "event_type": strType}, "stored", "event_type"),
+			Annotations:  additive("Receive Hook Event"),
 		},
 		Handler: func(args map[string]any) (*t.ToolResult, error) {
 			event := t.HookEvent{
@@ -112,17 +115,10 @@ func receiveHookEvent(ws string) t.Tool {
 			if d, ok := args["data"].(map[string]any); ok {
 				event.Data = d
 			}
-			eventsDir := filepath.Join(ws, ".projects", ".events")
-			_ = os.MkdirAll(eventsDir, 0o755)
-			logPath := filepath.Join(eventsDir, "hook-events.toon")
-			var log t.HookEventLog
-			_ = toon.ParseFile(logPath, &log) // ignore if not exists
-			log.Events = append(log.Events, event)
-			if len(log.Events) > maxHookEvents {
-				log.Events = log.Events[len(log.Events)-maxHookEvents:]
+			if err := hooks.Append(ws, event); err != nil {
+				return h.ErrorResult(err.Error()), nil
 			}
-			_ = toon.WriteFile(logPath, &log)
-			return h.JSONResult(map[string]any{"stored": true, "event_type": event.EventType}), nil
+			return h.StructuredResult(map[string]any{"stored": true, "event_type": event.EventType}), nil
 		},
 	}
 }
@@ -135,12 +131,11 @@ func getHookEvents(ws string) t.Tool {
 				"event_type": map[string]any{"type": "string", "description": "Filter by event type"},
 				"limit":      map[string]any{"type": "number", "description": "Max events to return"},
 			}, Required: []string{}},
+			OutputSchema: h.ListOutputSchema("events", t.HookEvent{}),
+			Annotations:  readOnly("Get Hook Events"),
 		},
 		Handler: func(args map[string]any) (*t.ToolResult, error) {
-			logPath := filepath.Join(ws, ".projects", ".events", "hook-events.toon")
-			var log t.HookEventLog
-			_ = toon.ParseFile(logPath, &log) // ignore error: file may not exist yet
-			events := log.Events
+			events := hooks.Read(ws)
 			if typeFilter := h.GetString(args, "event_type"); typeFilter != "" {
 				var filtered []t.HookEvent
 				for _, e := range events {
@@ -154,62 +149,72 @@ func getHookEvents(ws string) t.Tool {
 			if limit > 0 && len(events) > limit {
 				events = events[len(events)-limit:]
 			}
-			return h.JSONResult(events), nil
+			return h.ListResult("events", events), nil
 		},
 	}
 }
 
 func installSkills(ws string) t.Tool {
-	return t.Tool{
-		Definition: t.ToolDefinition{
+	return t.NewContextTool(
+		t.ToolDefinition{
 			Name: "install_skills", Description: "Install bundled skills to project",
 			InputSchema: t.InputSchema{Type: "object", Properties: map[string]any{
 				"names": map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Skill names to install (empty = all)"},
 			}, Required: []string{}},
+			OutputSchema: installOutput,
+			Annotations:  destructive("Install Skills"),
 		},
-		Handler: func(args map[string]any) (*t.ToolResult, error) {
+		func(ctx context.Context, args map[string]any) (*t.ToolResult, error) {
 			target := filepath.Join(ws, ".claude", "skills")
-			count, err := bootstrap.InstallSkills(target)
+			count, err := bootstrap.InstallSkillsProgress(target, func(done, total int) {
+				h.ReportProgress(ctx, float64(done), float64(total), "")
+			})
 			if err != nil {
 				return h.ErrorResult(err.Error()), nil
 			}
-			return h.JSONResult(map[string]any{
+			return h.StructuredResult(map[string]any{
 				"installed": count, "available": bootstrap.ListBundledSkills(),
 			}), nil
 		},
-	}
+	)
 }
 
 func installAgents(ws string) t.Tool {
-	return t.Tool{
-		Definition: t.ToolDefinition{
+	return t.NewContextTool(
+		t.ToolDefinition{
 			Name: "install_agents", Description: "Install bundled agents to project",
 			InputSchema: t.InputSchema{Type: "object", Properties: map[string]any{
 				"names": map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Agent names to install (empty = all)"},
 			}, Required: []string{}},
+			OutputSchema: installOutput,
+			Annotations:  destructive("Install Agents"),
 		},
-		Handler: func(args map[string]any) (*t.ToolResult, error) {
+		func(ctx context.Context, args map[string]any) (*t.ToolResult, error) {
 			target := filepath.Join(ws, ".claude", "agents")
-			count, err := bootstrap.InstallAgents(target)
+			count, err := bootstrap.InstallAgentsProgress(target, func(done, total int) {
+				h.ReportProgress(ctx, float64(done), float64(total), "")
+			})
 			if err != nil {
 				return h.ErrorResult(err.Error()), nil
 			}
-			return h.JSONResult(map[string]any{
+			return h.StructuredResult(map[string]any{
 				"installed": count, "available": bootstrap.ListBundledAgents(),
 			}), nil
 		},
-	}
+	)
 }
 
 func installDocsTool(ws string) t.Tool {
 	return t.Tool{
 		Definition: t.ToolDefinition{
 			Name: "install_docs", Description: "Install CLAUDE.md, AGENTS.md, CONTEXT.md to project root",
-			InputSchema: t.InputSchema{Type: "object", Properties: map[string]any{}, Required: []string{}},
+			InputSchema:  t.InputSchema{Type: "object", Properties: map[string]any{}, Required: []string{}},
+			OutputSchema: objectOutput(map[string]any{"installed": intType, "files": strListType}, "installed", "files"),
+			Annotations:  idempotent("Install Docs"),
 		},
 		Handler: func(args map[string]any) (*t.ToolResult, error) {
 			count := bootstrap.InstallDocs(ws)
-			return h.JSONResult(map[string]any{
+			return h.StructuredResult(map[string]any{
 				"installed": count,
 				"files":     []string{"CLAUDE.md", "AGENTS.md", "CONTEXT.md"},
 			}), nil
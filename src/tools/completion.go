@@ -0,0 +1,72 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	h "github.com/orchestra-mcp/mcp/src/helpers"
+	t "github.com/orchestra-mcp/mcp/src/types"
+)
+
+// completeIDs suggests project slugs and issue IDs from the .projects layout.
+// It understands both prompt argument names (project, epic_id, story_id,
+// task_id) and resource template variables (slug, epicId, storyId, taskId).
+// Each level needs its parents among the already resolved arguments, which
+// the client supplies and must be a slug and issue IDs before they name a
+// directory.
+func completeIDs(ws string) t.CompletionFunc {
+	return func(name string, args map[string]string) []string {
+		project := firstArg(args, "project", "slug")
+		epicID := firstArg(args, "epic_id", "epicId")
+		storyID := firstArg(args, "story_id", "storyId")
+		if project != "" && !h.IsSlug(project) || epicID != "" && !h.IsIssueID(epicID) || storyID != "" && !h.IsIssueID(storyID) {
+			return nil
+		}
+		epicsDir := filepath.Join(h.ProjectDir(ws, project), "epics")
+		switch name {
+		case "project", "slug":
+			return h.ProjectSlugs(ws)
+		case "epic_id", "epicId":
+			if project == "" {
+				return nil
+			}
+			return entryNames(epicsDir, true, "")
+		case "story_id", "storyId":
+			if project == "" || epicID == "" {
+				return nil
+			}
+			return entryNames(filepath.Join(epicsDir, epicID, "stories"), true, "")
+		case "task_id", "taskId":
+			if project == "" || epicID == "" || storyID == "" {
+				return nil
+			}
+			return entryNames(filepath.Join(epicsDir, epicID, "stories", storyID, "tasks"), false, ".toon")
+		}
+		return nil
+	}
+}
+
+func firstArg(args map[string]string, names ...string) string {
+	for _, n := range names {
+		if v := args[n]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// entryNames lists subdirectories of dir, or files with suffix (trimmed).
+func entryNames(dir string, dirs bool, suffix string) []string {
+	entries, _ := os.ReadDir(dir) // missing dir: no suggestions
+	var names []string
+	for _, e := range entries {
+		switch {
+		case dirs && e.IsDir():
+			names = append(names, e.Name())
+		case !dirs && !e.IsDir() && strings.HasSuffix(e.Name(), suffix):
+			names = append(names, strings.TrimSuffix(e.Name(), suffix))
+		}
+	}
+	return names
+}
@@ -22,13 +22,15 @@ func listEpics(ws string) t.Tool {
 			InputSchema: t.InputSchema{Type: "object", Properties: map[string]any{
 				"project": map[string]any{"type": "string", "description": "Project slug"},
 			}, Required: []string{"project"}},
+			OutputSchema: h.ListOutputSchema("epics", t.IssueData{}),
+			Annotations:  readOnly("List Epics"),
 		},
 		Handler: func(args map[string]any) (*t.ToolResult, error) {
 			epicsDir := filepath.Join(h.ProjectDir(ws, h.GetString(args, "project")), "epics")
 			entries, err := os.ReadDir(epicsDir)
 			if err != nil {
 				if os.IsNotExist(err) {
-					return h.JSONResult([]any{}), nil
+					return h.ListResult("epics", []t.IssueData{}), nil
 				}
 				return h.ErrorResult(err.Error()), nil
 			}
@@ -42,7 +44,7 @@ func listEpics(ws string) t.Tool {
 					epics = append(epics, issue)
 				}
 			}
-			return h.JSONResult(epics), nil
+			return h.ListResult("epics", epics), nil
 		},
 	}
 }
@@ -57,6 +59,8 @@ func createEpic(ws string) t.Tool {
 				"description": map[string]any{"type": "string"},
 				"priority":    map[string]any{"type": "string", "enum": []string{"low", "medium", "high", "critical"}},
 			}, Required: []string{"project", "title"}},
+			OutputSchema: issueOutput,
+			Annotations:  additive("Create Epic"),
 		},
 		Handler: func(args map[string]any) (*t.ToolResult, error) {
 			slug := h.GetString(args, "project")
@@ -83,7 +87,7 @@ func createEpic(ws string) t.Tool {
 			h.UpdateProjectStatus(&ps, issue)
 			ps.UpdatedAt = h.Now()
 			_ = toon.WriteFile(statusPath, &ps)
-			return h.JSONResult(issue), nil
+			return h.StructuredResult(issue), nil
 		},
 	}
 }
@@ -96,6 +100,8 @@ func getEpic(ws string) t.Tool {
 				"project": map[string]any{"type": "string"},
 				"epic_id": map[string]any{"type": "string"},
 			}, Required: []string{"project", "epic_id"}},
+			OutputSchema: issueOutput,
+			Annotations:  readOnly("Get Epic"),
 		},
 		Handler: func(args map[string]any) (*t.ToolResult, error) {
 			var issue t.IssueData
@@ -103,7 +109,7 @@ func getEpic(ws string) t.Tool {
 			if err := toon.ParseFile(p, &issue); err != nil {
 				return h.ErrorResult(err.Error()), nil
 			}
-			return h.JSONResult(issue), nil
+			return h.StructuredResult(issue), nil
 		},
 	}
 }
@@ -117,6 +123,8 @@ func updateEpic(ws string) t.Tool {
 				"title": map[string]any{"type": "string"}, "description": map[string]any{"type": "string"},
 				"status": map[string]any{"type": "string"}, "priority": map[string]any{"type": "string"},
 			}, Required: []string{"project", "epic_id"}},
+			OutputSchema: issueOutput,
+			Annotations:  idempotent("Update Epic"),
 		},
 		Handler: func(args map[string]any) (*t.ToolResult, error) {
 			slug := h.GetString(args, "project")
@@ -149,7 +157,7 @@ func updateEpic(ws string) t.Tool {
 				ps.UpdatedAt = h.Now()
 				_ = toon.WriteFile(statusPath, &ps)
 			}
-			return h.JSONResult(issue), nil
+			return h.StructuredResult(issue), nil
 		},
 	}
 }
@@ -162,6 +170,8 @@ func deleteEpic(ws string) t.Tool {
 				"project": map[string]any{"type": "string"},
 				"epic_id": map[string]any{"type": "string"},
 			}, Required: []string{"project", "epic_id"}},
+			OutputSchema: messageOutput,
+			Annotations:  destructive("Delete Epic"),
 		},
 		Handler: func(args map[string]any) (*t.ToolResult, error) {
 			slug := h.GetString(args, "project")
@@ -183,7 +193,7 @@ func deleteEpic(ws string) t.Tool {
 				ps.UpdatedAt = h.Now()
 				_ = toon.WriteFile(statusPath, &ps)
 			}
-			return h.TextResult(fmt.Sprintf("deleted epic %s", epicID)), nil
+			return h.MessageResult(fmt.Sprintf("deleted epic %s", epicID)), nil
 		},
 	}
 }
@@ -45,6 +45,11 @@ func advanceTask(ws string) t.Tool {
 				"task_id":  map[string]any{"type": "string"},
 				"evidence": map[string]any{"type": "string", "description": "Required for gated transitions. Describe tests run, docs written, or review findings."},
 			}, Required: []string{"project", "epic_id", "story_id", "task_id"}},
+			OutputSchema: objectOutput(map[string]any{
+				"task": issueType, "from": strType, "to": strType,
+				"evidence": strType, "gate": strType,
+			}, "task", "from", "to"),
+			Annotations: additive("Advance Task"),
 		},
 		Handler: func(args map[string]any) (*t.ToolResult, error) {
 			slug := h.GetString(args, "project")
@@ -85,7 +90,7 @@ func advanceTask(ws string) t.Tool {
 			if hint, ok := gateHints[next]; ok {
 				result["gate"] = hint
 			}
-			return h.JSONResult(result), nil
+			return h.StructuredResult(result), nil
 		},
 	}
 }
@@ -99,6 +104,8 @@ func rejectTask(ws string) t.Tool {
 				"story_id": map[string]any{"type": "string"}, "task_id": map[string]any{"type": "string"},
 				"reason": map[string]any{"type": "string", "description": "Rejection reason"},
 			}, Required: []string{"project", "epic_id", "story_id", "task_id"}},
+			OutputSchema: objectOutput(map[string]any{"rejected": issueType, "bug_created": issueType}, "rejected", "bug_created"),
+			Annotations:  additive("Reject Task"),
 		},
 		Handler: func(args map[string]any) (*t.ToolResult, error) {
 			slug := h.GetString(args, "project")
@@ -128,7 +135,7 @@ func rejectTask(ws string) t.Tool {
 				return h.ErrorResult(err.Error()), nil
 			}
 			cascadeParents(projDir, epicID, storyID, taskID, task)
-			return h.JSONResult(map[string]any{
+			return h.StructuredResult(map[string]any{
 				"rejected": task, "bug_created": bug,
 			}), nil
 		},
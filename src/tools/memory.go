@@ -1,6 +1,7 @@
 package tools
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -32,8 +33,8 @@ func sessionsDir(ws, slug string) string {
 }
 
 func saveMemory(ws string, bridge *engine.Bridge) t.Tool {
-	return t.Tool{
-		Definition: t.ToolDefinition{
+	return t.NewContextTool(
+		t.ToolDefinition{
 			Name: "save_memory", Description: "Save a context chunk to project memory",
 			InputSchema: t.InputSchema{Type: "object", Properties: map[string]any{
 				"project":   map[string]any{"type": "string"},
@@ -43,36 +44,42 @@ func saveMemory(ws string, bridge *engine.Bridge) t.Tool {
 				"source_id": map[string]any{"type": "string", "description": "Source ID (task ID, session ID)"},
 				"tags":      map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
 			}, Required: []string{"project", "content", "summary"}},
+			OutputSchema: h.OutputSchemaOf(t.MemoryChunk{}),
+			Annotations:  additive("Save Memory"),
 		},
-		Handler: func(args map[string]any) (*t.ToolResult, error) {
+		func(ctx context.Context, args map[string]any) (*t.ToolResult, error) {
 			slug := h.GetString(args, "project")
 			tags := extractTags(args)
 
 			if bridge.UsingEngine() {
-				resp, err := bridge.Client.StoreChunk(slug, h.GetString(args, "source"),
+				resp, err := bridge.Client.StoreChunk(ctx, slug, h.GetString(args, "source"),
 					h.GetString(args, "source_id"), h.GetString(args, "summary"),
 					h.GetString(args, "content"), tags)
 				if err == nil {
-					return h.JSONResult(resp.Chunk), nil
+					return h.StructuredResult(fromChunk(resp.Chunk)), nil
 				}
 				logFallback("save_memory", err)
+			} else {
+				logFallback("save_memory", nil)
 			}
-			return toonSaveMemory(ws, slug, args, tags)
+			return traceToon(ctx, "save_memory", func() (*t.ToolResult, error) { return toonSaveMemory(ws, slug, args, tags) })
 		},
-	}
+	)
 }
 
 func searchMemory(ws string, bridge *engine.Bridge) t.Tool {
-	return t.Tool{
-		Definition: t.ToolDefinition{
+	return t.NewContextTool(
+		t.ToolDefinition{
 			Name: "search_memory", Description: "Search project memory by keyword",
 			InputSchema: t.InputSchema{Type: "object", Properties: map[string]any{
 				"project": map[string]any{"type": "string"},
 				"query":   map[string]any{"type": "string"},
 				"limit":   map[string]any{"type": "number"},
 			}, Required: []string{"project", "query"}},
+			OutputSchema: h.ListOutputSchema("results", searchResult{}),
+			Annotations:  readOnly("Search Memory"),
 		},
-		Handler: func(args map[string]any) (*t.ToolResult, error) {
+		func(ctx context.Context, args map[string]any) (*t.ToolResult, error) {
 			slug := h.GetString(args, "project")
 			query := h.GetString(args, "query")
 			limit := h.GetInt(args, "limit")
@@ -81,28 +88,36 @@ func searchMemory(ws string, bridge *engine.Bridge) t.Tool {
 			}
 
 			if bridge.UsingEngine() {
-				resp, err := bridge.Client.SearchMemory(slug, query, int32(limit))
+				resp, err := bridge.Client.SearchMemory(ctx, slug, query, int32(limit))
 				if err == nil {
-					return h.JSONResult(resp.Results), nil
+					results := make([]searchResult, len(resp.Results))
+					for i, c := range resp.Results {
+						results[i] = searchResult{Chunk: fromChunk(c), Score: float64(c.GetScore())}
+					}
+					return h.ListResult("results", results), nil
 				}
 				logFallback("search_memory", err)
+			} else {
+				logFallback("search_memory", nil)
 			}
-			return toonSearchMemory(ws, slug, query, limit)
+			return traceToon(ctx, "search_memory", func() (*t.ToolResult, error) { return toonSearchMemory(ws, slug, query, limit) })
 		},
-	}
+	)
 }
 
 func getContext(ws string, bridge *engine.Bridge) t.Tool {
-	return t.Tool{
-		Definition: t.ToolDefinition{
+	return t.NewContextTool(
+		t.ToolDefinition{
 			Name: "get_context", Description: "Get relevant context for current work",
 			InputSchema: t.InputSchema{Type: "object", Properties: map[string]any{
 				"project": map[string]any{"type": "string"},
 				"query":   map[string]any{"type": "string", "description": "What context do you need?"},
 				"limit":   map[string]any{"type": "number"},
 			}, Required: []string{"project", "query"}},
+			OutputSchema: h.ListOutputSchema("items", contextItem{}),
+			Annotations:  readOnly("Get Memory Context"),
 		},
-		Handler: func(args map[string]any) (*t.ToolResult, error) {
+		func(ctx context.Context, args map[string]any) (*t.ToolResult, error) {
 			slug := h.GetString(args, "project")
 			query := h.GetString(args, "query")
 			limit := h.GetInt(args, "limit")
@@ -111,29 +126,44 @@ func getContext(ws string, bridge *engine.Bridge) t.Tool {
 			}
 
 			if bridge.UsingEngine() {
-				resp, err := bridge.Client.GetContext(slug, query, int32(limit))
+				resp, err := bridge.Client.GetContext(ctx, slug, query, int32(limit))
 				if err == nil {
-					return h.JSONResult(resp.Chunks), nil
+					items := make([]contextItem, len(resp.Chunks))
+					for i, c := range resp.Chunks {
+						items[i] = contextItem{Type: "memory", Summary: c.GetSummary(), Content: c.GetContent(), Score: float64(c.GetScore())}
+					}
+					return h.ListResult("items", items), nil
 				}
 				logFallback("get_context", err)
+			} else {
+				logFallback("get_context", nil)
 			}
-			return toonGetContext(ws, slug, query, limit)
+			return traceToon(ctx, "get_context", func() (*t.ToolResult, error) { return toonGetContext(ws, slug, query, limit) })
 		},
-	}
+	)
 }
 
 func saveSession(ws string, bridge *engine.Bridge) t.Tool {
-	return t.Tool{
-		Definition: t.ToolDefinition{
+	return t.NewContextTool(
+		t.ToolDefinition{
 			Name: "save_session", Description: "Save a session summary to project memory",
 			InputSchema: t.InputSchema{Type: "object", Properties: map[string]any{
 				"project":    map[string]any{"type": "string"},
 				"session_id": map[string]any{"type": "string"},
 				"summary":    map[string]any{"type": "string"},
-				"events":     map[string]any{"type": "array", "items": map[string]any{"type": "object"}},
+				"events": map[string]any{"type": "array", "items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"type":    map[string]any{"type": "string", "description": "tool_call, decision or output"},
+						"summary": map[string]any{"type": "string"},
+					},
+					"required": []string{"type", "summary"},
+				}},
 			}, Required: []string{"project", "session_id", "summary"}},
+			OutputSchema: h.OutputSchemaOf(t.SessionLog{}),
+			Annotations:  additive("Save Session"),
 		},
-		Handler: func(args map[string]any) (*t.ToolResult, error) {
+		func(ctx context.Context, args map[string]any) (*t.ToolResult, error) {
 			slug := h.GetString(args, "project")
 			sessionID := h.GetString(args, "session_id")
 			summary := h.GetString(args, "summary")
@@ -151,27 +181,31 @@ func saveSession(ws string, bridge *engine.Bridge) t.Tool {
 						}
 					}
 				}
-				resp, err := bridge.Client.StoreSession(slug, sessionID, summary, events)
+				resp, err := bridge.Client.StoreSession(ctx, slug, sessionID, summary, events)
 				if err == nil {
-					return h.JSONResult(resp.Session), nil
+					return h.StructuredResult(fromSession(resp.Session)), nil
 				}
 				logFallback("save_session", err)
+			} else {
+				logFallback("save_session", nil)
 			}
-			return toonSaveSession(ws, slug, sessionID, summary, args)
+			return traceToon(ctx, "save_session", func() (*t.ToolResult, error) { return toonSaveSession(ws, slug, sessionID, summary, args) })
 		},
-	}
+	)
 }
 
 func listSessions(ws string, bridge *engine.Bridge) t.Tool {
-	return t.Tool{
-		Definition: t.ToolDefinition{
+	return t.NewContextTool(
+		t.ToolDefinition{
 			Name: "list_sessions", Description: "List recent sessions for a project",
 			InputSchema: t.InputSchema{Type: "object", Properties: map[string]any{
 				"project": map[string]any{"type": "string"},
 				"limit":   map[string]any{"type": "number"},
 			}, Required: []string{"project"}},
+			OutputSchema: h.ListOutputSchema("sessions", t.SessionLog{}),
+			Annotations:  readOnly("List Sessions"),
 		},
-		Handler: func(args map[string]any) (*t.ToolResult, error) {
+		func(ctx context.Context, args map[string]any) (*t.ToolResult, error) {
 			slug := h.GetString(args, "project")
 			limit := h.GetInt(args, "limit")
 			if limit <= 0 {
@@ -179,44 +213,89 @@ func listSessions(ws string, bridge *engine.Bridge) t.Tool {
 			}
 
 			if bridge.UsingEngine() {
-				resp, err := bridge.Client.ListSessions(slug, int32(limit))
+				resp, err := bridge.Client.ListSessions(ctx, slug, int32(limit))
 				if err == nil {
-					return h.JSONResult(resp.Sessions), nil
+					sessions := make([]t.SessionLog, len(resp.Sessions))
+					for i, s := range resp.Sessions {
+						sessions[i] = fromSession(s)
+					}
+					return h.ListResult("sessions", sessions), nil
 				}
 				logFallback("list_sessions", err)
+			} else {
+				logFallback("list_sessions", nil)
 			}
-			return toonListSessions(ws, slug, limit)
+			return traceToon(ctx, "list_sessions", func() (*t.ToolResult, error) { return toonListSessions(ws, slug, limit) })
 		},
-	}
+	)
 }
 
 func getSession(ws string, bridge *engine.Bridge) t.Tool {
-	return t.Tool{
-		Definition: t.ToolDefinition{
+	return t.NewContextTool(
+		t.ToolDefinition{
 			Name: "get_session", Description: "Get full session details",
 			InputSchema: t.InputSchema{Type: "object", Properties: map[string]any{
 				"project":    map[string]any{"type": "string"},
 				"session_id": map[string]any{"type": "string"},
 			}, Required: []string{"project", "session_id"}},
+			OutputSchema: h.OutputSchemaOf(t.SessionLog{}),
+			Annotations:  readOnly("Get Session"),
 		},
-		Handler: func(args map[string]any) (*t.ToolResult, error) {
+		func(ctx context.Context, args map[string]any) (*t.ToolResult, error) {
 			slug := h.GetString(args, "project")
 			sessionID := h.GetString(args, "session_id")
 
 			if bridge.UsingEngine() {
-				resp, err := bridge.Client.GetSession(slug, sessionID)
+				resp, err := bridge.Client.GetSession(ctx, slug, sessionID)
 				if err == nil {
-					return h.JSONResult(resp.Session), nil
+					return h.StructuredResult(fromSession(resp.Session)), nil
 				}
 				logFallback("get_session", err)
+			} else {
+				logFallback("get_session", nil)
 			}
-			return toonGetSession(ws, slug, sessionID)
+			return traceToon(ctx, "get_session", func() (*t.ToolResult, error) { return toonGetSession(ws, slug, sessionID) })
 		},
-	}
+	)
 }
 
 // --- Helpers ---
 
+// searchResult is one search_memory hit.
+type searchResult struct {
+	Chunk t.MemoryChunk `json:"chunk"`
+	Score float64       `json:"score"`
+}
+
+// contextItem is one get_context entry, from a memory chunk or a session.
+type contextItem struct {
+	Type    string  `json:"type"` // memory or session
+	Summary string  `json:"summary"`
+	Content string  `json:"content"`
+	Score   float64 `json:"score"`
+}
+
+// fromChunk converts an engine chunk to the shape the TOON fallback returns.
+func fromChunk(c *pb.MemoryChunk) t.MemoryChunk {
+	return t.MemoryChunk{
+		ID: c.GetId(), Project: c.GetProject(), Source: c.GetSource(), SourceID: c.GetSourceId(),
+		Summary: c.GetSummary(), Content: c.GetContent(), Tags: c.GetTags(), CreatedAt: c.GetCreatedAt(),
+	}
+}
+
+// fromSession converts an engine session to the shape the TOON fallback
+// returns.
+func fromSession(s *pb.SessionLog) t.SessionLog {
+	out := t.SessionLog{
+		SessionID: s.GetSessionId(), Project: s.GetProject(), Summary: s.GetSummary(),
+		StartedAt: s.GetStartedAt(), EndedAt: s.GetEndedAt(),
+	}
+	for _, e := range s.GetEvents() {
+		out.Events = append(out.Events, t.SessionEvent{Type: e.GetType(), Summary: e.GetSummary(), Timestamp: e.GetTimestamp()})
+	}
+	return out
+}
+
 func extractTags(args map[string]any) []string {
 	var tags []string
 	if raw, ok := args["tags"].([]any); ok {
@@ -1,6 +1,7 @@
 package tools
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -8,12 +9,31 @@ import (
 	"strings"
 
 	h "github.com/orchestra-mcp/mcp/src/helpers"
+	"github.com/orchestra-mcp/mcp/src/logger"
+	"github.com/orchestra-mcp/mcp/src/metrics"
 	"github.com/orchestra-mcp/mcp/src/toon"
+	"github.com/orchestra-mcp/mcp/src/tracing"
 	t "github.com/orchestra-mcp/mcp/src/types"
 )
 
+// logFallback records that tool was served from TOON. err is the engine
+// error, or nil when no engine is running.
 func logFallback(tool string, err error) {
-	fmt.Fprintf(os.Stderr, "[memory] %s gRPC failed, TOON fallback: %v\n", tool, err)
+	if err == nil {
+		metrics.BridgeFallbacks.Inc(tool, "engine_down")
+		return
+	}
+	metrics.BridgeFallbacks.Inc(tool, "engine_error")
+	logger.Warning("memory", tool+" gRPC failed, using TOON fallback", "error", err)
+}
+
+// traceToon runs a TOON fallback inside its own span, so traces separate
+// it from the failed gRPC call.
+func traceToon(ctx context.Context, tool string, fn func() (*t.ToolResult, error)) (*t.ToolResult, error) {
+	_, span := tracing.Start(ctx, "toon "+tool)
+	result, err := fn()
+	tracing.End(span, err)
+	return result, err
 }
 
 // --- TOON fallback implementations ---
@@ -34,30 +54,26 @@ func toonSaveMemory(ws, slug string, args map[string]any, tags []string) (*t.Too
 	if err := toon.WriteFile(cp, &idx); err != nil {
 		return h.ErrorResult(err.Error()), nil
 	}
-	return h.JSONResult(chunk), nil
+	return h.StructuredResult(chunk), nil
 }
 
 func toonSearchMemory(ws, slug, query string, limit int) (*t.ToolResult, error) {
 	query = strings.ToLower(query)
 	var idx t.MemoryIndex
 	_ = toon.ParseFile(chunksPath(ws, slug), &idx) // ignore error: file may not exist yet
-	type scored struct {
-		Chunk t.MemoryChunk `json:"chunk"`
-		Score float64       `json:"score"`
-	}
-	var results []scored
+	var results []searchResult
 	for _, c := range idx.Chunks {
 		text := strings.ToLower(c.Summary + " " + c.Content + " " + strings.Join(c.Tags, " "))
 		score := keywordScore(text, query)
 		if score > 0 {
-			results = append(results, scored{Chunk: c, Score: score})
+			results = append(results, searchResult{Chunk: c, Score: score})
 		}
 	}
 	sort.Slice(results, func(i, j int) bool { return results[i].Score > results[j].Score })
 	if len(results) > limit {
 		results = results[:limit]
 	}
-	return h.JSONResult(results), nil
+	return h.ListResult("results", results), nil
 }
 
 func toonGetContext(ws, slug, query string, limit int) (*t.ToolResult, error) {
@@ -68,12 +84,6 @@ func toonGetContext(ws, slug, query string, limit int) (*t.ToolResult, error) {
 	sp := filepath.Join(sessionsDir(ws, slug), "index.toon")
 	_ = toon.ParseFile(sp, &sessions)
 
-	type contextItem struct {
-		Type    string  `json:"type"`
-		Summary string  `json:"summary"`
-		Content string  `json:"content"`
-		Score   float64 `json:"score"`
-	}
 	var items []contextItem
 	for _, c := range idx.Chunks {
 		text := strings.ToLower(c.Summary + " " + c.Content + " " + strings.Join(c.Tags, " "))
@@ -93,7 +103,7 @@ func toonGetContext(ws, slug, query string, limit int) (*t.ToolResult, error) {
 	if len(items) > limit {
 		items = items[:limit]
 	}
-	return h.JSONResult(items), nil
+	return h.ListResult("items", items), nil
 }
 
 func toonSaveSession(ws, slug, sessionID, summary string, args map[string]any) (*t.ToolResult, error) {
@@ -116,7 +126,7 @@ func toonSaveSession(ws, slug, sessionID, summary string, args map[string]any) (
 	_ = toon.ParseFile(indexPath, &idx)
 	idx.Sessions = append(idx.Sessions, session)
 	_ = toon.WriteFile(indexPath, &idx)
-	return h.JSONResult(session), nil
+	return h.StructuredResult(session), nil
 }
 
 func toonListSessions(ws, slug string, limit int) (*t.ToolResult, error) {
@@ -127,7 +137,7 @@ func toonListSessions(ws, slug string, limit int) (*t.ToolResult, error) {
 	if len(sessions) > limit {
 		sessions = sessions[len(sessions)-limit:]
 	}
-	return h.JSONResult(sessions), nil
+	return h.ListResult("sessions", sessions), nil
 }
 
 func toonGetSession(ws, slug, sessionID string) (*t.ToolResult, error) {
@@ -136,5 +146,5 @@ func toonGetSession(ws, slug, sessionID string) (*t.ToolResult, error) {
 	if err := toon.ParseFile(sessionPath, &session); err != nil {
 		return h.ErrorResult(err.Error()), nil
 	}
-	return h.JSONResult(session), nil
+	return h.StructuredResult(session), nil
 }
@@ -1,28 +1,21 @@
 package tools
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"strings"
 
 	h "github.com/orchestra-mcp/mcp/src/helpers"
+	"github.com/orchestra-mcp/mcp/src/logger"
 	t "github.com/orchestra-mcp/mcp/src/types"
 )
 
 // Prd returns all PRD management tools.
 func Prd(ws string) []t.Tool {
 	return []t.Tool{
-		{Definition: t.ToolDefinition{Name: "start_prd_session", Description: "Start guided PRD creation", InputSchema: sp()}, Handler: func(a map[string]any) (*t.ToolResult, error) {
-			slug := h.GetString(a, "project")
-			if !h.FileExists(h.ProjectDir(ws, slug)) {
-				return h.ErrorResult("project not found"), nil
-			}
-			s := &t.PrdSession{Slug: slug, ProjectName: slug, Status: "in_progress"}
-			if err := savePrd(ws, s); err != nil {
-				return h.ErrorResult(err.Error()), nil
-			}
-			return h.JSONResult(nextQ(s)), nil
-		}},
-		{Definition: t.ToolDefinition{Name: "answer_prd_question", Description: "Answer current PRD question", InputSchema: t.InputSchema{Type: "object", Properties: map[string]any{"project": map[string]any{"type": "string"}, "answer": map[string]any{"type": "string"}}, Required: []string{"project", "answer"}}}, Handler: func(a map[string]any) (*t.ToolResult, error) {
+		startPrd(ws),
+		{Definition: t.ToolDefinition{Name: "answer_prd_question", Description: "Answer current PRD question", InputSchema: t.InputSchema{Type: "object", Properties: map[string]any{"project": map[string]any{"type": "string"}, "answer": map[string]any{"type": "string"}}, Required: []string{"project", "answer"}}, OutputSchema: prdStepOutput, Annotations: additive("Answer PRD Question")}, Handler: func(a map[string]any) (*t.ToolResult, error) {
 			s, err := loadPrd(ws, h.GetString(a, "project"))
 			if err != nil {
 				return h.ErrorResult(err.Error()), nil
@@ -31,18 +24,18 @@ func Prd(ws string) []t.Tool {
 			s.Answers = append(s.Answers, t.PrdAnswer{Question: q.Key, Answer: h.GetString(a, "answer")})
 			return advancePrd(ws, s), nil
 		}},
-		{Definition: t.ToolDefinition{Name: "get_prd_session", Description: "Get PRD session state", InputSchema: sp()}, Handler: func(a map[string]any) (*t.ToolResult, error) {
+		{Definition: t.ToolDefinition{Name: "get_prd_session", Description: "Get PRD session state", InputSchema: sp(), OutputSchema: h.OutputSchemaOf(t.PrdSession{}), Annotations: readOnly("Get PRD Session")}, Handler: func(a map[string]any) (*t.ToolResult, error) {
 			s, err := loadPrd(ws, h.GetString(a, "project"))
 			if err != nil {
 				return h.ErrorResult(err.Error()), nil
 			}
-			return h.JSONResult(s), nil
+			return h.StructuredResult(s), nil
 		}},
-		{Definition: t.ToolDefinition{Name: "abandon_prd_session", Description: "Abandon PRD session", InputSchema: sp()}, Handler: func(a map[string]any) (*t.ToolResult, error) {
+		{Definition: t.ToolDefinition{Name: "abandon_prd_session", Description: "Abandon PRD session", InputSchema: sp(), OutputSchema: messageOutput, Annotations: destructive("Abandon PRD Session")}, Handler: func(a map[string]any) (*t.ToolResult, error) {
 			os.Remove(prdFile(ws, h.GetString(a, "project")))
-			return h.TextResult("abandoned"), nil
+			return h.MessageResult("abandoned"), nil
 		}},
-		{Definition: t.ToolDefinition{Name: "skip_prd_question", Description: "Skip optional PRD question", InputSchema: sp()}, Handler: func(a map[string]any) (*t.ToolResult, error) {
+		{Definition: t.ToolDefinition{Name: "skip_prd_question", Description: "Skip optional PRD question", InputSchema: sp(), OutputSchema: prdStepOutput, Annotations: additive("Skip PRD Question")}, Handler: func(a map[string]any) (*t.ToolResult, error) {
 			s, err := loadPrd(ws, h.GetString(a, "project"))
 			if err != nil {
 				return h.ErrorResult(err.Error()), nil
@@ -52,7 +45,7 @@ func Prd(ws string) []t.Tool {
 			}
 			return advancePrd(ws, s), nil
 		}},
-		{Definition: t.ToolDefinition{Name: "back_prd_question", Description: "Go back to previous PRD question", InputSchema: sp()}, Handler: func(a map[string]any) (*t.ToolResult, error) {
+		{Definition: t.ToolDefinition{Name: "back_prd_question", Description: "Go back to previous PRD question", InputSchema: sp(), OutputSchema: prdStepOutput, Annotations: additive("Previous PRD Question")}, Handler: func(a map[string]any) (*t.ToolResult, error) {
 			s, err := loadPrd(ws, h.GetString(a, "project"))
 			if err != nil {
 				return h.ErrorResult(err.Error()), nil
@@ -68,18 +61,80 @@ func Prd(ws string) []t.Tool {
 			if err := savePrd(ws, s); err != nil {
 				return h.ErrorResult(err.Error()), nil
 			}
-			return h.JSONResult(nextQ(s)), nil
+			return h.StructuredResult(nextQ(s)), nil
 		}},
-		{Definition: t.ToolDefinition{Name: "preview_prd", Description: "Preview PRD markdown", InputSchema: sp()}, Handler: func(a map[string]any) (*t.ToolResult, error) {
+		previewPrd(ws),
+		splitPrd(ws),
+		listPrdPhases(ws),
+	}
+}
+
+// startPrd creates a PRD session. When the client supports elicitation the
+// user fills in the questionnaire directly, one form per section; otherwise,
+// or once the user declines a form, the first unanswered question is
+// returned for the step-by-step tools.
+func startPrd(ws string) t.Tool {
+	return t.NewContextTool(
+		t.ToolDefinition{Name: "start_prd_session", Description: "Start guided PRD creation", InputSchema: sp(), OutputSchema: prdStepOutput, Annotations: destructive("Start PRD Session")},
+		func(ctx context.Context, a map[string]any) (*t.ToolResult, error) {
+			slug := h.GetString(a, "project")
+			if !h.FileExists(h.ProjectDir(ws, slug)) {
+				return h.ErrorResult("project not found"), nil
+			}
+			s := &t.PrdSession{Slug: slug, ProjectName: slug, Status: "in_progress"}
+			if h.CanElicit(ctx) {
+				elicitPrd(ctx, s)
+				if s.CurrentIndex >= len(prdQuestions) {
+					return finishPrd(ws, s), nil
+				}
+			}
+			if err := savePrd(ws, s); err != nil {
+				return h.ErrorResult(err.Error()), nil
+			}
+			return h.StructuredResult(nextQ(s)), nil
+		},
+	)
+}
+
+func previewPrd(ws string) t.Tool {
+	schema := sp()
+	schema.Properties["expand"] = map[string]any{"type": "boolean", "description": "Ask the client's model to expand terse answers into full sections"}
+	return t.NewContextTool(
+		t.ToolDefinition{Name: "preview_prd", Description: "Preview PRD markdown", InputSchema: schema, OutputSchema: documentOutput, Annotations: readOnly("Preview PRD")},
+		func(ctx context.Context, a map[string]any) (*t.ToolResult, error) {
 			s, err := loadPrd(ws, h.GetString(a, "project"))
 			if err != nil {
 				return h.ErrorResult(err.Error()), nil
 			}
-			return h.TextResult(generatePrdMarkdown(s)), nil
-		}},
-		splitPrd(ws),
-		listPrdPhases(ws),
+			md := generatePrdMarkdown(s)
+			if h.GetBool(a, "expand") && h.CanSample(ctx) {
+				expanded, err := expandPrd(ctx, md)
+				if err == nil {
+					return documentResult(expanded), nil
+				}
+				logger.Warning("tools", "preview_prd: expansion failed, returning answers as written", "error", err)
+			}
+			return documentResult(md), nil
+		},
+	)
+}
+
+// expandPrd asks the client's model to turn questionnaire answers into
+// full PRD prose, keeping the section headings.
+func expandPrd(ctx context.Context, md string) (string, error) {
+	res, err := h.Sample(ctx, t.CreateMessageParams{
+		SystemPrompt: "You are a product manager. Expand terse questionnaire answers into complete PRD sections. " +
+			"Keep every heading exactly as given, do not invent requirements, and reply with markdown only.",
+		Messages:  []t.SamplingMessage{{Role: "user", Content: t.ContentBlock{Type: "text", Text: md}}},
+		MaxTokens: 4000,
+	})
+	if err != nil {
+		return "", err
 	}
+	if res.Content.Type != "text" || strings.TrimSpace(res.Content.Text) == "" {
+		return "", fmt.Errorf("sampling returned no text")
+	}
+	return res.Content.Text, nil
 }
 
 func splitPrd(ws string) t.Tool {
@@ -90,6 +145,8 @@ func splitPrd(ws string) t.Tool {
 				"project": map[string]any{"type": "string", "description": "Project slug"},
 				"phases":  map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Phase names in order"},
 			}, Required: []string{"project", "phases"}},
+			OutputSchema: objectOutput(map[string]any{"phases": strListType, "count": intType}, "phases", "count"),
+			Annotations:  idempotent("Split PRD"),
 		},
 		Handler: func(a map[string]any) (*t.ToolResult, error) {
 			slug := h.GetString(a, "project")
@@ -135,16 +192,29 @@ func splitPrd(ws string) t.Tool {
 			if err := savePrd(ws, parent); err != nil {
 				return h.ErrorResult(err.Error()), nil
 			}
-			return h.JSONResult(map[string]any{"phases": parent.Phases, "count": len(phases)}), nil
+			return h.StructuredResult(map[string]any{"phases": parent.Phases, "count": len(phases)}), nil
 		},
 	}
 }
 
+// prdPhase summarizes one phase of a split PRD.
+type prdPhase struct {
+	Slug   string `json:"slug"`
+	Name   string `json:"name"`
+	Phase  int    `json:"phase"`
+	Status string `json:"status"`
+}
+
 func listPrdPhases(ws string) t.Tool {
 	return t.Tool{
 		Definition: t.ToolDefinition{
 			Name: "list_prd_phases", Description: "List PRD phases for a project",
 			InputSchema: sp(),
+			OutputSchema: objectOutput(map[string]any{
+				"phases":  map[string]any{"type": "array", "items": h.SchemaOf(prdPhase{})},
+				"message": strType,
+			}, "phases"),
+			Annotations: readOnly("List PRD Phases"),
 		},
 		Handler: func(a map[string]any) (*t.ToolResult, error) {
 			slug := h.GetString(a, "project")
@@ -153,24 +223,18 @@ func listPrdPhases(ws string) t.Tool {
 				return h.ErrorResult(err.Error()), nil
 			}
 			if len(s.Phases) == 0 {
-				return h.JSONResult(map[string]any{"phases": []any{}, "message": "no phases — use split_prd first"}), nil
-			}
-			type phaseInfo struct {
-				Slug   string `json:"slug"`
-				Name   string `json:"name"`
-				Phase  int    `json:"phase"`
-				Status string `json:"status"`
+				return h.StructuredResult(map[string]any{"phases": []any{}, "message": "no phases — use split_prd first"}), nil
 			}
-			var phases []phaseInfo
+			var phases []prdPhase
 			for _, ps := range s.Phases {
 				child, err := loadPrd(ws, ps)
 				if err != nil {
-					phases = append(phases, phaseInfo{Slug: ps, Status: "missing"})
+					phases = append(phases, prdPhase{Slug: ps, Status: "missing"})
 					continue
 				}
-				phases = append(phases, phaseInfo{Slug: ps, Name: child.ProjectName, Phase: child.Phase, Status: child.Status})
+				phases = append(phases, prdPhase{Slug: ps, Name: child.ProjectName, Phase: child.Phase, Status: child.Status})
 			}
-			return h.JSONResult(phases), nil
+			return h.ListResult("phases", phases), nil
 		},
 	}
 }
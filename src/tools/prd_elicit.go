@@ -0,0 +1,81 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	h "github.com/orchestra-mcp/mcp/src/helpers"
+	"github.com/orchestra-mcp/mcp/src/logger"
+	t "github.com/orchestra-mcp/mcp/src/types"
+)
+
+// elicitPrd asks the user for each remaining questionnaire section in turn
+// and records the answers on s. It stops at the first declined, cancelled
+// or failed form, or at a required question left empty, leaving
+// s.CurrentIndex on the first unanswered question.
+func elicitPrd(ctx context.Context, s *t.PrdSession) {
+	for s.CurrentIndex < len(prdQuestions) {
+		qs := sectionQuestions(s.CurrentIndex)
+		sec := qs[0].Section
+		res, err := h.Elicit(ctx, t.ElicitParams{
+			Message:         fmt.Sprintf("PRD for %s: %s", s.ProjectName, sectionTitles[sec]),
+			RequestedSchema: prdSectionSchema(qs),
+		})
+		if err != nil {
+			logger.Warning("tools", "start_prd_session: elicitation failed, continuing step by step", "section", sec, "error", err)
+			return
+		}
+		if res.Action != "accept" {
+			return
+		}
+		for _, q := range qs {
+			v := elicitedString(res.Content[q.Key])
+			if v == "" && q.Required {
+				return
+			}
+			if v != "" {
+				s.Answers = append(s.Answers, t.PrdAnswer{Question: q.Key, Answer: v})
+			}
+			s.CurrentIndex++
+		}
+	}
+}
+
+// sectionQuestions returns the questions from index to the end of its section.
+func sectionQuestions(index int) []t.PrdQuestion {
+	end := index
+	for end < len(prdQuestions) && prdQuestions[end].Section == prdQuestions[index].Section {
+		end++
+	}
+	return prdQuestions[index:end]
+}
+
+// prdSectionSchema maps questions to an elicitation form: one string field
+// per question, Options as an enum, Required questions required.
+func prdSectionSchema(qs []t.PrdQuestion) t.ElicitSchema {
+	schema := t.ElicitSchema{Type: "object", Properties: map[string]any{}}
+	for _, q := range qs {
+		prop := map[string]any{"type": "string", "title": questionLabels[q.Key], "description": q.Question}
+		if len(q.Options) > 0 {
+			prop["enum"] = q.Options
+		}
+		if q.Required {
+			prop["minLength"] = 1
+			schema.Required = append(schema.Required, q.Key)
+		}
+		schema.Properties[q.Key] = prop
+	}
+	return schema
+}
+
+func elicitedString(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(x)
+	default:
+		return fmt.Sprint(x)
+	}
+}
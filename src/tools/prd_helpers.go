@@ -93,7 +93,7 @@ func finishPrd(ws string, s *t.PrdSession) *t.ToolResult {
 	if err := savePrd(ws, s); err != nil {
 		return h.ErrorResult(err.Error())
 	}
-	return h.JSONResult(map[string]any{"status": "complete", "file": "prd.md"})
+	return h.StructuredResult(map[string]any{"status": "complete", "file": "prd.md"})
 }
 
 func advancePrd(ws string, s *t.PrdSession) *t.ToolResult {
@@ -104,7 +104,7 @@ func advancePrd(ws string, s *t.PrdSession) *t.ToolResult {
 	if err := savePrd(ws, s); err != nil {
 		return h.ErrorResult(err.Error())
 	}
-	return h.JSONResult(nextQ(s))
+	return h.StructuredResult(nextQ(s))
 }
 
 func sp() t.InputSchema {
@@ -22,14 +22,16 @@ func listProjects(ws string) t.Tool {
 	return t.Tool{
 		Definition: t.ToolDefinition{
 			Name: "list_projects", Description: "List all projects",
-			InputSchema: t.InputSchema{Type: "object"},
+			InputSchema:  t.InputSchema{Type: "object"},
+			OutputSchema: h.ListOutputSchema("projects", t.ProjectStatus{}),
+			Annotations:  readOnly("List Projects"),
 		},
 		Handler: func(args map[string]any) (*t.ToolResult, error) {
 			dir := h.ProjectsDir(ws)
 			entries, err := os.ReadDir(dir)
 			if err != nil {
 				if os.IsNotExist(err) {
-					return h.JSONResult([]any{}), nil
+					return h.ListResult("projects", []t.ProjectStatus{}), nil
 				}
 				return h.ErrorResult(err.Error()), nil
 			}
@@ -43,7 +45,7 @@ func listProjects(ws string) t.Tool {
 					projects = append(projects, ps)
 				}
 			}
-			return h.JSONResult(projects), nil
+			return h.ListResult("projects", projects), nil
 		},
 	}
 }
@@ -56,6 +58,8 @@ func createProject(ws string) t.Tool {
 				"name":        map[string]any{"type": "string", "description": "Project name"},
 				"description": map[string]any{"type": "string", "description": "Project description"},
 			}, Required: []string{"name"}},
+			OutputSchema: objectOutput(map[string]any{"slug": strType, "key": strType, "status": strType}, "slug", "key", "status"),
+			Annotations:  additive("Create Project"),
 		},
 		Handler: func(args map[string]any) (*t.ToolResult, error) {
 			name := h.GetString(args, "name")
@@ -79,7 +83,7 @@ func createProject(ws string) t.Tool {
 			if err := os.WriteFile(filepath.Join(dir, "prd.md"), []byte(prd), 0o644); err != nil {
 				return h.ErrorResult(err.Error()), nil
 			}
-			return h.JSONResult(map[string]any{
+			return h.StructuredResult(map[string]any{
 				"slug": slug, "key": h.DeriveKey(name), "status": "created",
 			}), nil
 		},
@@ -93,6 +97,8 @@ func getProjectStatus(ws string) t.Tool {
 			InputSchema: t.InputSchema{Type: "object", Properties: map[string]any{
 				"project": map[string]any{"type": "string", "description": "Project slug"},
 			}, Required: []string{"project"}},
+			OutputSchema: h.OutputSchemaOf(t.ProjectStatus{}),
+			Annotations:  readOnly("Get Project Status"),
 		},
 		Handler: func(args map[string]any) (*t.ToolResult, error) {
 			var ps t.ProjectStatus
@@ -100,7 +106,7 @@ func getProjectStatus(ws string) t.Tool {
 			if err := toon.ParseFile(p, &ps); err != nil {
 				return h.ErrorResult(err.Error()), nil
 			}
-			return h.JSONResult(ps), nil
+			return h.StructuredResult(ps), nil
 		},
 	}
 }
@@ -112,13 +118,15 @@ func readPrd(ws string) t.Tool {
 			InputSchema: t.InputSchema{Type: "object", Properties: map[string]any{
 				"project": map[string]any{"type": "string"},
 			}, Required: []string{"project"}},
+			OutputSchema: documentOutput,
+			Annotations:  readOnly("Read PRD"),
 		},
 		Handler: func(args map[string]any) (*t.ToolResult, error) {
 			data, err := os.ReadFile(filepath.Join(h.ProjectDir(ws, h.GetString(args, "project")), "prd.md"))
 			if err != nil {
 				return h.ErrorResult(err.Error()), nil
 			}
-			return h.TextResult(string(data)), nil
+			return documentResult(string(data)), nil
 		},
 	}
 }
@@ -131,13 +139,15 @@ func writePrd(ws string) t.Tool {
 				"project": map[string]any{"type": "string"},
 				"content": map[string]any{"type": "string", "description": "PRD markdown"},
 			}, Required: []string{"project", "content"}},
+			OutputSchema: messageOutput,
+			Annotations:  destructive("Write PRD"),
 		},
 		Handler: func(args map[string]any) (*t.ToolResult, error) {
 			p := filepath.Join(h.ProjectDir(ws, h.GetString(args, "project")), "prd.md")
 			if err := os.WriteFile(p, []byte(h.GetString(args, "content")), 0o644); err != nil {
 				return h.ErrorResult(err.Error()), nil
 			}
-			return h.TextResult("PRD updated"), nil
+			return h.MessageResult("PRD updated"), nil
 		},
 	}
 }
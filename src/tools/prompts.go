@@ -60,6 +60,7 @@ func reviewTaskPrompt(ws string) t.Prompt {
 				{Name: "task_id", Description: "Task ID to review", Required: true},
 			},
 		},
+		Complete: completeIDs(ws),
 		Handler: func(args map[string]string) (string, []t.PromptMessage, error) {
 			p := filepath.Join(h.ProjectDir(ws, args["project"]),
 				"epics", args["epic_id"], "stories", args["story_id"],
@@ -89,6 +90,7 @@ func planSprintPrompt(ws string) t.Prompt {
 				{Name: "project", Description: "Project slug", Required: true},
 			},
 		},
+		Complete: completeIDs(ws),
 		Handler: func(args map[string]string) (string, []t.PromptMessage, error) {
 			statusPath := filepath.Join(h.ProjectDir(ws, args["project"]), "project-status.toon")
 			var ps t.ProjectStatus
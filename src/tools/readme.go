@@ -1,6 +1,7 @@
 package tools
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -29,12 +30,14 @@ func statusBadge(s string) string {
 // Readme returns the readme generation tool.
 func Readme(ws string) []t.Tool {
 	return []t.Tool{
-		{Definition: t.ToolDefinition{
+		t.NewContextTool(t.ToolDefinition{
 			Name: "regenerate_readme", Description: "Regenerate project README from issues",
 			InputSchema: t.InputSchema{Type: "object", Properties: map[string]any{
 				"project": map[string]any{"type": "string", "description": "Project slug"},
 			}, Required: []string{"project"}},
-		}, Handler: func(a map[string]any) (*t.ToolResult, error) {
+			OutputSchema: messageOutput,
+			Annotations:  idempotent("Regenerate README"),
+		}, func(ctx context.Context, a map[string]any) (*t.ToolResult, error) {
 			slug := h.GetString(a, "project")
 			projDir := h.ProjectDir(ws, slug)
 			statusPath := filepath.Join(projDir, "project-status.toon")
@@ -42,7 +45,11 @@ func Readme(ws string) []t.Tool {
 			if err := toon.ParseFile(statusPath, &ps); err != nil {
 				return h.ErrorResult(err.Error()), nil
 			}
-			issues := h.ScanAllIssues(ws, slug)
+			issues := h.ScanAllIssuesContext(ctx, ws, slug)
+			if err := ctx.Err(); err != nil {
+				// The scan stopped early; keep the old README over a partial one.
+				return nil, err
+			}
 			var b strings.Builder
 			b.WriteString(fmt.Sprintf("# %s\n\n", ps.Project))
 			if ps.Description != "" {
@@ -67,8 +74,8 @@ func Readme(ws string) []t.Tool {
 			if err := os.WriteFile(p, []byte(b.String()), 0o644); err != nil {
 				return h.ErrorResult(err.Error()), nil
 			}
-			return h.TextResult("README.md regenerated"), nil
-		}},
+			return h.MessageResult("README.md regenerated"), nil
+		}),
 	}
 }
 
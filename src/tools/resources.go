@@ -21,83 +21,133 @@ func Resources(ws string) []t.Resource {
 	}
 }
 
+const (
+	prdTemplate    = "toon://project/{slug}/prd"
+	statusTemplate = "toon://project/{slug}/status"
+	taskTemplate   = "toon://project/{slug}/task/{epicId}/{storyId}/{taskId}"
+)
+
 func projectPrdResource(ws string) t.Resource {
 	return t.Resource{
 		Definition: t.ResourceDefinition{
-			URI:         "toon://project/{slug}/prd",
+			URI:         prdTemplate,
 			Name:        "project_prd",
 			Title:       "Project PRD Document",
 			Description: "The Product Requirements Document for a project",
 			MimeType:    "text/markdown",
 		},
 		Handler: func(uri string) ([]t.ResourceContent, error) {
-			slug := extractParam("toon://project/{slug}/prd", uri, "slug")
-			data, err := os.ReadFile(filepath.Join(h.ProjectDir(ws, slug), "prd.md"))
+			slug := extractParam(prdTemplate, uri, "slug")
+			data, err := os.ReadFile(prdResourcePath(ws, uri))
 			if err != nil {
 				return nil, fmt.Errorf("prd not found for %s: %w", slug, err)
 			}
 			return []t.ResourceContent{{URI: uri, MimeType: "text/markdown", Text: string(data)}}, nil
 		},
+		Complete: completeIDs(ws),
+		Paths:    func(uri string) []string { return []string{prdResourcePath(ws, uri)} },
+		List: func() []t.ResourceDefinition {
+			var out []t.ResourceDefinition
+			for _, slug := range h.ProjectSlugs(ws) {
+				if h.FileExists(filepath.Join(h.ProjectDir(ws, slug), "prd.md")) {
+					out = append(out, t.ResourceDefinition{
+						URI:  h.ExpandURITemplate(prdTemplate, map[string]string{"slug": slug}),
+						Name: slug + " PRD", MimeType: "text/markdown",
+					})
+				}
+			}
+			return out
+		},
 	}
 }
 
 func projectStatusResource(ws string) t.Resource {
 	return t.Resource{
 		Definition: t.ResourceDefinition{
-			URI:         "toon://project/{slug}/status",
+			URI:         statusTemplate,
 			Name:        "project_status",
 			Title:       "Project Status",
 			Description: "Current project status with epic/story/task summaries",
 			MimeType:    "application/json",
 		},
 		Handler: func(uri string) ([]t.ResourceContent, error) {
-			slug := extractParam("toon://project/{slug}/status", uri, "slug")
 			var ps t.ProjectStatus
-			p := filepath.Join(h.ProjectDir(ws, slug), "project-status.toon")
-			if err := toon.ParseFile(p, &ps); err != nil {
+			if err := toon.ParseFile(statusResourcePath(ws, uri), &ps); err != nil {
 				return nil, err
 			}
 			data, _ := json.MarshalIndent(ps, "", "  ")
 			return []t.ResourceContent{{URI: uri, MimeType: "application/json", Text: string(data)}}, nil
 		},
+		Complete: completeIDs(ws),
+		Paths:    func(uri string) []string { return []string{statusResourcePath(ws, uri)} },
+		List: func() []t.ResourceDefinition {
+			var out []t.ResourceDefinition
+			for _, slug := range h.ProjectSlugs(ws) {
+				if h.FileExists(filepath.Join(h.ProjectDir(ws, slug), "project-status.toon")) {
+					out = append(out, t.ResourceDefinition{
+						URI:  h.ExpandURITemplate(statusTemplate, map[string]string{"slug": slug}),
+						Name: slug + " status", MimeType: "application/json",
+					})
+				}
+			}
+			return out
+		},
 	}
 }
 
 func taskDetailResource(ws string) t.Resource {
 	return t.Resource{
 		Definition: t.ResourceDefinition{
-			URI:         "toon://project/{slug}/task/{epicId}/{storyId}/{taskId}",
+			URI:         taskTemplate,
 			Name:        "task_detail",
 			Title:       "Task Detail",
 			Description: "Full detail of a specific task",
 			MimeType:    "application/json",
 		},
 		Handler: func(uri string) ([]t.ResourceContent, error) {
-			pattern := "toon://project/{slug}/task/{epicId}/{storyId}/{taskId}"
-			slug := extractParam(pattern, uri, "slug")
-			epicID := extractParam(pattern, uri, "epicId")
-			storyID := extractParam(pattern, uri, "storyId")
-			taskID := extractParam(pattern, uri, "taskId")
-			p := filepath.Join(h.ProjectDir(ws, slug),
-				"epics", epicID, "stories", storyID, "tasks", taskID+".toon")
 			var task t.IssueData
-			if err := toon.ParseFile(p, &task); err != nil {
+			if err := toon.ParseFile(taskResourcePath(ws, uri), &task); err != nil {
 				return nil, err
 			}
 			data, _ := json.MarshalIndent(task, "", "  ")
 			return []t.ResourceContent{{URI: uri, MimeType: "application/json", Text: string(data)}}, nil
 		},
+		Complete: completeIDs(ws),
+		Paths:    func(uri string) []string { return []string{taskResourcePath(ws, uri)} },
+		List: func() []t.ResourceDefinition {
+			var out []t.ResourceDefinition
+			for _, slug := range h.ProjectSlugs(ws) {
+				for _, tk := range h.ScanAllTasks(ws, slug) {
+					taskID := strings.TrimSuffix(filepath.Base(tk.Path), ".toon")
+					out = append(out, t.ResourceDefinition{
+						URI: h.ExpandURITemplate(taskTemplate, map[string]string{
+							"slug": slug, "epicId": tk.EpicID, "storyId": tk.StoryID, "taskId": taskID,
+						}),
+						Name: tk.Data.ID, Title: tk.Data.Title, MimeType: "application/json",
+					})
+				}
+			}
+			return out
+		},
 	}
 }
 
+func prdResourcePath(ws, uri string) string {
+	return filepath.Join(h.ProjectDir(ws, extractParam(prdTemplate, uri, "slug")), "prd.md")
+}
+
+func statusResourcePath(ws, uri string) string {
+	return filepath.Join(h.ProjectDir(ws, extractParam(statusTemplate, uri, "slug")), "project-status.toon")
+}
+
+func taskResourcePath(ws, uri string) string {
+	vars, _ := h.MatchURITemplate(taskTemplate, uri)
+	return filepath.Join(h.ProjectDir(ws, vars["slug"]),
+		"epics", vars["epicId"], "stories", vars["storyId"], "tasks", vars["taskId"]+".toon")
+}
+
 // extractParam extracts a named {param} from a URI given the pattern.
 func extractParam(pattern, uri, name string) string {
-	pp := strings.Split(pattern, "/")
-	up := strings.Split(uri, "/")
-	for i, seg := range pp {
-		if seg == "{"+name+"}" && i < len(up) {
-			return up[i]
-		}
-	}
-	return ""
+	vars, _ := h.MatchURITemplate(pattern, uri)
+	return vars[name]
 }
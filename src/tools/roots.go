@@ -0,0 +1,123 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	h "github.com/orchestra-mcp/mcp/src/helpers"
+	t "github.com/orchestra-mcp/mcp/src/types"
+)
+
+// Rooted builds the tools from factory for ws and lets them also serve the
+// workspaces the client exposes as MCP roots. Each tool gains an optional
+// "workspace" argument (a root path or name). Without it, the call goes to
+// the one workspace holding the "project" slug, or to ws when none does;
+// a slug found in several workspaces is an error asking for "workspace".
+func Rooted(ws string, factory func(ws string) []t.Tool) []t.Tool {
+	set := &rootedSet{factory: factory, byWS: map[string][]t.Tool{}}
+	base := set.tools(ws)
+	out := make([]t.Tool, len(base))
+	for i, tool := range base {
+		def := tool.Definition
+		def.InputSchema.Properties = maps.Clone(def.InputSchema.Properties)
+		if def.InputSchema.Properties == nil {
+			def.InputSchema.Properties = map[string]any{}
+		}
+		def.InputSchema.Properties["workspace"] = map[string]any{
+			"type": "string", "description": "Workspace root path or name, when the project exists in several",
+		}
+		out[i] = t.NewContextTool(def, func(ctx context.Context, args map[string]any) (*t.ToolResult, error) {
+			target, err := resolveWorkspace(ctx, ws, args)
+			if err != nil {
+				return h.ErrorResult(err.Error()), nil
+			}
+			args = maps.Clone(args)
+			delete(args, "workspace")
+			return set.tools(target)[i].Call(ctx, args)
+		})
+	}
+	return out
+}
+
+// rootedSet caches the tools built for each workspace.
+type rootedSet struct {
+	mu      sync.Mutex
+	factory func(ws string) []t.Tool
+	byWS    map[string][]t.Tool
+}
+
+func (s *rootedSet) tools(ws string) []t.Tool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if tools, ok := s.byWS[ws]; ok {
+		return tools
+	}
+	tools := s.factory(ws)
+	s.byWS[ws] = tools
+	return tools
+}
+
+// workspace is a candidate directory with the name a client may refer to it by.
+type workspace struct {
+	path, name string
+}
+
+// workspaces returns ws followed by every file:// root in ctx.
+func workspaces(ctx context.Context, ws string) []workspace {
+	out := []workspace{{path: ws, name: filepath.Base(ws)}}
+	seen := map[string]bool{filepath.Clean(ws): true}
+	for _, r := range h.Roots(ctx) {
+		p, ok := h.RootPath(r)
+		if !ok || seen[p] {
+			continue
+		}
+		seen[p] = true
+		name := r.Name
+		if name == "" {
+			name = filepath.Base(p)
+		}
+		out = append(out, workspace{path: p, name: name})
+	}
+	return out
+}
+
+func resolveWorkspace(ctx context.Context, ws string, args map[string]any) (string, error) {
+	candidates := workspaces(ctx, ws)
+	if want := h.GetString(args, "workspace"); want != "" {
+		for _, c := range candidates {
+			if filepath.Clean(c.path) == filepath.Clean(want) || c.name == want {
+				return c.path, nil
+			}
+		}
+		return "", fmt.Errorf("unknown workspace %q; available: %s", want, workspaceNames(candidates))
+	}
+	slug := h.GetString(args, "project")
+	if slug == "" || len(candidates) == 1 {
+		return ws, nil
+	}
+	var matches []workspace
+	for _, c := range candidates {
+		if h.FileExists(h.ProjectDir(c.path, slug)) {
+			matches = append(matches, c)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return ws, nil
+	case 1:
+		return matches[0].path, nil
+	}
+	return "", fmt.Errorf("project %q exists in several workspaces (%s); pass workspace", slug, workspaceNames(matches))
+}
+
+func workspaceNames(ws []workspace) string {
+	names := make([]string, len(ws))
+	for i, w := range ws {
+		names[i] = fmt.Sprintf("%s (%s)", w.name, w.path)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,53 @@
+package tools
+
+import (
+	h "github.com/orchestra-mcp/mcp/src/helpers"
+	t "github.com/orchestra-mcp/mcp/src/types"
+)
+
+// Output schemas shared by tools that return the same shapes.
+var (
+	issueOutput   = h.OutputSchemaOf(t.IssueData{})
+	messageOutput = objectOutput(map[string]any{"message": strType}, "message")
+	// documentOutput carries a markdown document returned as plain text.
+	documentOutput = objectOutput(map[string]any{"content": strType}, "content")
+	installOutput  = objectOutput(map[string]any{"installed": intType, "available": strListType}, "installed", "available")
+	// prdStepOutput is the next PRD question, or {status, file} once complete.
+	prdStepOutput = objectOutput(map[string]any{
+		"status": strType, "question": strType, "key": strType, "index": intType,
+		"required": boolType, "options": strListType, "file": strType,
+	}, "status")
+	// nextTaskOutput is an issue, or {message} when nothing is actionable.
+	nextTaskOutput = objectOutput(withProps(issueType, "message", strType))
+)
+
+var (
+	strType     = map[string]any{"type": "string"}
+	intType     = map[string]any{"type": "integer"}
+	boolType    = map[string]any{"type": "boolean"}
+	strListType = map[string]any{"type": "array", "items": strType}
+	// nullable lists are slices built by append that encode as null when empty.
+	nullableStrList = map[string]any{"type": []string{"array", "null"}, "items": strType}
+	issueType       = h.SchemaOf(t.IssueData{})
+)
+
+// objectOutput builds an object output schema from explicit properties.
+func objectOutput(props map[string]any, required ...string) *t.OutputSchema {
+	return &t.OutputSchema{Type: "object", Properties: props, Required: required}
+}
+
+// withProps copies the properties of an object schema and adds name: schema.
+func withProps(object map[string]any, name string, schema any) map[string]any {
+	props := map[string]any{name: schema}
+	for k, v := range object["properties"].(map[string]any) {
+		props[k] = v
+	}
+	return props
+}
+
+// documentResult returns markdown as plain text plus {"content": md}.
+func documentResult(md string) *t.ToolResult {
+	r := h.TextResult(md)
+	r.StructuredContent = map[string]any{"content": md}
+	return r
+}
@@ -23,13 +23,15 @@ func listStories(ws string) t.Tool {
 				"project": map[string]any{"type": "string"},
 				"epic_id": map[string]any{"type": "string"},
 			}, Required: []string{"project", "epic_id"}},
+			OutputSchema: h.ListOutputSchema("stories", t.IssueData{}),
+			Annotations:  readOnly("List Stories"),
 		},
 		Handler: func(args map[string]any) (*t.ToolResult, error) {
 			dir := filepath.Join(h.ProjectDir(ws, h.GetString(args, "project")), "epics", h.GetString(args, "epic_id"), "stories")
 			entries, err := os.ReadDir(dir)
 			if err != nil {
 				if os.IsNotExist(err) {
-					return h.JSONResult([]any{}), nil
+					return h.ListResult("stories", []t.IssueData{}), nil
 				}
 				return h.ErrorResult(err.Error()), nil
 			}
@@ -43,7 +45,7 @@ func listStories(ws string) t.Tool {
 					stories = append(stories, issue)
 				}
 			}
-			return h.JSONResult(stories), nil
+			return h.ListResult("stories", stories), nil
 		},
 	}
 }
@@ -58,6 +60,8 @@ func createStory(ws string) t.Tool {
 				"user_story": map[string]any{"type": "string", "description": "As a... I want... So that..."},
 				"priority":   map[string]any{"type": "string"},
 			}, Required: []string{"project", "epic_id", "title", "user_story"}},
+			OutputSchema: issueOutput,
+			Annotations:  additive("Create Story"),
 		},
 		Handler: func(args map[string]any) (*t.ToolResult, error) {
 			slug := h.GetString(args, "project")
@@ -87,7 +91,7 @@ func createStory(ws string) t.Tool {
 			h.UpdateProjectStatus(&ps, issue)
 			ps.UpdatedAt = h.Now()
 			_ = toon.WriteFile(statusPath, &ps)
-			return h.JSONResult(issue), nil
+			return h.StructuredResult(issue), nil
 		},
 	}
 }
@@ -100,6 +104,8 @@ func getStory(ws string) t.Tool {
 				"project": map[string]any{"type": "string"}, "epic_id": map[string]any{"type": "string"},
 				"story_id": map[string]any{"type": "string"},
 			}, Required: []string{"project", "epic_id", "story_id"}},
+			OutputSchema: issueOutput,
+			Annotations:  readOnly("Get Story"),
 		},
 		Handler: func(args map[string]any) (*t.ToolResult, error) {
 			storyDir := filepath.Join(h.ProjectDir(ws, h.GetString(args, "project")),
@@ -121,7 +127,7 @@ func getStory(ws string) t.Tool {
 				}
 			}
 			issue.Children = children
-			return h.JSONResult(issue), nil
+			return h.StructuredResult(issue), nil
 		},
 	}
 }
@@ -136,6 +142,8 @@ func updateStory(ws string) t.Tool {
 				"description": map[string]any{"type": "string"}, "status": map[string]any{"type": "string"},
 				"priority": map[string]any{"type": "string"},
 			}, Required: []string{"project", "epic_id", "story_id"}},
+			OutputSchema: issueOutput,
+			Annotations:  idempotent("Update Story"),
 		},
 		Handler: func(args map[string]any) (*t.ToolResult, error) {
 			slug := h.GetString(args, "project")
@@ -172,7 +180,7 @@ func updateStory(ws string) t.Tool {
 				ps.UpdatedAt = h.Now()
 				_ = toon.WriteFile(statusPath, &ps)
 			}
-			return h.JSONResult(issue), nil
+			return h.StructuredResult(issue), nil
 		},
 	}
 }
@@ -185,6 +193,8 @@ func deleteStory(ws string) t.Tool {
 				"project": map[string]any{"type": "string"}, "epic_id": map[string]any{"type": "string"},
 				"story_id": map[string]any{"type": "string"},
 			}, Required: []string{"project", "epic_id", "story_id"}},
+			OutputSchema: messageOutput,
+			Annotations:  destructive("Delete Story"),
 		},
 		Handler: func(args map[string]any) (*t.ToolResult, error) {
 			slug := h.GetString(args, "project")
@@ -204,7 +214,7 @@ func deleteStory(ws string) t.Tool {
 				ps.UpdatedAt = h.Now()
 				_ = toon.WriteFile(statusPath, &ps)
 			}
-			return h.TextResult(fmt.Sprintf("deleted story %s", storyID)), nil
+			return h.MessageResult(fmt.Sprintf("deleted story %s", storyID)), nil
 		},
 	}
 }
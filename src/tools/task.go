@@ -23,6 +23,8 @@ func listTasks(ws string) t.Tool {
 				"project": map[string]any{"type": "string"}, "epic_id": map[string]any{"type": "string"},
 				"story_id": map[string]any{"type": "string"},
 			}, Required: []string{"project", "epic_id", "story_id"}},
+			OutputSchema: h.ListOutputSchema("tasks", t.IssueData{}),
+			Annotations:  readOnly("List Tasks"),
 		},
 		Handler: func(args map[string]any) (*t.ToolResult, error) {
 			dir := filepath.Join(h.ProjectDir(ws, h.GetString(args, "project")),
@@ -30,7 +32,7 @@ func listTasks(ws string) t.Tool {
 			entries, err := os.ReadDir(dir)
 			if err != nil {
 				if os.IsNotExist(err) {
-					return h.JSONResult([]any{}), nil
+					return h.ListResult("tasks", []t.IssueData{}), nil
 				}
 				return h.ErrorResult(err.Error()), nil
 			}
@@ -44,7 +46,7 @@ func listTasks(ws string) t.Tool {
 					tasks = append(tasks, task)
 				}
 			}
-			return h.JSONResult(tasks), nil
+			return h.ListResult("tasks", tasks), nil
 		},
 	}
 }
@@ -60,6 +62,8 @@ func createTask(ws string) t.Tool {
 				"description": map[string]any{"type": "string"},
 				"priority":    map[string]any{"type": "string"},
 			}, Required: []string{"project", "epic_id", "story_id", "title", "type"}},
+			OutputSchema: issueOutput,
+			Annotations:  additive("Create Task"),
 		},
 		Handler: func(args map[string]any) (*t.ToolResult, error) {
 			slug := h.GetString(args, "project")
@@ -90,7 +94,7 @@ func createTask(ws string) t.Tool {
 			h.UpdateProjectStatus(&ps, task)
 			ps.UpdatedAt = h.Now()
 			_ = toon.WriteFile(statusPath, &ps)
-			return h.JSONResult(task), nil
+			return h.StructuredResult(task), nil
 		},
 	}
 }
@@ -103,6 +107,8 @@ func getTask(ws string) t.Tool {
 				"project": map[string]any{"type": "string"}, "epic_id": map[string]any{"type": "string"},
 				"story_id": map[string]any{"type": "string"}, "task_id": map[string]any{"type": "string"},
 			}, Required: []string{"project", "epic_id", "story_id", "task_id"}},
+			OutputSchema: issueOutput,
+			Annotations:  readOnly("Get Task"),
 		},
 		Handler: func(args map[string]any) (*t.ToolResult, error) {
 			p := filepath.Join(h.ProjectDir(ws, h.GetString(args, "project")),
@@ -112,7 +118,7 @@ func getTask(ws string) t.Tool {
 			if err := toon.ParseFile(p, &task); err != nil {
 				return h.ErrorResult(err.Error()), nil
 			}
-			return h.JSONResult(task), nil
+			return h.StructuredResult(task), nil
 		},
 	}
 }
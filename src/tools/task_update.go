@@ -22,6 +22,8 @@ func updateTask(ws string) t.Tool {
 				"title": map[string]any{"type": "string"}, "description": map[string]any{"type": "string"},
 				"status": map[string]any{"type": "string"}, "priority": map[string]any{"type": "string"},
 			}, Required: []string{"project", "epic_id", "story_id", "task_id"}},
+			OutputSchema: issueOutput,
+			Annotations:  idempotent("Update Task"),
 		},
 		Handler: func(args map[string]any) (*t.ToolResult, error) {
 			slug := h.GetString(args, "project")
@@ -71,7 +73,7 @@ func updateTask(ws string) t.Tool {
 				ps.UpdatedAt = h.Now()
 				_ = toon.WriteFile(statusPath, &ps)
 			}
-			return h.JSONResult(task), nil
+			return h.StructuredResult(task), nil
 		},
 	}
 }
@@ -84,6 +86,8 @@ func deleteTask(ws string) t.Tool {
 				"project": map[string]any{"type": "string"}, "epic_id": map[string]any{"type": "string"},
 				"story_id": map[string]any{"type": "string"}, "task_id": map[string]any{"type": "string"},
 			}, Required: []string{"project", "epic_id", "story_id", "task_id"}},
+			OutputSchema: messageOutput,
+			Annotations:  destructive("Delete Task"),
 		},
 		Handler: func(args map[string]any) (*t.ToolResult, error) {
 			slug := h.GetString(args, "project")
@@ -104,7 +108,7 @@ func deleteTask(ws string) t.Tool {
 				ps.UpdatedAt = h.Now()
 				_ = toon.WriteFile(statusPath, &ps)
 			}
-			return h.TextResult(fmt.Sprintf("deleted task %s", taskID)), nil
+			return h.MessageResult(fmt.Sprintf("deleted task %s", taskID)), nil
 		},
 	}
 }
@@ -27,22 +27,27 @@ func openSession(u *t.UsageData) *t.UsageSession {
 	return nil
 }
 
+var usageOutput = objectOutput(map[string]any{
+	"totals":          h.SchemaOf(t.UsageTotals{}),
+	"recent_sessions": map[string]any{"type": []string{"array", "null"}, "items": h.SchemaOf(t.UsageSession{})},
+}, "totals", "recent_sessions")
+
 // Usage returns token usage tracking tools.
 func Usage(ws string) []t.Tool {
 	return []t.Tool{
-		{Definition: t.ToolDefinition{Name: "get_usage", Description: "Get usage totals and recent sessions", InputSchema: t.InputSchema{Type: "object"}}, Handler: func(a map[string]any) (*t.ToolResult, error) {
+		{Definition: t.ToolDefinition{Name: "get_usage", Description: "Get usage totals and recent sessions", InputSchema: t.InputSchema{Type: "object"}, OutputSchema: usageOutput, Annotations: readOnly("Get Usage")}, Handler: func(a map[string]any) (*t.ToolResult, error) {
 			u := loadUsage(ws)
 			last := u.Sessions
 			if len(last) > 10 {
 				last = last[len(last)-10:]
 			}
-			return h.JSONResult(map[string]any{"totals": u.Totals, "recent_sessions": last}), nil
+			return h.StructuredResult(map[string]any{"totals": u.Totals, "recent_sessions": last}), nil
 		}},
 		{Definition: t.ToolDefinition{Name: "record_usage", Description: "Record token usage for current session", InputSchema: t.InputSchema{Type: "object", Properties: map[string]any{
 			"provider": map[string]any{"type": "string"}, "model": map[string]any{"type": "string"},
 			"input_tokens": map[string]any{"type": "number"}, "output_tokens": map[string]any{"type": "number"},
 			"cost": map[string]any{"type": "number"},
-		}, Required: []string{"input_tokens", "output_tokens"}}}, Handler: func(a map[string]any) (*t.ToolResult, error) {
+		}, Required: []string{"input_tokens", "output_tokens"}}, OutputSchema: objectOutput(map[string]any{"session_input": intType, "session_output": intType}, "session_input", "session_output"), Annotations: additive("Record Usage")}, Handler: func(a map[string]any) (*t.ToolResult, error) {
 			u := loadUsage(ws)
 			s := openSession(u)
 			if s == nil {
@@ -64,19 +69,19 @@ func Usage(ws string) []t.Tool {
 			if err := saveUsage(ws, u); err != nil {
 				return h.ErrorResult(err.Error()), nil
 			}
-			return h.JSONResult(map[string]any{"session_input": s.TotalInput, "session_output": s.TotalOutput}), nil
+			return h.StructuredResult(map[string]any{"session_input": s.TotalInput, "session_output": s.TotalOutput}), nil
 		}},
-		{Definition: t.ToolDefinition{Name: "reset_session_usage", Description: "End the current usage session", InputSchema: t.InputSchema{Type: "object"}}, Handler: func(a map[string]any) (*t.ToolResult, error) {
+		{Definition: t.ToolDefinition{Name: "reset_session_usage", Description: "End the current usage session", InputSchema: t.InputSchema{Type: "object"}, OutputSchema: messageOutput, Annotations: idempotent("Reset Session Usage")}, Handler: func(a map[string]any) (*t.ToolResult, error) {
 			u := loadUsage(ws)
 			s := openSession(u)
 			if s == nil {
-				return h.TextResult("no open session"), nil
+				return h.MessageResult("no open session"), nil
 			}
 			s.EndedAt = h.Now()
 			if err := saveUsage(ws, u); err != nil {
 				return h.ErrorResult(err.Error()), nil
 			}
-			return h.TextResult("session ended"), nil
+			return h.MessageResult("session ended"), nil
 		}},
 	}
 }
@@ -1,6 +1,7 @@
 package tools
 
 import (
+	"context"
 	"fmt"
 	"sort"
 
@@ -44,6 +45,8 @@ func getNextTask(ws string) t.Tool {
 			InputSchema: t.InputSchema{Type: "object", Properties: map[string]any{
 				"project": map[string]any{"type": "string"},
 			}, Required: []string{"project"}},
+			OutputSchema: nextTaskOutput,
+			Annotations:  readOnly("Get Next Task"),
 		},
 		Handler: func(args map[string]any) (*t.ToolResult, error) {
 			tasks := h.ScanAllTasks(ws, h.GetString(args, "project"))
@@ -57,7 +60,7 @@ func getNextTask(ws string) t.Tool {
 				}
 			}
 			if len(actionable) == 0 {
-				return h.TextResult("no actionable tasks"), nil
+				return h.MessageResult("no actionable tasks"), nil
 			}
 			sort.Slice(actionable, func(i, j int) bool {
 				ti, tj := typePriority[actionable[i].Data.Type], typePriority[actionable[j].Data.Type]
@@ -66,26 +69,28 @@ func getNextTask(ws string) t.Tool {
 				}
 				return statusPriority[actionable[i].Data.Status] < statusPriority[actionable[j].Data.Status]
 			})
-			return h.JSONResult(actionable[0].Data), nil
+			return h.StructuredResult(actionable[0].Data), nil
 		},
 	}
 }
 
 func searchIssues(ws string) t.Tool {
-	return t.Tool{
-		Definition: t.ToolDefinition{
+	return t.NewContextTool(
+		t.ToolDefinition{
 			Name: "search", Description: "Search issues by text, optional type filter",
 			InputSchema: t.InputSchema{Type: "object", Properties: map[string]any{
 				"project": map[string]any{"type": "string"},
 				"query":   map[string]any{"type": "string"},
 				"type":    map[string]any{"type": "string", "enum": []string{"epic", "story", "task", "bug", "hotfix"}},
 			}, Required: []string{"project", "query"}},
+			OutputSchema: h.ListOutputSchema("issues", t.IssueData{}),
+			Annotations:  readOnly("Search Issues"),
 		},
-		Handler: func(args map[string]any) (*t.ToolResult, error) {
+		func(ctx context.Context, args map[string]any) (*t.ToolResult, error) {
 			slug := h.GetString(args, "project")
 			query := h.GetString(args, "query")
 			typeFilter := h.GetString(args, "type")
-			issues := h.ScanAllIssues(ws, slug)
+			issues := h.ScanAllIssuesContext(ctx, ws, slug)
 			var matches []t.IssueData
 			for _, iss := range issues {
 				if typeFilter != "" && iss.Data.Type != typeFilter {
@@ -96,9 +101,9 @@ func searchIssues(ws string) t.Tool {
 					matches = append(matches, iss.Data)
 				}
 			}
-			return h.JSONResult(matches), nil
+			return h.ListResult("issues", matches), nil
 		},
-	}
+	)
 }
 
 func getWorkflowStatus(ws string) t.Tool {
@@ -108,6 +113,13 @@ func getWorkflowStatus(ws string) t.Tool {
 			InputSchema: t.InputSchema{Type: "object", Properties: map[string]any{
 				"project": map[string]any{"type": "string"},
 			}, Required: []string{"project"}},
+			OutputSchema: objectOutput(map[string]any{
+				"total": intType, "done": intType, "completion_pct": strType,
+				"by_status": map[string]any{"type": "object"}, "by_type": map[string]any{"type": "object"},
+				"blocked": nullableStrList, "in_progress": nullableStrList, "ready": nullableStrList,
+				"testing": nullableStrList, "documenting": nullableStrList, "reviewing": nullableStrList,
+			}, "total", "done", "completion_pct", "by_status", "by_type"),
+			Annotations: readOnly("Get Workflow Status"),
 		},
 		Handler: func(args map[string]any) (*t.ToolResult, error) {
 			tasks := h.ScanAllTasks(ws, h.GetString(args, "project"))
@@ -142,7 +154,7 @@ func getWorkflowStatus(ws string) t.Tool {
 			if total > 0 {
 				pct = float64(done) / float64(total) * 100
 			}
-			return h.JSONResult(map[string]any{
+			return h.StructuredResult(map[string]any{
 				"total": total, "done": done, "completion_pct": fmt.Sprintf("%.1f", pct),
 				"by_status": byStatus, "by_type": byType,
 				"blocked": blocked, "in_progress": inProgress, "ready": ready,
@@ -18,6 +18,8 @@ func setCurrentTask(ws string) t.Tool {
 				"project": map[string]any{"type": "string"}, "epic_id": map[string]any{"type": "string"},
 				"story_id": map[string]any{"type": "string"}, "task_id": map[string]any{"type": "string"},
 			}, Required: []string{"project", "epic_id", "story_id", "task_id"}},
+			OutputSchema: issueOutput,
+			Annotations:  idempotent("Set Current Task"),
 		},
 		Handler: func(args map[string]any) (*t.ToolResult, error) {
 			slug := h.GetString(args, "project")
@@ -72,7 +74,7 @@ func setCurrentTask(ws string) t.Tool {
 				ps.UpdatedAt = h.Now()
 				_ = toon.WriteFile(statusPath, &ps)
 			}
-			return h.JSONResult(task), nil
+			return h.StructuredResult(task), nil
 		},
 	}
 }
@@ -85,6 +87,8 @@ func completeTask(ws string) t.Tool {
 				"project": map[string]any{"type": "string"}, "epic_id": map[string]any{"type": "string"},
 				"story_id": map[string]any{"type": "string"}, "task_id": map[string]any{"type": "string"},
 			}, Required: []string{"project", "epic_id", "story_id", "task_id"}},
+			OutputSchema: issueOutput,
+			Annotations:  idempotent("Complete Task"),
 		},
 		Handler: func(args map[string]any) (*t.ToolResult, error) {
 			slug := h.GetString(args, "project")
@@ -141,7 +145,7 @@ func completeTask(ws string) t.Tool {
 				ps.UpdatedAt = h.Now()
 				_ = toon.WriteFile(statusPath, &ps)
 			}
-			return h.JSONResult(task), nil
+			return h.StructuredResult(task), nil
 		},
 	}
 }
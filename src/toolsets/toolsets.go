@@ -0,0 +1,243 @@
+// Package toolsets selects which built-in tools a server exposes. Tools
+// come in groups named after their source file; a Selection enables or
+// disables groups and single tools, and profiles are named selections.
+package toolsets
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	t "github.com/orchestra-mcp/mcp/src/types"
+	"gopkg.in/yaml.v3"
+)
+
+// Groups lists every toolset name.
+var Groups = []string{
+	"project", "epic", "story", "task", "workflow", "lifecycle", "prd",
+	"bugfix", "memory", "usage", "artifacts", "readme", "claude", "audit",
+}
+
+// Profiles are the built-in named selections. A workspace config can add
+// its own or override these.
+var Profiles = map[string]Selection{
+	// admin exposes every tool.
+	"admin": {},
+	// agent is for coding agents working through tasks: no workspace setup
+	// tools and nothing that deletes or overwrites data.
+	"agent": {DisabledToolsets: []string{"claude", "audit"}, NoDestructive: true},
+	// readonly exposes the tools that never modify the workspace, except
+	// the audit log, which shows every caller's activity.
+	"readonly": {DisabledToolsets: []string{"audit"}, ReadOnly: true},
+}
+
+// DefaultProfile applies when neither the config nor a flag names one.
+const DefaultProfile = "admin"
+
+// Selection picks tools. An empty Toolsets enables every group. Tool rules
+// win over group rules, and Disabled wins over Enabled.
+type Selection struct {
+	Toolsets         []string  `yaml:"toolsets"`
+	DisabledToolsets []string  `yaml:"disabled_toolsets"`
+	Tools            ToolRules `yaml:"tools"`
+	// ReadOnly keeps only tools annotated readOnlyHint.
+	ReadOnly bool `yaml:"read_only"`
+	// NoDestructive drops tools annotated destructiveHint.
+	NoDestructive bool `yaml:"no_destructive"`
+}
+
+// ToolRules enable or disable single tools by name.
+type ToolRules struct {
+	Enabled  []string `yaml:"enabled"`
+	Disabled []string `yaml:"disabled"`
+}
+
+// Config is the tool selection in .orchestra/config.yaml. The top-level
+// selection is applied over the chosen profile.
+type Config struct {
+	Profile   string               `yaml:"profile"`
+	Selection Selection            `yaml:",inline"`
+	Profiles  map[string]Selection `yaml:"profiles"`
+}
+
+// ConfigPath returns the workspace config file, .orchestra/config.yaml.
+func ConfigPath(ws string) string {
+	return filepath.Join(ws, ".orchestra", "config.yaml")
+}
+
+// Load reads the workspace config. A missing file is an empty config.
+func Load(ws string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(ConfigPath(ws))
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("%s: %w", ConfigPath(ws), err)
+	}
+	return cfg, nil
+}
+
+// Resolve builds the effective selection. profile and toolsets come from
+// flags and override the config when set.
+func (c Config) Resolve(profile string, toolsets []string) (Selection, error) {
+	if profile == "" {
+		profile = c.Profile
+	}
+	if profile == "" {
+		profile = DefaultProfile
+	}
+	base, ok := c.Profiles[profile]
+	if !ok {
+		if base, ok = Profiles[profile]; !ok {
+			return Selection{}, fmt.Errorf("unknown profile %q (have %s)", profile, strings.Join(c.profileNames(), ", "))
+		}
+	}
+	sel := base.merge(c.Selection)
+	if len(toolsets) > 0 {
+		sel.Toolsets = toolsets
+	}
+	return sel, sel.Validate()
+}
+
+func (c Config) profileNames() []string {
+	names := make([]string, 0, len(Profiles)+len(c.Profiles))
+	for name := range Profiles {
+		names = append(names, name)
+	}
+	for name := range c.Profiles {
+		if _, builtin := Profiles[name]; !builtin {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// merge applies over on top of s: a non-empty Toolsets replaces s's, the
+// other lists add up and the flags combine.
+func (s Selection) merge(over Selection) Selection {
+	out := Selection{
+		Toolsets:         s.Toolsets,
+		DisabledToolsets: concat(s.DisabledToolsets, over.DisabledToolsets),
+		Tools: ToolRules{
+			Enabled:  concat(s.Tools.Enabled, over.Tools.Enabled),
+			Disabled: concat(s.Tools.Disabled, over.Tools.Disabled),
+		},
+		ReadOnly:      s.ReadOnly || over.ReadOnly,
+		NoDestructive: s.NoDestructive || over.NoDestructive,
+	}
+	if len(over.Toolsets) > 0 {
+		out.Toolsets = over.Toolsets
+	}
+	return out
+}
+
+// ProxyGroup is the toolset of the tools proxied from an upstream server.
+func ProxyGroup(server string) string {
+	return "proxy:" + server
+}
+
+// Validate rejects unknown toolset names. Proxy groups are accepted for
+// any server name, since upstreams are mounted after the selection is made.
+func (s Selection) Validate() error {
+	for _, g := range concat(s.Toolsets, s.DisabledToolsets) {
+		if !slices.Contains(Groups, g) && !strings.HasPrefix(g, ProxyGroup("")) {
+			return fmt.Errorf("unknown toolset %q (have %s)", g, strings.Join(Groups, ", "))
+		}
+	}
+	return nil
+}
+
+// ValidateTools rejects tool rules naming none of the built-in tools in
+// groups, keyed by toolset. Proxied "<server>.<tool>" names are accepted,
+// since upstreams are mounted after the selection is made.
+func (s Selection) ValidateTools(groups map[string][]t.Tool) error {
+	known := make(map[string]bool)
+	for _, tools := range groups {
+		for _, tool := range tools {
+			known[tool.Definition.Name] = true
+		}
+	}
+	for _, rule := range []struct {
+		key   string
+		names []string
+	}{{"enabled", s.Tools.Enabled}, {"disabled", s.Tools.Disabled}} {
+		for _, name := range rule.names {
+			if !known[name] && !strings.Contains(name, ".") {
+				return fmt.Errorf("unknown tool %q in tools.%s", name, rule.key)
+			}
+		}
+	}
+	return nil
+}
+
+// Allows reports whether a tool from group is exposed.
+func (s Selection) Allows(group string, def t.ToolDefinition) bool {
+	switch {
+	case slices.Contains(s.Tools.Disabled, def.Name):
+		return false
+	case slices.Contains(s.Tools.Enabled, def.Name):
+		return true
+	case slices.Contains(s.DisabledToolsets, group):
+		return false
+	case len(s.Toolsets) > 0 && !slices.Contains(s.Toolsets, group):
+		return false
+	case s.ReadOnly && !readOnly(def):
+		return false
+	case s.NoDestructive && destructive(def):
+		return false
+	}
+	return true
+}
+
+// Filter returns the tools of group that s allows.
+func (s Selection) Filter(group string, tools []t.Tool) []t.Tool {
+	out := make([]t.Tool, 0, len(tools))
+	for _, tool := range tools {
+		if s.Allows(group, tool.Definition) {
+			out = append(out, tool)
+		}
+	}
+	return out
+}
+
+// ParseList splits a comma-separated flag value, dropping blanks.
+func ParseList(v string) []string {
+	var out []string
+	for _, part := range strings.Split(v, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+func readOnly(def t.ToolDefinition) bool {
+	a := def.Annotations
+	return a != nil && a.ReadOnlyHint != nil && *a.ReadOnlyHint
+}
+
+// destructive follows the MCP defaults: a tool that is not read-only is
+// destructive unless it says otherwise.
+func destructive(def t.ToolDefinition) bool {
+	if readOnly(def) {
+		return false
+	}
+	a := def.Annotations
+	return a == nil || a.DestructiveHint == nil || *a.DestructiveHint
+}
+
+func concat(a, b []string) []string {
+	if len(b) == 0 {
+		return a
+	}
+	return append(slices.Clip(a), b...)
+}
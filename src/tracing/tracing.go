@@ -0,0 +1,138 @@
+// Package tracing sets up OpenTelemetry for the server. Spans cover each
+// JSON-RPC request, each tool handler, TOON scans and the gRPC calls to the
+// Rust engine, which receive the trace context through gRPC metadata.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentation names the tracer spans are created with.
+const instrumentation = "github.com/orchestra-mcp/mcp"
+
+// Config selects where spans are exported. With neither field set, and no
+// OTEL_EXPORTER_OTLP_ENDPOINT in the environment, tracing stays off.
+type Config struct {
+	// OTLPEndpoint is an OTLP/gRPC collector address such as
+	// "localhost:4317". Plain-text gRPC is used unless it starts with
+	// "https://".
+	OTLPEndpoint string
+	// File receives spans as JSON lines, for offline debugging.
+	File string
+	// ServiceName defaults to "orchestra-mcp".
+	ServiceName    string
+	ServiceVersion string
+}
+
+// Enabled reports whether c exports spans anywhere.
+func (c Config) Enabled() bool {
+	return c.OTLPEndpoint != "" || c.File != "" || os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != ""
+}
+
+// Setup installs a global tracer provider exporting to the configured
+// destinations and the W3C trace-context propagator. The returned func
+// flushes pending spans and closes the exporters; it is a no-op when
+// tracing is off.
+func Setup(ctx context.Context, c Config) (shutdown func(context.Context) error, err error) {
+	noop := func(context.Context) error { return nil }
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	if !c.Enabled() {
+		return noop, nil
+	}
+	if c.ServiceName == "" {
+		c.ServiceName = "orchestra-mcp"
+	}
+
+	var exporters []sdktrace.SpanExporter
+	var closers []func() error
+	// fail releases the exporters and files already opened.
+	fail := func(err error) (func(context.Context) error, error) {
+		for _, exp := range exporters {
+			_ = exp.Shutdown(ctx)
+		}
+		for _, c := range closers {
+			_ = c()
+		}
+		return noop, err
+	}
+	if c.OTLPEndpoint != "" || os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" {
+		var eopts []otlptracegrpc.Option
+		if c.OTLPEndpoint != "" {
+			eopts = append(eopts, otlptracegrpc.WithEndpointURL(endpointURL(c.OTLPEndpoint)))
+		}
+		exp, err := otlptracegrpc.New(ctx, eopts...)
+		if err != nil {
+			return fail(fmt.Errorf("otlp exporter: %w", err))
+		}
+		exporters = append(exporters, exp)
+	}
+	if c.File != "" {
+		f, err := os.OpenFile(c.File, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+		if err != nil {
+			return fail(fmt.Errorf("trace file: %w", err))
+		}
+		closers = append(closers, f.Close)
+		exp, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			return fail(fmt.Errorf("file exporter: %w", err))
+		}
+		exporters = append(exporters, exp)
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		semconv.ServiceName(c.ServiceName),
+		semconv.ServiceVersion(c.ServiceVersion),
+	))
+	if err != nil {
+		return fail(err)
+	}
+	opts := []sdktrace.TracerProviderOption{sdktrace.WithResource(res)}
+	for _, exp := range exporters {
+		opts = append(opts, sdktrace.WithBatcher(exp))
+	}
+	tp := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(tp)
+	return func(ctx context.Context) error {
+		errs := []error{tp.Shutdown(ctx)}
+		for _, c := range closers {
+			errs = append(errs, c())
+		}
+		return errors.Join(errs...)
+	}, nil
+}
+
+// endpointURL turns a bare host:port into a plain-text gRPC URL.
+func endpointURL(endpoint string) string {
+	if strings.HasPrefix(endpoint, "http://") || strings.HasPrefix(endpoint, "https://") {
+		return endpoint
+	}
+	return "http://" + endpoint
+}
+
+// Start begins a span named name as a child of any span in ctx.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentation).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err on span, if any, and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
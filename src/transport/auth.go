@@ -0,0 +1,65 @@
+package transport
+
+import (
+	"github.com/orchestra-mcp/mcp/src/auth"
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+// SetPrincipal records who authenticated the connection.
+func (s *Session) SetPrincipal(p *auth.Principal) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.principal = p
+	s.mu.Unlock()
+}
+
+// Principal returns who authenticated the connection, or nil.
+func (s *Session) Principal() *auth.Principal {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.principal
+}
+
+// SetAuthenticator enables per-tool authorization. Transports that
+// authenticate requests store the principal on the session; calls are then
+// checked against the scope each tool requires. A nil or empty
+// Authenticator allows every call.
+func (s *MCPServer) SetAuthenticator(a *auth.Authenticator) {
+	s.mu.Lock()
+	s.auth = a
+	s.mu.Unlock()
+}
+
+func (s *MCPServer) authenticator() *auth.Authenticator {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.auth
+}
+
+// readMethods need the read scope: they expose workspace content.
+var readMethods = map[string]bool{
+	"resources/read": true, "resources/subscribe": true,
+	"prompts/get": true, "completion/complete": true,
+}
+
+// authorizeMethod answers the request with an auth error and returns false
+// when the session may not call a read method.
+func (s *MCPServer) authorizeMethod(req *types.JSONRPCRequest, w ResponseWriter) bool {
+	if !readMethods[req.Method] {
+		return true
+	}
+	return s.authorized(req, w, s.authenticator().Require(w.Session().Principal(), auth.ScopeRead))
+}
+
+func (s *MCPServer) authorized(req *types.JSONRPCRequest, w ResponseWriter, err error) bool {
+	if err == nil {
+		return true
+	}
+	_ = w.WriteError(req.ID, auth.Code(err), err.Error())
+	return false
+}
@@ -0,0 +1,76 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+// maxCompletionValues is the MCP limit on values per completion response.
+const maxCompletionValues = 100
+
+func (s *MCPServer) handleCompleteW(req *types.JSONRPCRequest, w ResponseWriter) {
+	var params types.CompleteParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		_ = w.WriteError(req.ID, -32602, "invalid params")
+		return
+	}
+	complete, err := s.completer(params.Ref)
+	if err != nil {
+		_ = w.WriteError(req.ID, -32602, err.Error())
+		return
+	}
+	args := map[string]string{}
+	if params.Context != nil {
+		for k, v := range params.Context.Arguments {
+			args[k] = v
+		}
+	}
+	var candidates []string
+	if complete != nil {
+		candidates = complete(params.Argument.Name, args)
+	}
+	_ = w.WriteResult(req.ID, types.CompleteResult{Completion: filterCompletions(candidates, params.Argument.Value)})
+}
+
+// completer finds the completion func of the referenced prompt or resource.
+// A known reference without one completes to nothing.
+func (s *MCPServer) completer(ref types.CompletionRef) (types.CompletionFunc, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	switch ref.Type {
+	case "ref/prompt":
+		p, ok := s.prompts[ref.Name]
+		if !ok {
+			return nil, fmt.Errorf("unknown prompt: %s", ref.Name)
+		}
+		return p.Complete, nil
+	case "ref/resource":
+		r, ok := s.resources[ref.URI]
+		if !ok {
+			return nil, fmt.Errorf("unknown resource: %s", ref.URI)
+		}
+		return r.Complete, nil
+	}
+	return nil, fmt.Errorf("unknown reference type: %q", ref.Type)
+}
+
+// filterCompletions keeps candidates starting with value (case-insensitive)
+// and truncates to maxCompletionValues.
+func filterCompletions(candidates []string, value string) types.Completion {
+	prefix := strings.ToLower(value)
+	values := []string{}
+	for _, c := range candidates {
+		if strings.HasPrefix(strings.ToLower(c), prefix) {
+			values = append(values, c)
+		}
+	}
+	out := types.Completion{Values: values, Total: len(values)}
+	if len(values) > maxCompletionValues {
+		out.Values = values[:maxCompletionValues]
+		out.HasMore = true
+	}
+	return out
+}
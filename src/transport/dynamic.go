@@ -2,20 +2,70 @@ package transport
 
 // UnregisterTool removes a tool by flat name and cleans up aliases.
 func (s *MCPServer) UnregisterTool(name string) {
-	delete(s.tools, name)
-	for alias, flat := range s.toolAlias {
-		if flat == name {
-			delete(s.toolAlias, alias)
+	s.UnregisterTools([]string{name})
+}
+
+// UnregisterTools removes tools by flat name and notifies clients once if
+// any were registered.
+func (s *MCPServer) UnregisterTools(names []string) {
+	s.mu.Lock()
+	removed := false
+	for _, name := range names {
+		if _, ok := s.tools[name]; !ok {
+			continue
 		}
+		removed = true
+		delete(s.tools, name)
+		for alias, flat := range s.toolAlias {
+			if flat == name {
+				delete(s.toolAlias, alias)
+			}
+		}
+	}
+	s.mu.Unlock()
+	if removed {
+		s.listChanged(listTools)
 	}
 }
 
 // UnregisterResource removes a resource by URI.
 func (s *MCPServer) UnregisterResource(uri string) {
-	delete(s.resources, uri)
+	s.UnregisterResources([]string{uri})
+}
+
+// UnregisterResources removes resources by URI and notifies clients once.
+func (s *MCPServer) UnregisterResources(uris []string) {
+	s.mu.Lock()
+	removed := false
+	for _, uri := range uris {
+		if _, ok := s.resources[uri]; ok {
+			removed = true
+			delete(s.resources, uri)
+		}
+	}
+	s.mu.Unlock()
+	if removed {
+		s.listChanged(listResources)
+	}
 }
 
 // UnregisterPrompt removes a prompt by name.
 func (s *MCPServer) UnregisterPrompt(name string) {
-	delete(s.prompts, name)
+	s.UnregisterPrompts([]string{name})
+}
+
+// UnregisterPrompts removes prompts by name and notifies clients once.
+func (s *MCPServer) UnregisterPrompts(names []string) {
+	s.mu.Lock()
+	removed := false
+	for _, name := range names {
+		if _, ok := s.prompts[name]; ok {
+			removed = true
+			delete(s.prompts, name)
+		}
+	}
+	s.mu.Unlock()
+	if removed {
+		s.listChanged(listPrompts)
+	}
 }
@@ -0,0 +1,403 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/orchestra-mcp/mcp/src/auth"
+	"github.com/orchestra-mcp/mcp/src/metrics"
+	"github.com/orchestra-mcp/mcp/src/types"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// SessionHeader carries the session ID for the Streamable HTTP transport.
+const SessionHeader = "Mcp-Session-Id"
+
+// HTTPEndpoint is the default path of the Streamable HTTP endpoint.
+const HTTPEndpoint = "/mcp"
+
+// HTTPHandler serves the MCP Streamable HTTP transport on a single endpoint.
+// POST carries client messages, GET opens a server-to-client SSE stream,
+// DELETE terminates the session. Requests from browser origins other than
+// localhost and those given to SetAllowedOrigins are refused, and once the
+// server has an Authenticator every request needs a token; a session only
+// accepts the token that initialized it.
+type HTTPHandler struct {
+	server   *MCPServer
+	sessions *SSESessionManager
+}
+
+// NewHTTPHandler creates a Streamable HTTP handler for the given server.
+func NewHTTPHandler(s *MCPServer) *HTTPHandler {
+	return &HTTPHandler{server: s, sessions: NewSSESessionManager()}
+}
+
+// Sessions returns the session manager backing this handler.
+func (hh *HTTPHandler) Sessions() *SSESessionManager { return hh.sessions }
+
+// SetAllowedOrigins lists the browser origins, e.g.
+// "https://app.example.com", that may call the Streamable HTTP endpoint
+// besides localhost ones.
+func (s *MCPServer) SetAllowedOrigins(origins ...string) {
+	s.mu.Lock()
+	s.origins = origins
+	s.mu.Unlock()
+}
+
+// ListenAndServeHTTP serves the Streamable HTTP transport on addr at
+// HTTPEndpoint. Bind it to localhost, e.g. "127.0.0.1:8080", unless
+// tokens are configured.
+func (s *MCPServer) ListenAndServeHTTP(addr string) error {
+	mux := http.NewServeMux()
+	hh := NewHTTPHandler(s)
+	metrics.SSESessions.SetFunc(func() float64 { return float64(hh.sessions.Count()) }, "http")
+	mux.Handle(HTTPEndpoint, hh)
+	srv := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	return srv.ListenAndServe()
+}
+
+func (hh *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Continue the client's trace when it sends a traceparent header.
+	r = r.WithContext(otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header)))
+	// Browsers always send Origin; checking it keeps web pages from
+	// reaching a localhost server through DNS rebinding.
+	if !hh.allowedOrigin(r.Header.Get("Origin")) {
+		writeHTTPError(w, http.StatusForbidden, nil, -32600, "origin not allowed")
+		return
+	}
+	principal, err := hh.authenticate(r)
+	if err != nil {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="orchestra-mcp"`)
+		writeHTTPError(w, http.StatusUnauthorized, nil, auth.Code(err), err.Error())
+		return
+	}
+	switch r.Method {
+	case http.MethodPost:
+		hh.handlePost(w, r, principal)
+	case http.MethodGet:
+		hh.handleStream(w, r, principal)
+	case http.MethodDelete:
+		hh.handleDelete(w, r, principal)
+	default:
+		w.Header().Set("Allow", "GET, POST, DELETE")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// allowedOrigin accepts requests without an Origin, such as those from
+// non-browser clients, localhost origins and the configured ones.
+func (hh *HTTPHandler) allowedOrigin(origin string) bool {
+	if origin == "" {
+		return true
+	}
+	if u, err := url.Parse(origin); err == nil {
+		host := u.Hostname()
+		if ip := net.ParseIP(host); host == "localhost" || ip != nil && ip.IsLoopback() {
+			return true
+		}
+	}
+	hh.server.mu.RLock()
+	defer hh.server.mu.RUnlock()
+	return slices.Contains(hh.server.origins, strings.TrimSuffix(origin, "/"))
+}
+
+// authenticate resolves the request's token, returning a nil principal
+// while the server has no tokens configured.
+func (hh *HTTPHandler) authenticate(r *http.Request) (*auth.Principal, error) {
+	a := hh.server.authenticator()
+	if !a.Enabled() {
+		return nil, nil
+	}
+	return a.Authenticate(auth.Credential(r.Header.Get("Authorization"), r.Header.Get("X-API-Key")))
+}
+
+func (hh *HTTPHandler) handlePost(w http.ResponseWriter, r *http.Request, principal *auth.Principal) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxScanSize))
+	if err != nil {
+		writeHTTPError(w, http.StatusBadRequest, nil, -32700, "parse error")
+		return
+	}
+	msgs, batch, errResp := splitBatch(body)
+	if errResp != nil {
+		writeHTTPError(w, http.StatusBadRequest, nil, errResp.Error.Code, errResp.Error.Message)
+		return
+	}
+	if batch {
+		hh.handleBatch(w, r, body, principal)
+		return
+	}
+	req, reply, errResp := decodeMessage(msgs[0])
+	if errResp != nil {
+		writeHTTPError(w, http.StatusBadRequest, errResp.ID, errResp.Error.Code, errResp.Error.Message)
+		return
+	}
+	if reply != nil {
+		// A response to a server-initiated request such as sampling.
+		sess, status := hh.lookup(r, principal)
+		if sess == nil {
+			writeHTTPError(w, status, nil, lookupCode(status), http.StatusText(status))
+			return
+		}
+		sess.State.deliver(*reply)
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	if req.Method == "initialize" {
+		hh.initialize(w, r, req, principal)
+		return
+	}
+	sess, status := hh.lookup(r, principal)
+	if sess == nil {
+		writeHTTPError(w, status, req.ID, lookupCode(status), http.StatusText(status))
+		return
+	}
+
+	// Notifications and responses get no body.
+	if req.ID == nil {
+		hh.server.HandleRequest(req, NewSSEWriter(sess))
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	if req.Method == "tools/call" && accepts(r, "text/event-stream") {
+		sw := newHTTPStreamWriter(w, sess.State)
+		if sw != nil {
+			hh.server.HandleRequestContext(r.Context(), req, sw)
+			return
+		}
+	}
+
+	jw := &httpJSONWriter{session: sess.State, notify: NewSSEWriter(sess)}
+	hh.server.HandleRequestContext(r.Context(), req, jw)
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(jw.buf.Bytes())
+}
+
+// initialize answers an initialize request on a new session. The session
+// is only advertised when the handshake succeeds; a failed one, or an
+// initialize sent as a notification, leaves no session behind.
+func (hh *HTTPHandler) initialize(w http.ResponseWriter, r *http.Request, req *types.JSONRPCRequest, principal *auth.Principal) {
+	sess := hh.sessions.Create()
+	sess.State.SetPrincipal(principal)
+	jw := &httpJSONWriter{session: sess.State, notify: NewSSEWriter(sess)}
+	hh.server.HandleRequestContext(r.Context(), req, jw)
+	if req.ID == nil || jw.failed {
+		hh.sessions.Remove(sess.ID)
+	} else {
+		w.Header().Set(SessionHeader, sess.ID)
+	}
+	if req.ID == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(jw.buf.Bytes())
+}
+
+// handleBatch answers a JSON-RPC batch with one JSON array, or 202 when it
+// holds only notifications and responses. Batches need an existing session;
+// initialize cannot be batched.
+func (hh *HTTPHandler) handleBatch(w http.ResponseWriter, r *http.Request, body []byte, principal *auth.Principal) {
+	sess, status := hh.lookup(r, principal)
+	if sess == nil {
+		writeHTTPError(w, status, nil, lookupCode(status), http.StatusText(status))
+		return
+	}
+	jw := &httpJSONWriter{session: sess.State, notify: NewSSEWriter(sess)}
+	hh.server.HandleMessage(r.Context(), body, jw)
+	if jw.buf.Len() == 0 {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(jw.buf.Bytes())
+}
+
+// handleStream opens a long-lived SSE stream for server-initiated messages.
+// A Last-Event-ID header replays buffered events after that ID.
+func (hh *HTTPHandler) handleStream(w http.ResponseWriter, r *http.Request, principal *auth.Principal) {
+	if !accepts(r, "text/event-stream") {
+		w.WriteHeader(http.StatusNotAcceptable)
+		return
+	}
+	sess, status := hh.lookup(r, principal)
+	if sess == nil {
+		w.WriteHeader(status)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	setSSEHeaders(w)
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	flush := func() error { flusher.Flush(); return nil }
+	_ = sess.Stream(r.Context(), w, flush, r.Header.Get("Last-Event-ID"))
+}
+
+func (hh *HTTPHandler) handleDelete(w http.ResponseWriter, r *http.Request, principal *auth.Principal) {
+	sess, status := hh.lookup(r, principal)
+	if sess == nil {
+		w.WriteHeader(status)
+		return
+	}
+	hh.sessions.Remove(sess.ID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// lookup resolves the session named by the Mcp-Session-Id header. Only
+// the principal that initialized a session may use it.
+func (hh *HTTPHandler) lookup(r *http.Request, principal *auth.Principal) (*SSESession, int) {
+	id := r.Header.Get(SessionHeader)
+	if id == "" {
+		return nil, http.StatusBadRequest
+	}
+	sess, ok := hh.sessions.Get(id)
+	if !ok {
+		return nil, http.StatusNotFound
+	}
+	if owner := sess.State.Principal(); owner != nil && owner != principal {
+		return nil, http.StatusForbidden
+	}
+	return sess, http.StatusOK
+}
+
+// lookupCode is the JSON-RPC error code for a failed lookup.
+func lookupCode(status int) int {
+	if status == http.StatusForbidden {
+		return auth.CodeForbidden
+	}
+	return -32600
+}
+
+func accepts(r *http.Request, mime string) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		if strings.HasPrefix(strings.TrimSpace(part), mime) {
+			return true
+		}
+	}
+	return false
+}
+
+func setSSEHeaders(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+}
+
+func writeHTTPError(w http.ResponseWriter, status int, id any, code int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(types.JSONRPCResponse{
+		JSONRPC: "2.0", ID: id,
+		Error: &types.JSONRPCError{Code: code, Message: msg},
+	})
+}
+
+// httpJSONWriter buffers a single JSON-RPC response for a plain JSON reply.
+// Notifications and server requests cannot ride on a JSON body, so they go
+// to the session's GET stream.
+type httpJSONWriter struct {
+	buf     bytes.Buffer
+	session *Session
+	notify  *SSEWriter
+	failed  bool // an error response was written
+}
+
+func (w *httpJSONWriter) Session() *Session { return w.session }
+
+func (w *httpJSONWriter) WriteResult(id, result any) error {
+	return json.NewEncoder(&w.buf).Encode(types.JSONRPCResponse{JSONRPC: "2.0", ID: id, Result: result})
+}
+
+func (w *httpJSONWriter) WriteError(id any, code int, msg string) error {
+	w.failed = true
+	return json.NewEncoder(&w.buf).Encode(types.JSONRPCResponse{
+		JSONRPC: "2.0", ID: id,
+		Error: &types.JSONRPCError{Code: code, Message: msg},
+	})
+}
+
+func (w *httpJSONWriter) writeBatch(responses []types.JSONRPCResponse) error {
+	return json.NewEncoder(&w.buf).Encode(responses)
+}
+
+func (w *httpJSONWriter) WriteNotification(method string, params any) error {
+	return w.notify.WriteNotification(method, params)
+}
+
+func (w *httpJSONWriter) WriteRequest(id any, method string, params any) error {
+	return w.notify.WriteRequest(id, method, params)
+}
+
+// httpStreamWriter answers a POST with an SSE stream, flushing each message.
+type httpStreamWriter struct {
+	mu      sync.Mutex
+	w       http.ResponseWriter
+	flusher http.Flusher
+	session *Session
+	started bool
+}
+
+func newHTTPStreamWriter(w http.ResponseWriter, sess *Session) *httpStreamWriter {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return nil
+	}
+	return &httpStreamWriter{w: w, flusher: flusher, session: sess}
+}
+
+func (w *httpStreamWriter) Session() *Session { return w.session }
+
+func (w *httpStreamWriter) WriteResult(id, result any) error {
+	return w.send(types.JSONRPCResponse{JSONRPC: "2.0", ID: id, Result: result})
+}
+
+func (w *httpStreamWriter) WriteError(id any, code int, msg string) error {
+	return w.send(types.JSONRPCResponse{
+		JSONRPC: "2.0", ID: id,
+		Error: &types.JSONRPCError{Code: code, Message: msg},
+	})
+}
+
+func (w *httpStreamWriter) WriteNotification(method string, params any) error {
+	return w.send(types.JSONRPCNotification{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+func (w *httpStreamWriter) WriteRequest(id any, method string, params any) error {
+	return w.send(types.JSONRPCOutgoingRequest{JSONRPC: "2.0", ID: id, Method: method, Params: params})
+}
+
+func (w *httpStreamWriter) send(msg any) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.started {
+		setSSEHeaders(w.w)
+		w.w.WriteHeader(http.StatusOK)
+		w.started = true
+	}
+	if _, err := fmt.Fprintf(w.w, "event: message\ndata: %s\n\n", data); err != nil {
+		return err
+	}
+	w.flusher.Flush()
+	return nil
+}
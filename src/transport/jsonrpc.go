@@ -0,0 +1,226 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/orchestra-mcp/mcp/src/tracing"
+	"github.com/orchestra-mcp/mcp/src/types"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// HandleMessage processes one raw JSON-RPC payload: a single request or
+// notification, or a batch array. Malformed input is answered with -32700
+// or -32600 instead of being dropped; batch replies are written together as
+// one array, and a batch of only notifications gets no reply at all.
+func (s *MCPServer) HandleMessage(ctx context.Context, data []byte, w ResponseWriter) {
+	ctx, span := tracing.Start(ctx, "jsonrpc.message", attribute.Int("jsonrpc.message.size", len(data)))
+	defer span.End()
+	msgs, batch, errResp := splitBatch(data)
+	if errResp != nil {
+		_ = w.WriteError(errResp.ID, errResp.Error.Code, errResp.Error.Message)
+		return
+	}
+	if !batch {
+		s.handleRaw(ctx, msgs[0], w, false)
+		return
+	}
+	bw := &batchWriter{parent: w}
+	var wg sync.WaitGroup
+	for _, m := range msgs {
+		wg.Add(1)
+		go func(m json.RawMessage) {
+			defer wg.Done()
+			s.handleRaw(ctx, m, bw, true)
+		}(m)
+	}
+	wg.Wait()
+	bw.flush()
+}
+
+func (s *MCPServer) handleRaw(ctx context.Context, raw json.RawMessage, w ResponseWriter, inBatch bool) {
+	_, span := tracing.Start(ctx, "jsonrpc.decode")
+	req, reply, errResp := decodeMessage(raw)
+	span.End()
+	if reply != nil {
+		w.Session().deliver(*reply)
+		return
+	}
+	if errResp == nil && req != nil && inBatch && req.Method == "initialize" {
+		errResp = rpcError(req.ID, -32600, "invalid request: initialize cannot be batched")
+	}
+	if errResp != nil {
+		_ = w.WriteError(errResp.ID, errResp.Error.Code, errResp.Error.Message)
+		return
+	}
+	if req != nil {
+		s.HandleRequestContext(ctx, req, w)
+	}
+}
+
+// splitBatch separates a payload into messages. A parse failure or an empty
+// batch yields the error response to send back.
+func splitBatch(data []byte) ([]json.RawMessage, bool, *types.JSONRPCResponse) {
+	data = bytes.TrimSpace(data)
+	if !json.Valid(data) {
+		return nil, false, rpcError(nil, -32700, "parse error")
+	}
+	if len(data) == 0 || data[0] != '[' {
+		return []json.RawMessage{data}, false, nil
+	}
+	var msgs []json.RawMessage
+	if err := json.Unmarshal(data, &msgs); err != nil {
+		return nil, false, rpcError(nil, -32700, "parse error")
+	}
+	if len(msgs) == 0 {
+		return nil, false, rpcError(nil, -32600, "invalid request: empty batch")
+	}
+	return msgs, true, nil
+}
+
+// wireMessage mirrors a JSON-RPC message with raw fields so presence and
+// types can be checked before trusting them.
+type wireMessage struct {
+	JSONRPC *string         `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Method  *string         `json:"method"`
+	Params  json.RawMessage `json:"params"`
+	Result  json.RawMessage `json:"result"`
+	Error   json.RawMessage `json:"error"`
+}
+
+// decodeMessage validates one message. It returns exactly one of the
+// request, the client's reply to a server-initiated request (result/error
+// without method, Result kept as json.RawMessage), or the error response to
+// send back.
+func decodeMessage(raw json.RawMessage) (*types.JSONRPCRequest, *types.JSONRPCResponse, *types.JSONRPCResponse) {
+	var m wireMessage
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, nil, rpcError(nil, -32600, "invalid request: not an object")
+	}
+	id, ok := decodeID(m.ID)
+	if !ok {
+		return nil, nil, rpcError(nil, -32600, "invalid request: id must be a string or number")
+	}
+	if m.JSONRPC == nil || *m.JSONRPC != "2.0" {
+		return nil, nil, rpcError(id, -32600, `invalid request: jsonrpc must be "2.0"`)
+	}
+	if m.Method == nil {
+		if id != nil && (m.Result != nil || m.Error != nil) {
+			return nil, decodeReply(id, m), nil
+		}
+		return nil, nil, rpcError(id, -32600, "invalid request: missing method")
+	}
+	if *m.Method == "" {
+		return nil, nil, rpcError(id, -32600, "invalid request: empty method")
+	}
+	if len(m.Params) > 0 && m.Params[0] != '{' && m.Params[0] != '[' && string(m.Params) != "null" {
+		return nil, nil, rpcError(id, -32600, "invalid request: params must be an object or array")
+	}
+	return &types.JSONRPCRequest{JSONRPC: "2.0", ID: id, Method: *m.Method, Params: m.Params}, nil, nil
+}
+
+// decodeReply builds the response a client sent for a server request.
+// A malformed error object still counts as a failure.
+func decodeReply(id any, m wireMessage) *types.JSONRPCResponse {
+	resp := &types.JSONRPCResponse{JSONRPC: "2.0", ID: id}
+	if m.Error != nil && string(m.Error) != "null" {
+		var e types.JSONRPCError
+		if json.Unmarshal(m.Error, &e) != nil {
+			e = types.JSONRPCError{Code: -32603, Message: "malformed error"}
+		}
+		resp.Error = &e
+		return resp
+	}
+	resp.Result = m.Result
+	return resp
+}
+
+// decodeID accepts an absent id (a notification), a string or a number.
+// Numbers decode to float64 like the rest of encoding/json.
+func decodeID(raw json.RawMessage) (any, bool) {
+	if raw == nil {
+		return nil, true
+	}
+	var id any
+	if err := json.Unmarshal(raw, &id); err != nil {
+		return nil, false
+	}
+	switch id.(type) {
+	case string, float64:
+		return id, true
+	}
+	return nil, false
+}
+
+func rpcError(id any, code int, msg string) *types.JSONRPCResponse {
+	return &types.JSONRPCResponse{JSONRPC: "2.0", ID: id, Error: &types.JSONRPCError{Code: code, Message: msg}}
+}
+
+// batchResponder is implemented by writers that can send several responses
+// as one JSON array.
+type batchResponder interface {
+	writeBatch(responses []types.JSONRPCResponse) error
+}
+
+// batchWriter collects the responses of a batch; notifications such as
+// progress pass straight through.
+type batchWriter struct {
+	parent    ResponseWriter
+	mu        sync.Mutex
+	responses []types.JSONRPCResponse
+}
+
+func (w *batchWriter) Session() *Session { return w.parent.Session() }
+
+func (w *batchWriter) WriteResult(id, result any) error {
+	return w.add(types.JSONRPCResponse{JSONRPC: "2.0", ID: id, Result: result})
+}
+
+func (w *batchWriter) WriteError(id any, code int, msg string) error {
+	return w.add(*rpcError(id, code, msg))
+}
+
+func (w *batchWriter) WriteNotification(method string, params any) error {
+	return w.parent.WriteNotification(method, params)
+}
+
+func (w *batchWriter) WriteRequest(id any, method string, params any) error {
+	return w.parent.WriteRequest(id, method, params)
+}
+
+func (w *batchWriter) add(r types.JSONRPCResponse) error {
+	w.mu.Lock()
+	w.responses = append(w.responses, r)
+	w.mu.Unlock()
+	return nil
+}
+
+func (w *batchWriter) flush() {
+	w.mu.Lock()
+	responses := w.responses
+	w.mu.Unlock()
+	if len(responses) == 0 {
+		return
+	}
+	if br, ok := w.parent.(batchResponder); ok {
+		_ = br.writeBatch(responses)
+		return
+	}
+	for _, r := range responses {
+		if r.Error != nil {
+			_ = w.parent.WriteError(r.ID, r.Error.Code, r.Error.Message)
+		} else {
+			_ = w.parent.WriteResult(r.ID, r.Result)
+		}
+	}
+}
+
+// notificationWriter drops replies: notifications are never answered, even
+// with an error, but may still trigger server notifications.
+type notificationWriter struct{ ResponseWriter }
+
+func (notificationWriter) WriteResult(any, any) error        { return nil }
+func (notificationWriter) WriteError(any, int, string) error { return nil }
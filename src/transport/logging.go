@@ -0,0 +1,61 @@
+package transport
+
+import (
+	"encoding/json"
+
+	"github.com/orchestra-mcp/mcp/src/logger"
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+// SetLogLevel sets the minimum level of log messages sent to this client.
+func (s *Session) SetLogLevel(l logger.Level) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.logLevel = l
+	s.mu.Unlock()
+}
+
+// LogLevel returns the minimum level of log messages sent to this client.
+func (s *Session) LogLevel() logger.Level {
+	if s == nil {
+		return ClientLogLevel
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.logLevel
+}
+
+func (s *MCPServer) handleSetLevelW(req *types.JSONRPCRequest, w ResponseWriter) {
+	var params types.SetLevelParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		_ = w.WriteError(req.ID, -32602, "invalid params")
+		return
+	}
+	level, err := logger.ParseLevel(params.Level)
+	if err != nil {
+		_ = w.WriteError(req.ID, -32602, err.Error())
+		return
+	}
+	w.Session().SetLogLevel(level)
+	_ = w.WriteResult(req.ID, map[string]any{})
+}
+
+// attachLogSink forwards log entries at or above the session's level to the
+// client as notifications/message until the session closes. A local stdio
+// client gets every entry, since the process is its own; a remote client
+// only gets entries tagged with its session ID, so it never sees other
+// clients' data. Entries go through the session's outbox, in order, so
+// logging never blocks on a slow stream.
+func (s *MCPServer) attachLogSink(w ResponseWriter) {
+	sess := w.Session()
+	remove := s.logger.AddSink(logger.SinkFunc(func(e logger.Entry) {
+		if e.Level < sess.LogLevel() || !sess.Local() && e.Fields["session"] != sess.ID {
+			return
+		}
+		params := types.LoggingMessageParams{Level: e.Level.String(), Logger: e.Logger, Data: e.Data()}
+		sess.notify(w, "notifications/message", params)
+	}))
+	sess.onClose(remove)
+}
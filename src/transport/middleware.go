@@ -0,0 +1,288 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/orchestra-mcp/mcp/src/auth"
+	h "github.com/orchestra-mcp/mcp/src/helpers"
+	"github.com/orchestra-mcp/mcp/src/logger"
+	"github.com/orchestra-mcp/mcp/src/metrics"
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+// ErrUnknownTool is returned by CallTool when no tool has the given name.
+var ErrUnknownTool = errors.New("unknown tool")
+
+// ToolCall is one tool invocation as seen by middleware. CallTool fills in
+// Definition; Session is nil for calls that arrive over REST.
+type ToolCall struct {
+	Name       string
+	Arguments  map[string]any
+	Definition types.ToolDefinition
+	Principal  *auth.Principal
+	Session    *Session
+	redact     []string
+}
+
+// ToolHandler runs a tool call.
+type ToolHandler func(ctx context.Context, call *ToolCall) (*types.ToolResult, error)
+
+// Middleware wraps a ToolHandler to add behaviour around every tool call.
+type Middleware func(next ToolHandler) ToolHandler
+
+// Use appends middleware to the tool-call chain. The first middleware
+// registered is the outermost. Calls from every transport, including the
+// REST route, run through the chain once they are authorized; arguments
+// are validated at its end, so middleware also sees invalid calls.
+func (s *MCPServer) Use(mw ...Middleware) {
+	s.mu.Lock()
+	s.middleware = append(s.middleware, mw...)
+	s.mu.Unlock()
+}
+
+// RejectHook is told about calls refused before the middleware chain
+// runs: unknown tools and failed authorization. Their arguments are masked
+// with DefaultRedactKeys.
+type RejectHook func(ctx context.Context, call *ToolCall, err error)
+
+// OnReject adds fn to the hooks told about rejected calls.
+func (s *MCPServer) OnReject(fn RejectHook) {
+	s.mu.Lock()
+	s.rejectHooks = append(s.rejectHooks, fn)
+	s.mu.Unlock()
+}
+
+// reject reports a call refused before the chain and returns err.
+func (s *MCPServer) reject(ctx context.Context, call *ToolCall, err error) error {
+	metrics.ToolErrors.Inc(strconv.Itoa(ErrorCode(err)))
+	call.redact = append(call.redact, DefaultRedactKeys...)
+	s.mu.RLock()
+	hooks := s.rejectHooks
+	s.mu.RUnlock()
+	for _, fn := range hooks {
+		fn(ctx, call, err)
+	}
+	return err
+}
+
+// CallTool looks up call's tool, authorizes the call and runs it through
+// the middleware chain, which ends by validating its arguments and running
+// the handler. Unknown and unauthorized calls go to the OnReject hooks
+// instead of the chain. Errors wrap ErrUnknownTool, auth.ErrUnauthorized or
+// auth.ErrForbidden, or are h.ValidationErrors; ErrorCode maps them.
+func (s *MCPServer) CallTool(ctx context.Context, call *ToolCall) (*types.ToolResult, error) {
+	tool, ok := s.lookupTool(call.Name)
+	if !ok {
+		return nil, s.reject(ctx, call, fmt.Errorf("%w: %s", ErrUnknownTool, call.Name))
+	}
+	call.Definition = tool.Definition
+	// Authorize before any middleware runs, so none acts on a call the
+	// caller may not make or changes who the caller is.
+	if err := s.authenticator().AuthorizeTool(call.Principal, tool.Definition); err != nil {
+		return nil, s.reject(ctx, call, err)
+	}
+	s.mu.RLock()
+	chain := s.middleware
+	s.mu.RUnlock()
+	next := ToolHandler(func(ctx context.Context, call *ToolCall) (*types.ToolResult, error) {
+		if err := h.ValidateInput(call.Arguments, tool.Definition.InputSchema); err != nil {
+			return nil, err
+		}
+		return tool.Call(ctx, call.Arguments)
+	})
+	for i := len(chain) - 1; i >= 0; i-- {
+		next = chain[i](next)
+	}
+	return next(ctx, call)
+}
+
+// DefaultMiddleware is the chain the orchestra-mcp servers install: secret
+// arguments are masked, each call is logged at debug level with its
+// duration, counted in the metrics and traced, and handler panics become
+// errors.
+func DefaultMiddleware() []Middleware {
+	return []Middleware{
+		Redact(),
+		Timing(func(call *ToolCall, elapsed time.Duration, err error) {
+			kv := []any{"tool", call.Name, "elapsed_ms", elapsed.Milliseconds(), "arguments", call.RedactedArguments()}
+			if err != nil {
+				kv = append(kv, "error", err)
+			}
+			logger.Debug("tools", "call finished", kv...)
+		}),
+		Metrics(),
+		Tracing(),
+		Recover(),
+	}
+}
+
+// CodedError is an error that carries its own JSON-RPC code, such as one
+// relayed from an upstream server.
+type CodedError interface {
+	error
+	JSONRPCCode() int
+}
+
+// ErrorCode maps a CallTool error to its JSON-RPC code.
+func ErrorCode(err error) int {
+	var invalid h.ValidationErrors
+	var coded CodedError
+	switch {
+	case errors.Is(err, ErrUnknownTool):
+		return -32601
+	case errors.Is(err, auth.ErrUnauthorized), errors.Is(err, auth.ErrForbidden):
+		return auth.Code(err)
+	case errors.As(err, &invalid):
+		return -32602
+	case errors.As(err, &coded):
+		return coded.JSONRPCCode()
+	}
+	return -32000
+}
+
+// Recover turns a panicking handler into an error result instead of
+// crashing the server. Register it after Timeout, whose handler runs on
+// its own goroutine.
+func Recover() Middleware {
+	return func(next ToolHandler) ToolHandler {
+		return func(ctx context.Context, call *ToolCall) (result *types.ToolResult, err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					result, err = nil, fmt.Errorf("tool %s panicked: %v", call.Name, r)
+					logger.Error("tools", "handler panicked", "tool", call.Name, "panic", fmt.Sprint(r), "stack", string(debug.Stack()))
+				}
+			}()
+			return next(ctx, call)
+		}
+	}
+}
+
+// Timeout cancels the call's context after d and returns an error if the
+// handler has not finished. Handlers that ignore ctx keep running in the
+// background, but their result is discarded.
+func Timeout(d time.Duration) Middleware {
+	return func(next ToolHandler) ToolHandler {
+		return func(ctx context.Context, call *ToolCall) (*types.ToolResult, error) {
+			ctx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+			type outcome struct {
+				result *types.ToolResult
+				err    error
+			}
+			done := make(chan outcome, 1)
+			go func() {
+				r, err := next(ctx, call)
+				done <- outcome{r, err}
+			}()
+			select {
+			case o := <-done:
+				return o.result, o.err
+			case <-ctx.Done():
+				if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					return nil, fmt.Errorf("tool %s timed out after %s", call.Name, d)
+				}
+				return nil, ctx.Err()
+			}
+		}
+	}
+}
+
+// Timing reports how long each call took and how it ended.
+func Timing(report func(call *ToolCall, elapsed time.Duration, err error)) Middleware {
+	return func(next ToolHandler) ToolHandler {
+		return func(ctx context.Context, call *ToolCall) (*types.ToolResult, error) {
+			start := time.Now()
+			result, err := next(ctx, call)
+			report(call, time.Since(start), err)
+			return result, err
+		}
+	}
+}
+
+// Metrics counts calls, latency and errors per JSON-RPC code in the
+// default metrics registry. Calls are labelled with the tool's registered
+// name, so aliases count towards it.
+func Metrics() Middleware {
+	return func(next ToolHandler) ToolHandler {
+		return func(ctx context.Context, call *ToolCall) (*types.ToolResult, error) {
+			start := time.Now()
+			result, err := next(ctx, call)
+			metrics.ToolCalls.Inc(call.Definition.Name)
+			metrics.ToolDuration.Observe(time.Since(start).Seconds(), call.Definition.Name)
+			if err != nil {
+				metrics.ToolErrors.Inc(strconv.Itoa(ErrorCode(err)))
+			}
+			return result, err
+		}
+	}
+}
+
+// DefaultRedactKeys are the argument names Redact masks when given none.
+var DefaultRedactKeys = []string{"password", "secret", "token", "api_key", "apikey", "authorization", "credential"}
+
+// Redacted replaces the values of redacted arguments.
+const Redacted = "[REDACTED]"
+
+// Redact marks arguments whose names contain one of keys (case-insensitive)
+// as sensitive, so later middleware that records calls sees them masked in
+// ToolCall.RedactedArguments. The handler still gets the real values.
+func Redact(keys ...string) Middleware {
+	if len(keys) == 0 {
+		keys = DefaultRedactKeys
+	}
+	lower := make([]string, len(keys))
+	for i, k := range keys {
+		lower[i] = strings.ToLower(k)
+	}
+	return func(next ToolHandler) ToolHandler {
+		return func(ctx context.Context, call *ToolCall) (*types.ToolResult, error) {
+			call.redact = append(call.redact, lower...)
+			return next(ctx, call)
+		}
+	}
+}
+
+// RedactedArguments returns a copy of the arguments with the values of
+// sensitive keys, at any depth, replaced by Redacted.
+func (c *ToolCall) RedactedArguments() map[string]any {
+	out, _ := c.redactValue(c.Arguments).(map[string]any)
+	return out
+}
+
+func (c *ToolCall) redactValue(v any) any {
+	switch x := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(x))
+		for k, val := range x {
+			if c.sensitive(k) {
+				out[k] = Redacted
+			} else {
+				out[k] = c.redactValue(val)
+			}
+		}
+		return out
+	case []any:
+		out := make([]any, len(x))
+		for i, val := range x {
+			out[i] = c.redactValue(val)
+		}
+		return out
+	}
+	return v
+}
+
+func (c *ToolCall) sensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, k := range c.redact {
+		if strings.Contains(key, k) {
+			return true
+		}
+	}
+	return false
+}
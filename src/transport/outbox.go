@@ -0,0 +1,65 @@
+package transport
+
+import "sync"
+
+// maxOutbox bounds the notifications waiting for one session; later ones
+// are dropped until the stream catches up.
+const maxOutbox = 1024
+
+// outbox sends a session's server-initiated notifications one at a time,
+// in the order they were queued, on a goroutine that exits when the queue
+// drains. Senders never block on a slow stream.
+type outbox struct {
+	mu      sync.Mutex
+	pending []func()
+	running bool
+	closed  bool
+}
+
+// push queues send, starting the drain goroutine if none is running.
+func (o *outbox) push(send func()) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.closed || len(o.pending) >= maxOutbox {
+		return
+	}
+	o.pending = append(o.pending, send)
+	if !o.running {
+		o.running = true
+		go o.drain()
+	}
+}
+
+func (o *outbox) drain() {
+	for {
+		o.mu.Lock()
+		if len(o.pending) == 0 {
+			o.running = false
+			o.mu.Unlock()
+			return
+		}
+		send := o.pending[0]
+		o.pending[0] = nil
+		o.pending = o.pending[1:]
+		o.mu.Unlock()
+		send()
+	}
+}
+
+// close discards queued sends and refuses new ones.
+func (o *outbox) close() {
+	o.mu.Lock()
+	o.closed = true
+	o.pending = nil
+	o.mu.Unlock()
+}
+
+// notify queues a notification for the session's client behind any sent
+// before it.
+func (s *Session) notify(w ResponseWriter, method string, params any) {
+	if s == nil {
+		_ = w.WriteNotification(method, params)
+		return
+	}
+	s.outbox.push(func() { _ = w.WriteNotification(method, params) })
+}
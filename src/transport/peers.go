@@ -0,0 +1,66 @@
+package transport
+
+import "sync"
+
+// List kinds used in notifications/<kind>/list_changed.
+const (
+	listTools     = "tools"
+	listResources = "resources"
+	listPrompts   = "prompts"
+)
+
+// peerSet tracks the writers of initialized sessions so registry changes
+// can be pushed to every live client. Entries leave when the session closes.
+type peerSet struct {
+	mu      sync.RWMutex
+	writers map[*Session]ResponseWriter
+}
+
+func newPeerSet() *peerSet {
+	return &peerSet{writers: make(map[*Session]ResponseWriter)}
+}
+
+// add attaches w, which then receives the session's resource updates too,
+// and reports whether its session is new to the set.
+func (p *peerSet) add(w ResponseWriter) bool {
+	sess := w.Session()
+	if sess == nil {
+		return false
+	}
+	p.mu.Lock()
+	_, known := p.writers[sess]
+	p.writers[sess] = w
+	p.mu.Unlock()
+	sess.mu.Lock()
+	sess.notifier = w
+	sess.mu.Unlock()
+	if !known {
+		sess.onClose(func() { p.remove(sess) })
+	}
+	return !known
+}
+
+func (p *peerSet) remove(sess *Session) {
+	p.mu.Lock()
+	delete(p.writers, sess)
+	p.mu.Unlock()
+}
+
+// Peers returns the number of initialized sessions attached to the server.
+func (s *MCPServer) Peers() int {
+	s.peers.mu.RLock()
+	defer s.peers.mu.RUnlock()
+	return len(s.peers.writers)
+}
+
+// listChanged tells every attached session that a list changed. The
+// notification goes through each session's outbox, so a slow or idle
+// stream never blocks registration and changes arrive in order.
+func (s *MCPServer) listChanged(kind string) {
+	s.peers.mu.RLock()
+	defer s.peers.mu.RUnlock()
+	method := "notifications/" + kind + "/list_changed"
+	for sess, w := range s.peers.writers {
+		sess.notify(w, method, nil)
+	}
+}
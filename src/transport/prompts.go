@@ -8,18 +8,23 @@ import (
 
 // RegisterPrompt adds a single prompt to the server.
 func (s *MCPServer) RegisterPrompt(p types.Prompt) {
-	s.prompts[p.Definition.Name] = p
+	s.RegisterPrompts([]types.Prompt{p})
 }
 
-// RegisterPrompts adds multiple prompts to the server.
+// RegisterPrompts adds multiple prompts to the server and notifies clients once.
 func (s *MCPServer) RegisterPrompts(prompts []types.Prompt) {
+	s.mu.Lock()
 	for _, p := range prompts {
-		s.RegisterPrompt(p)
+		s.prompts[p.Definition.Name] = p
 	}
+	s.mu.Unlock()
+	s.listChanged(listPrompts)
 }
 
 // getPromptDefs returns all registered prompt definitions.
 func (s *MCPServer) getPromptDefs() []types.PromptDefinition {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	defs := make([]types.PromptDefinition, 0, len(s.prompts))
 	for _, p := range s.prompts {
 		defs = append(defs, p.Definition)
@@ -33,7 +38,9 @@ func (s *MCPServer) handlePromptGetW(req *types.JSONRPCRequest, w ResponseWriter
 		_ = w.WriteError(req.ID, -32602, "invalid params")
 		return
 	}
+	s.mu.RLock()
 	prompt, ok := s.prompts[params.Name]
+	s.mu.RUnlock()
 	if !ok {
 		_ = w.WriteError(req.ID, -32601, "unknown prompt: "+params.Name)
 		return
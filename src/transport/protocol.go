@@ -0,0 +1,170 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/orchestra-mcp/mcp/src/auth"
+	"github.com/orchestra-mcp/mcp/src/logger"
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+// SupportedVersions lists the MCP protocol versions this server speaks, newest first.
+var SupportedVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// Feature names a protocol feature gated by the negotiated version.
+type Feature string
+
+const (
+	FeatureToolAnnotations  Feature = "toolAnnotations"
+	FeatureCompletions      Feature = "completions"
+	FeatureStructuredOutput Feature = "structuredOutput"
+	FeatureElicitation      Feature = "elicitation"
+)
+
+// featureSince maps each feature to the first protocol version that has it.
+var featureSince = map[Feature]string{
+	FeatureToolAnnotations:  "2025-03-26",
+	FeatureCompletions:      "2025-03-26",
+	FeatureStructuredOutput: "2025-06-18",
+	FeatureElicitation:      "2025-06-18",
+}
+
+// NegotiateVersion picks the highest supported version not newer than requested.
+// Unknown or older requests get the latest version, per the MCP lifecycle spec.
+func NegotiateVersion(requested string) string {
+	for _, v := range SupportedVersions {
+		if v <= requested {
+			return v
+		}
+	}
+	return SupportedVersions[0]
+}
+
+// Session holds per-connection protocol state negotiated during initialize.
+// A nil *Session behaves like an uninitialized connection.
+type Session struct {
+	ID string
+
+	mu              sync.RWMutex
+	protocolVersion string
+	clientCaps      types.ClientCaps
+	clientInfo      types.ClientInfo
+	inflight        map[string]context.CancelFunc
+	notifier        ResponseWriter // receives resource updates; set when attached to a server
+	watch           *watcher
+	closers         []func()
+	logLevel        logger.Level
+	pending         map[string]chan types.JSONRPCResponse // server-initiated requests awaiting replies
+	nextID          int64
+	roots           []types.Root
+	principal       *auth.Principal
+	local           bool // a stdio client running on the server host
+	outbox          outbox
+}
+
+// ClientLogLevel is the minimum level sent to a client until it calls
+// logging/setLevel.
+var ClientLogLevel = logger.LevelInfo
+
+// NewSession creates an uninitialized session with the given ID.
+func NewSession(id string) *Session {
+	return &Session{ID: id, inflight: make(map[string]context.CancelFunc), logLevel: ClientLogLevel}
+}
+
+// Initialize stores the client's handshake and returns the negotiated version.
+func (s *Session) Initialize(p types.InitializeParams) string {
+	v := NegotiateVersion(p.ProtocolVersion)
+	if s == nil {
+		return v
+	}
+	s.mu.Lock()
+	s.protocolVersion = v
+	s.clientCaps = p.Capabilities
+	s.clientInfo = p.ClientInfo
+	s.mu.Unlock()
+	return v
+}
+
+// Local reports whether the client runs on the server host, as stdio
+// clients do. Only local sessions may name arbitrary roots.
+func (s *Session) Local() bool {
+	return s != nil && s.local
+}
+
+// ProtocolVersion returns the negotiated version, or "" before initialize.
+func (s *Session) ProtocolVersion() string {
+	if s == nil {
+		return ""
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.protocolVersion
+}
+
+// ClientCapabilities returns the capabilities the client declared.
+func (s *Session) ClientCapabilities() types.ClientCaps {
+	if s == nil {
+		return types.ClientCaps{}
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.clientCaps
+}
+
+// ClientInfo returns the client's name and version.
+func (s *Session) ClientInfo() types.ClientInfo {
+	if s == nil {
+		return types.ClientInfo{}
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.clientInfo
+}
+
+// Supports reports whether the negotiated version includes a feature.
+func (s *Session) Supports(f Feature) bool {
+	since, ok := featureSince[f]
+	if !ok {
+		return false
+	}
+	v := s.ProtocolVersion()
+	return v != "" && v >= since
+}
+
+// requestKey normalizes a JSON-RPC ID so numeric and string IDs never collide.
+func requestKey(id any) string { return fmt.Sprintf("%T:%v", id, id) }
+
+// track registers an in-flight request and returns a context cancelled by
+// Cancel(id). The returned release func must be called when the request ends.
+func (s *Session) track(ctx context.Context, id any) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	if s == nil || id == nil {
+		return ctx, cancel
+	}
+	key := requestKey(id)
+	s.mu.Lock()
+	s.inflight[key] = cancel
+	s.mu.Unlock()
+	return ctx, func() {
+		s.mu.Lock()
+		delete(s.inflight, key)
+		s.mu.Unlock()
+		cancel()
+	}
+}
+
+// Cancel aborts the in-flight request with the given ID, if any.
+func (s *Session) Cancel(id any) bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	cancel, ok := s.inflight[requestKey(id)]
+	s.mu.Unlock()
+	if ok {
+		cancel()
+	}
+	return ok
+}
@@ -0,0 +1,128 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+// RequestTimeout bounds how long the server waits for the answer to a
+// server-initiated request when the caller's context has no deadline.
+var RequestTimeout = 60 * time.Second
+
+// RPCError is a JSON-RPC error returned by the client for a server request.
+type RPCError struct {
+	Code    int
+	Message string
+}
+
+func (e *RPCError) Error() string { return fmt.Sprintf("client error %d: %s", e.Code, e.Message) }
+
+// Request sends a server-initiated request through w and decodes the
+// client's result into result. On timeout or cancellation the client is
+// sent notifications/cancelled. Responses are matched by ID via deliver.
+func (s *Session) Request(ctx context.Context, w ResponseWriter, method string, params, result any) error {
+	if s == nil {
+		return fmt.Errorf("%s: no session", method)
+	}
+	ch := make(chan types.JSONRPCResponse, 1)
+	s.mu.Lock()
+	s.nextID++
+	id := fmt.Sprintf("srv-%d", s.nextID) // string IDs never collide with decoded numbers
+	if s.pending == nil {
+		s.pending = make(map[string]chan types.JSONRPCResponse)
+	}
+	s.pending[id] = ch
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.pending, id)
+		s.mu.Unlock()
+	}()
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, RequestTimeout)
+		defer cancel()
+	}
+	if err := w.WriteRequest(id, method, params); err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	select {
+	case resp := <-ch:
+		if resp.Error != nil {
+			return &RPCError{Code: resp.Error.Code, Message: resp.Error.Message}
+		}
+		raw, _ := resp.Result.(json.RawMessage)
+		if result == nil || raw == nil {
+			return nil
+		}
+		if err := json.Unmarshal(raw, result); err != nil {
+			return fmt.Errorf("%s: decode result: %w", method, err)
+		}
+		return nil
+	case <-ctx.Done():
+		_ = w.WriteNotification("notifications/cancelled", types.CancelledParams{RequestID: id, Reason: ctx.Err().Error()})
+		return fmt.Errorf("%s: %w", method, ctx.Err())
+	}
+}
+
+// deliver hands a client response to the Request waiting for its ID.
+// It reports false for unknown IDs, e.g. answers that arrive after a timeout.
+func (s *Session) deliver(resp types.JSONRPCResponse) bool {
+	if s == nil {
+		return false
+	}
+	id, ok := resp.ID.(string)
+	if !ok {
+		return false
+	}
+	s.mu.Lock()
+	ch, ok := s.pending[id]
+	delete(s.pending, id)
+	s.mu.Unlock()
+	if ok {
+		ch <- resp
+	}
+	return ok
+}
+
+// failPending ends every outstanding request when the session closes.
+func (s *Session) failPending() {
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = nil
+	s.mu.Unlock()
+	for id, ch := range pending {
+		ch <- types.JSONRPCResponse{ID: id, Error: &types.JSONRPCError{Code: -32000, Message: "session closed"}}
+	}
+}
+
+// CreateMessage asks the client's model for a completion via
+// sampling/createMessage. The client must have declared sampling support.
+func (s *Session) CreateMessage(ctx context.Context, w ResponseWriter, params types.CreateMessageParams) (*types.CreateMessageResult, error) {
+	if s.ClientCapabilities().Sampling == nil {
+		return nil, fmt.Errorf("sampling/createMessage: client did not declare sampling")
+	}
+	var result types.CreateMessageResult
+	if err := s.Request(ctx, w, "sampling/createMessage", params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Elicit asks the user for structured input via elicitation/create. The
+// client must have declared elicitation on a 2025-06-18 or later session.
+func (s *Session) Elicit(ctx context.Context, w ResponseWriter, params types.ElicitParams) (*types.ElicitResult, error) {
+	if s.ClientCapabilities().Elicitation == nil || !s.Supports(FeatureElicitation) {
+		return nil, fmt.Errorf("elicitation/create: client did not declare elicitation")
+	}
+	var result types.ElicitResult
+	if err := s.Request(ctx, w, "elicitation/create", params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -2,32 +2,80 @@ package transport
 
 import (
 	"encoding/json"
-	"strings"
+	"sort"
 
+	h "github.com/orchestra-mcp/mcp/src/helpers"
 	"github.com/orchestra-mcp/mcp/src/types"
 )
 
 // RegisterResource adds a single resource to the server.
 func (s *MCPServer) RegisterResource(r types.Resource) {
-	s.resources[r.Definition.URI] = r
+	s.RegisterResources([]types.Resource{r})
 }
 
-// RegisterResources adds multiple resources to the server.
+// RegisterResources adds multiple resources to the server and notifies clients once.
 func (s *MCPServer) RegisterResources(resources []types.Resource) {
+	s.mu.Lock()
 	for _, r := range resources {
-		s.RegisterResource(r)
+		s.resources[r.Definition.URI] = r
 	}
+	s.mu.Unlock()
+	s.listChanged(listResources)
 }
 
-// getResourceDefs returns all registered resource definitions.
+// getResourceDefs returns concrete resources, expanding templates into their instances.
 func (s *MCPServer) getResourceDefs() []types.ResourceDefinition {
-	defs := make([]types.ResourceDefinition, 0, len(s.resources))
+	return ResourceDefinitions(s.resourceList())
+}
+
+// GetResourceTemplates returns all templated resources.
+func (s *MCPServer) GetResourceTemplates() []types.ResourceTemplate {
+	return ResourceTemplates(s.resourceList())
+}
+
+// resourceList snapshots the registry so List funcs run without the lock.
+func (s *MCPServer) resourceList() []types.Resource {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]types.Resource, 0, len(s.resources))
 	for _, r := range s.resources {
-		defs = append(defs, r.Definition)
+		out = append(out, r)
 	}
+	return out
+}
+
+// ResourceDefinitions lists concrete resources: fixed URIs as registered and
+// templated ones expanded through their List func, sorted by URI.
+func ResourceDefinitions(resources []types.Resource) []types.ResourceDefinition {
+	defs := make([]types.ResourceDefinition, 0, len(resources))
+	for _, r := range resources {
+		switch {
+		case !h.IsURITemplate(r.Definition.URI):
+			defs = append(defs, r.Definition)
+		case r.List != nil:
+			defs = append(defs, r.List()...)
+		}
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].URI < defs[j].URI })
 	return defs
 }
 
+// ResourceTemplates lists the templated resources as RFC 6570 templates, sorted by URI.
+func ResourceTemplates(resources []types.Resource) []types.ResourceTemplate {
+	out := make([]types.ResourceTemplate, 0, len(resources))
+	for _, r := range resources {
+		d := r.Definition
+		if h.IsURITemplate(d.URI) {
+			out = append(out, types.ResourceTemplate{
+				URITemplate: d.URI, Name: d.Name, Title: d.Title,
+				Description: d.Description, MimeType: d.MimeType,
+			})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].URITemplate < out[j].URITemplate })
+	return out
+}
+
 func (s *MCPServer) handleResourceReadW(req *types.JSONRPCRequest, w ResponseWriter) {
 	var params types.ReadResourceParams
 	if err := json.Unmarshal(req.Params, &params); err != nil {
@@ -49,31 +97,22 @@ func (s *MCPServer) handleResourceReadW(req *types.JSONRPCRequest, w ResponseWri
 
 // findResource looks up a resource by exact URI match or template pattern.
 func (s *MCPServer) findResource(uri string) (types.Resource, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	if r, ok := s.resources[uri]; ok {
 		return r, true
 	}
-	for pattern, r := range s.resources {
-		if matchPattern(pattern, uri) {
-			return r, true
-		}
-	}
-	return types.Resource{}, false
-}
-
-// matchPattern checks if a URI matches a pattern with {param} placeholders.
-func matchPattern(pattern, uri string) bool {
-	pp := strings.Split(pattern, "/")
-	up := strings.Split(uri, "/")
-	if len(pp) != len(up) {
-		return false
+	// Try templates in a fixed order so overlapping ones always resolve
+	// the same way.
+	patterns := make([]string, 0, len(s.resources))
+	for pattern := range s.resources {
+		patterns = append(patterns, pattern)
 	}
-	for i, seg := range pp {
-		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
-			continue
-		}
-		if seg != up[i] {
-			return false
+	sort.Strings(patterns)
+	for _, pattern := range patterns {
+		if _, ok := h.MatchURITemplate(pattern, uri); ok {
+			return s.resources[pattern], true
 		}
 	}
-	return true
+	return types.Resource{}, false
 }
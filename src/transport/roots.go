@@ -0,0 +1,80 @@
+package transport
+
+import (
+	"context"
+
+	h "github.com/orchestra-mcp/mcp/src/helpers"
+	"github.com/orchestra-mcp/mcp/src/logger"
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+// Roots returns the roots the client last reported through roots/list.
+func (s *Session) Roots() []types.Root {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.roots
+}
+
+// refreshRoots asks the client for its roots and stores them on the
+// session. It runs in its own goroutine: on stdio the notification that
+// triggers it is handled on the read loop, which must stay free to read
+// the client's reply.
+func (s *MCPServer) refreshRoots(w ResponseWriter) {
+	sess := w.Session()
+	if sess.ClientCapabilities().Roots == nil {
+		return
+	}
+	go func() {
+		var res types.ListRootsResult
+		if err := sess.Request(context.Background(), w, "roots/list", nil, &res); err != nil {
+			s.logger.Log(logger.LevelWarning, "transport", "roots/list failed", "session", sess.ID, "error", err)
+			return
+		}
+		roots := s.honouredRoots(sess, res.Roots)
+		sess.mu.Lock()
+		sess.roots = roots
+		sess.mu.Unlock()
+		s.logger.Log(logger.LevelDebug, "transport", "roots updated", "session", sess.ID, "count", len(roots))
+	}()
+}
+
+// SetRootDirs lists the directories that roots from remote (HTTP and SSE)
+// sessions must lie under. Roots describe the client's filesystem, so only
+// stdio clients, which share the server's host, may name any directory;
+// without root dirs, remote roots are ignored.
+func (s *MCPServer) SetRootDirs(dirs ...string) {
+	s.mu.Lock()
+	s.rootDirs = dirs
+	s.mu.Unlock()
+}
+
+// honouredRoots drops the roots a session may not use, logging each.
+func (s *MCPServer) honouredRoots(sess *Session, roots []types.Root) []types.Root {
+	if sess.Local() {
+		return roots
+	}
+	s.mu.RLock()
+	dirs := s.rootDirs
+	s.mu.RUnlock()
+	out := make([]types.Root, 0, len(roots))
+	for _, r := range roots {
+		if p, ok := h.RootPath(r); ok && withinAny(dirs, p) {
+			out = append(out, r)
+			continue
+		}
+		s.logger.Log(logger.LevelWarning, "transport", "root rejected", "session", sess.ID, "uri", r.URI)
+	}
+	return out
+}
+
+func withinAny(dirs []string, path string) bool {
+	for _, d := range dirs {
+		if h.WithinDir(d, path) {
+			return true
+		}
+	}
+	return false
+}
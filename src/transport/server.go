@@ -2,25 +2,41 @@ package transport
 
 import (
 	"bufio"
+	"bytes"
+	"context"
 	"encoding/json"
-	"fmt"
+	"io"
 	"os"
+	"sync"
 
+	"github.com/orchestra-mcp/mcp/src/auth"
 	h "github.com/orchestra-mcp/mcp/src/helpers"
+	"github.com/orchestra-mcp/mcp/src/logger"
 	"github.com/orchestra-mcp/mcp/src/types"
 )
 
 const maxScanSize = 10 * 1024 * 1024 // 10MB
 
 // MCPServer handles JSON-RPC transport for the MCP protocol.
+// The registry is guarded by mu so tools can be added or removed while
+// requests are in flight; peers are the initialized sessions that receive
+// list_changed notifications.
 type MCPServer struct {
-	name      string
-	version   string
-	tools     map[string]types.Tool
-	toolAlias map[string]string // maps "ns.toolName" -> flat name
-	resources map[string]types.Resource
-	prompts   map[string]types.Prompt
-	writer    ResponseWriter
+	name        string
+	version     string
+	mu          sync.RWMutex
+	tools       map[string]types.Tool
+	toolAlias   map[string]string // maps "ns.toolName" -> flat name
+	resources   map[string]types.Resource
+	prompts     map[string]types.Prompt
+	writer      *StdioWriter
+	peers       *peerSet
+	logger      *logger.Logger
+	auth        *auth.Authenticator
+	middleware  []Middleware
+	rootDirs    []string
+	origins     []string // browser origins allowed over Streamable HTTP
+	rejectHooks []RejectHook
 }
 
 // New creates an MCPServer with the given name and version.
@@ -31,28 +47,39 @@ func New(name, version string) *MCPServer {
 		toolAlias: make(map[string]string),
 		resources: make(map[string]types.Resource),
 		prompts:   make(map[string]types.Prompt),
-		writer:    &StdioWriter{},
+		writer:    NewStdioWriter(os.Stdout),
+		peers:     newPeerSet(),
+		logger:    logger.Default(),
 	}
 }
 
+// SetLogger replaces the logger whose entries are forwarded to clients.
+// Call it before serving; sessions attach to the logger at initialize.
+func (s *MCPServer) SetLogger(l *logger.Logger) { s.logger = l }
+
 // RegisterTool adds a single tool to the server.
 func (s *MCPServer) RegisterTool(t types.Tool) {
-	flat := t.Definition.Name
-	s.tools[flat] = t
-	if t.Definition.Namespace != "" {
-		s.toolAlias[t.Definition.QualifiedName()] = flat
-	}
+	s.RegisterTools([]types.Tool{t})
 }
 
-// RegisterTools adds multiple tools to the server.
+// RegisterTools adds multiple tools to the server and notifies clients once.
 func (s *MCPServer) RegisterTools(tools []types.Tool) {
+	s.mu.Lock()
 	for _, t := range tools {
-		s.RegisterTool(t)
+		flat := t.Definition.Name
+		s.tools[flat] = t
+		if t.Definition.Namespace != "" {
+			s.toolAlias[t.Definition.QualifiedName()] = flat
+		}
 	}
+	s.mu.Unlock()
+	s.listChanged(listTools)
 }
 
 // GetTools returns all registered tool definitions.
 func (s *MCPServer) GetTools() []types.ToolDefinition {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	defs := make([]types.ToolDefinition, 0, len(s.tools))
 	for _, t := range s.tools {
 		defs = append(defs, t.Definition)
@@ -72,53 +99,116 @@ func (s *MCPServer) GetPrompts() []types.PromptDefinition {
 
 // Run starts the stdio JSON-RPC loop.
 func (s *MCPServer) Run() {
-	scanner := bufio.NewScanner(os.Stdin)
+	s.serve(os.Stdin, s.writer)
+}
+
+// Serve runs the JSON Lines loop over arbitrary streams until in reaches EOF.
+func (s *MCPServer) Serve(in io.Reader, out io.Writer) {
+	s.serve(in, NewStdioWriter(out))
+}
+
+// serve reads messages line by line. Requests and batches run concurrently
+// so a slow tool never blocks ping or other calls; initialize, notifications
+// and malformed lines run inline to keep handshake ordering, make
+// cancellation immediate and answer errors in order.
+func (s *MCPServer) serve(in io.Reader, w *StdioWriter) {
+	scanner := bufio.NewScanner(in)
 	scanner.Buffer(make([]byte, 0, maxScanSize), maxScanSize)
+	ctx := context.Background()
+	var wg sync.WaitGroup
 	for scanner.Scan() {
-		line := scanner.Bytes()
+		line := bytes.TrimSpace(scanner.Bytes())
 		if len(line) == 0 {
 			continue
 		}
-		var req types.JSONRPCRequest
-		if err := json.Unmarshal(line, &req); err != nil {
-			fmt.Fprintf(os.Stderr, "parse error: %v\n", err)
+		if handleInline(line) {
+			s.HandleMessage(ctx, line, w)
 			continue
 		}
-		s.HandleRequest(&req, s.writer)
+		line = bytes.Clone(line) // the scanner reuses its buffer
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.HandleMessage(ctx, line, w)
+		}()
 	}
+	if err := scanner.Err(); err != nil {
+		s.logger.Log(logger.LevelError, "transport", "stdin read failed", "error", err)
+	}
+	wg.Wait()
+	w.Session().Close()
+}
+
+// handleInline reports whether a line should be handled on the read loop:
+// anything that is not a well-formed single request other than initialize.
+// Client replies are inline too; they only wake a waiting request.
+func handleInline(line []byte) bool {
+	if line[0] == '[' {
+		return false
+	}
+	var m struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+	}
+	if json.Unmarshal(line, &m) != nil {
+		return true
+	}
+	return m.ID == nil || m.Method == "" || m.Method == "initialize"
 }
 
 // HandleRequest processes a JSON-RPC request using the given writer.
 // Thread-safe: reads server state only, writes via the provided ResponseWriter.
 func (s *MCPServer) HandleRequest(req *types.JSONRPCRequest, w ResponseWriter) {
+	s.HandleRequestContext(context.Background(), req, w)
+}
+
+// HandleRequestContext is HandleRequest with a parent context. The request
+// is tracked on the writer's session so notifications/cancelled can abort it.
+func (s *MCPServer) HandleRequestContext(ctx context.Context, req *types.JSONRPCRequest, w ResponseWriter) {
+	if req.ID == nil {
+		w = notificationWriter{w}
+	}
+	ctx, span := startRequestSpan(ctx, req, w.Session())
+	defer span.End()
+	w = spanWriter{w, span}
+	ctx, release := w.Session().track(ctx, req.ID)
+	defer release()
+	if !s.authorizeMethod(req, w) {
+		return
+	}
+
 	switch req.Method {
 	case "initialize":
-		caps := types.ServerCaps{Tools: &types.ToolsCap{}}
-		if len(s.resources) > 0 {
-			caps.Resources = &types.ResourcesCap{}
-		}
-		if len(s.prompts) > 0 {
-			caps.Prompts = &types.PromptsCap{}
+		s.handleInitializeW(req, w)
+	case "notifications/initialized", "notifications/roots/list_changed":
+		s.refreshRoots(w)
+	case "notifications/cancelled":
+		var params types.CancelledParams
+		if json.Unmarshal(req.Params, &params) == nil && params.RequestID != nil {
+			w.Session().Cancel(params.RequestID)
 		}
-		_ = w.WriteResult(req.ID, types.InitializeResult{
-			ProtocolVersion: "2024-11-05",
-			Capabilities:    caps,
-			ServerInfo:      types.ServerInfo{Name: s.name, Version: s.version},
-		})
-	case "notifications/initialized":
-		// no response for notifications
 	case "tools/list":
-		w.WriteResult(req.ID, types.ListToolsResult{Tools: s.GetTools()})
+		w.WriteResult(req.ID, types.ListToolsResult{Tools: toolsFor(w.Session(), s.GetTools())})
 	case "tools/call":
-		s.handleToolCallW(req, w)
+		s.handleToolCallW(ctx, req, w)
 	case "resources/list":
 		w.WriteResult(req.ID, types.ListResourcesResult{Resources: s.GetResources()})
+	case "resources/templates/list":
+		w.WriteResult(req.ID, types.ListResourceTemplatesResult{ResourceTemplates: s.GetResourceTemplates()})
 	case "resources/read":
 		s.handleResourceReadW(req, w)
+	case "resources/subscribe":
+		s.handleSubscribeW(req, w)
+	case "resources/unsubscribe":
+		s.handleUnsubscribeW(req, w)
 	case "prompts/list":
 		w.WriteResult(req.ID, types.ListPromptsResult{Prompts: s.GetPrompts()})
 	case "prompts/get":
 		s.handlePromptGetW(req, w)
+	case "completion/complete":
+		s.handleCompleteW(req, w)
+	case "logging/setLevel":
+		s.handleSetLevelW(req, w)
 	case "ping":
 		w.WriteResult(req.ID, map[string]any{})
 	default:
@@ -126,32 +216,117 @@ func (s *MCPServer) HandleRequest(req *types.JSONRPCRequest, w ResponseWriter) {
 	}
 }
 
-func (s *MCPServer) handleToolCallW(req *types.JSONRPCRequest, w ResponseWriter) {
+func (s *MCPServer) handleInitializeW(req *types.JSONRPCRequest, w ResponseWriter) {
+	var params types.InitializeParams
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			_ = w.WriteError(req.ID, -32602, "invalid params")
+			return
+		}
+	}
+	version := w.Session().Initialize(params)
+	if s.peers.add(w) {
+		s.attachLogSink(w)
+	}
+	caps := types.ServerCaps{Tools: &types.ToolsCap{ListChanged: true}, Logging: &types.LoggingCap{}}
+	s.mu.RLock()
+	if len(s.resources) > 0 {
+		caps.Resources = &types.ResourcesCap{Subscribe: true, ListChanged: true}
+	}
+	if len(s.prompts) > 0 {
+		caps.Prompts = &types.PromptsCap{ListChanged: true}
+	}
+	if (caps.Resources != nil || caps.Prompts != nil) && w.Session().Supports(FeatureCompletions) {
+		caps.Completions = &types.CompletionsCap{}
+	}
+	s.mu.RUnlock()
+	_ = w.WriteResult(req.ID, types.InitializeResult{
+		ProtocolVersion: version,
+		Capabilities:    caps,
+		ServerInfo:      types.ServerInfo{Name: s.name, Version: s.version},
+	})
+}
+
+func (s *MCPServer) handleToolCallW(ctx context.Context, req *types.JSONRPCRequest, w ResponseWriter) {
 	var params types.CallToolParams
 	if err := json.Unmarshal(req.Params, &params); err != nil {
 		w.WriteError(req.ID, -32602, "invalid params")
 		return
 	}
-	tool, ok := s.tools[params.Name]
-	if !ok {
-		if flat, aliased := s.toolAlias[params.Name]; aliased {
-			tool, ok = s.tools[flat]
-		}
+	if params.Meta != nil && params.Meta.ProgressToken != nil {
+		token := params.Meta.ProgressToken
+		ctx = h.WithProgress(ctx, func(progress, total float64, message string) {
+			_ = w.WriteNotification("notifications/progress", types.ProgressParams{
+				ProgressToken: token, Progress: progress, Total: total, Message: message,
+			})
+		})
 	}
-	if !ok {
-		w.WriteError(req.ID, -32601, "unknown tool: "+params.Name)
-		return
+	if sess := w.Session(); sess != nil {
+		if roots := sess.Roots(); len(roots) > 0 {
+			ctx = h.WithRoots(ctx, roots)
+		}
+		if sess.ClientCapabilities().Sampling != nil {
+			ctx = h.WithSampler(ctx, func(ctx context.Context, p types.CreateMessageParams) (*types.CreateMessageResult, error) {
+				return sess.CreateMessage(ctx, w, p)
+			})
+		}
+		if sess.ClientCapabilities().Elicitation != nil && sess.Supports(FeatureElicitation) {
+			ctx = h.WithElicitor(ctx, func(ctx context.Context, p types.ElicitParams) (*types.ElicitResult, error) {
+				return sess.Elicit(ctx, w, p)
+			})
+		}
 	}
-	if err := h.ValidateArgs(params.Arguments,
-		tool.Definition.InputSchema.Properties,
-		tool.Definition.InputSchema.Required); err != nil {
-		w.WriteError(req.ID, -32602, err.Error())
-		return
+	result, err := s.CallTool(ctx, &ToolCall{
+		Name: params.Name, Arguments: params.Arguments,
+		Principal: w.Session().Principal(), Session: w.Session(),
+	})
+	if ctx.Err() != nil {
+		return // cancelled: the client no longer expects a response
 	}
-	result, err := tool.Handler(params.Arguments)
 	if err != nil {
-		w.WriteError(req.ID, -32000, err.Error())
+		w.WriteError(req.ID, ErrorCode(err), err.Error())
 		return
 	}
-	w.WriteResult(req.ID, result)
+	w.WriteResult(req.ID, resultFor(w.Session(), result))
+	w.Session().checkSubscriptions()
+}
+
+// toolsFor drops the definition fields newer than the session's protocol
+// version: annotations before 2025-03-26, output schemas before 2025-06-18.
+func toolsFor(sess *Session, defs []types.ToolDefinition) []types.ToolDefinition {
+	annotations := sess.Supports(FeatureToolAnnotations)
+	structured := sess.Supports(FeatureStructuredOutput)
+	for i := range defs {
+		if !annotations {
+			defs[i].Annotations = nil
+		}
+		if !structured {
+			defs[i].OutputSchema = nil
+		}
+	}
+	return defs
+}
+
+// resultFor drops structuredContent for clients that predate structured
+// output; the text block already carries the same data.
+func resultFor(sess *Session, r *types.ToolResult) *types.ToolResult {
+	if r == nil || r.StructuredContent == nil || sess.Supports(FeatureStructuredOutput) {
+		return r
+	}
+	plain := *r
+	plain.StructuredContent = nil
+	return &plain
+}
+
+// lookupTool resolves a tool by flat name or "ns.name" alias.
+func (s *MCPServer) lookupTool(name string) (types.Tool, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	tool, ok := s.tools[name]
+	if !ok {
+		if flat, aliased := s.toolAlias[name]; aliased {
+			tool, ok = s.tools[flat]
+		}
+	}
+	return tool, ok
 }
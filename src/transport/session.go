@@ -2,61 +2,241 @@ package transport
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 )
 
-// SSESession represents a single SSE client connection.
+// ReplayBufferSize is how many outbound events a session keeps for
+// redelivery after a reconnect. Older events are dropped.
+const ReplayBufferSize = 256
+
+// DefaultSessionTTL is how long a session with no open stream and no
+// incoming requests survives before it expires.
+const DefaultSessionTTL = 5 * time.Minute
+
+// SSEEvent is one outbound message with its per-session event ID.
+type SSEEvent struct {
+	ID   uint64
+	Data []byte
+}
+
+// SSESession represents a single SSE client connection. Outbound messages
+// get monotonic event IDs and are kept in a bounded replay buffer, so a
+// client that reconnects with Last-Event-ID receives what it missed.
 type SSESession struct {
-	ID       string
-	Messages chan []byte // outbound SSE messages
-	ctx      context.Context
-	cancel   context.CancelFunc
+	ID     string
+	State  *Session // negotiated protocol state
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu        sync.Mutex
+	events    []SSEEvent    // replay buffer, oldest first
+	lastID    uint64        // ID of the newest event
+	delivered uint64        // highest ID written to any stream
+	changed   chan struct{} // closed and replaced on each publish
+	streams   int           // open streams
+	idle      *time.Timer   // expiry while no stream is open
+	ttl       time.Duration
 }
 
-// NewSSESession creates a new session with a buffered message channel.
+// NewSSESession creates a new session with an empty replay buffer.
 func NewSSESession() *SSESession {
 	ctx, cancel := context.WithCancel(context.Background())
+	id := uuid.New().String()
 	return &SSESession{
-		ID:       uuid.New().String(),
-		Messages: make(chan []byte, 32),
-		ctx:      ctx,
-		cancel:   cancel,
+		ID:      id,
+		State:   NewSession(id),
+		ctx:     ctx,
+		cancel:  cancel,
+		changed: make(chan struct{}),
 	}
 }
 
-// Close terminates the session.
-func (s *SSESession) Close() { s.cancel() }
+// Close terminates the session and drops its resource subscriptions.
+func (s *SSESession) Close() {
+	s.mu.Lock()
+	if s.idle != nil {
+		s.idle.Stop()
+	}
+	s.mu.Unlock()
+	s.cancel()
+	s.State.Close()
+}
 
 // Context returns the session context (cancelled when closed).
 func (s *SSESession) Context() context.Context { return s.ctx }
 
-// SSESessionManager tracks all active SSE sessions.
+// Publish assigns the next event ID to data and queues it for the open
+// streams. It never blocks; a session with no stream buffers the event for
+// replay until the buffer wraps.
+func (s *SSESession) Publish(data []byte) (SSEEvent, error) {
+	if err := s.ctx.Err(); err != nil {
+		return SSEEvent{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastID++
+	ev := SSEEvent{ID: s.lastID, Data: data}
+	s.events = append(s.events, ev)
+	if n := len(s.events) - ReplayBufferSize; n > 0 {
+		s.events = append(s.events[:0:0], s.events[n:]...)
+	}
+	close(s.changed)
+	s.changed = make(chan struct{})
+	return ev, nil
+}
+
+// EventsAfter returns the buffered events with an ID greater than id.
+func (s *SSESession) EventsAfter(id uint64) []SSEEvent {
+	evs, _ := s.next(id)
+	return evs
+}
+
+// next returns the buffered events after id together with a channel that
+// is closed when another event is published.
+func (s *SSESession) next(id uint64) ([]SSEEvent, <-chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []SSEEvent
+	for _, ev := range s.events {
+		if ev.ID > id {
+			out = append(out, ev)
+		}
+	}
+	return out, s.changed
+}
+
+// Stream writes events to w until ctx or the session ends, flushing after
+// each frame and sending a keep-alive comment every 15 seconds. A non-empty
+// lastEventID resumes after that event; otherwise the stream starts with
+// the first event no stream has delivered yet.
+func (s *SSESession) Stream(ctx context.Context, w io.Writer, flush func() error, lastEventID string) error {
+	s.attach()
+	defer s.detach()
+
+	s.mu.Lock()
+	after := s.delivered
+	s.mu.Unlock()
+	if id, err := strconv.ParseUint(strings.TrimSpace(lastEventID), 10, 64); err == nil {
+		after = id
+	}
+
+	ticker := time.NewTicker(15 * time.Second)
+	defer ticker.Stop()
+	for {
+		evs, changed := s.next(after)
+		for _, ev := range evs {
+			if _, err := fmt.Fprintf(w, "id: %d\nevent: message\ndata: %s\n\n", ev.ID, ev.Data); err != nil {
+				return err
+			}
+			after = ev.ID
+		}
+		if len(evs) > 0 {
+			if err := flush(); err != nil {
+				return err
+			}
+			s.markDelivered(after)
+		}
+		select {
+		case <-changed:
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return err
+			}
+			if err := flush(); err != nil {
+				return err
+			}
+		case <-s.ctx.Done():
+			return nil
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (s *SSESession) markDelivered(id uint64) {
+	s.mu.Lock()
+	if id > s.delivered {
+		s.delivered = id
+	}
+	s.mu.Unlock()
+}
+
+// attach records an open stream and pauses idle expiry.
+func (s *SSESession) attach() {
+	s.mu.Lock()
+	s.streams++
+	if s.idle != nil {
+		s.idle.Stop()
+	}
+	s.mu.Unlock()
+}
+
+// detach records a closed stream and restarts idle expiry once none remain.
+func (s *SSESession) detach() {
+	s.mu.Lock()
+	s.streams--
+	if s.streams == 0 && s.idle != nil {
+		s.idle.Reset(s.ttl)
+	}
+	s.mu.Unlock()
+}
+
+// touch restarts idle expiry for a session with no open stream.
+func (s *SSESession) touch() {
+	s.mu.Lock()
+	if s.streams == 0 && s.idle != nil {
+		s.idle.Reset(s.ttl)
+	}
+	s.mu.Unlock()
+}
+
+// SSESessionManager tracks all active SSE sessions. A session whose
+// streams have all closed stays resumable until it has been idle for the
+// manager's TTL.
 type SSESessionManager struct {
 	mu       sync.RWMutex
 	sessions map[string]*SSESession
+	ttl      time.Duration
 }
 
-// NewSSESessionManager creates a session manager.
+// NewSSESessionManager creates a session manager using DefaultSessionTTL.
 func NewSSESessionManager() *SSESessionManager {
-	return &SSESessionManager{sessions: make(map[string]*SSESession)}
+	return &SSESessionManager{sessions: make(map[string]*SSESession), ttl: DefaultSessionTTL}
+}
+
+// SetTTL changes the idle TTL for sessions created afterwards.
+func (m *SSESessionManager) SetTTL(d time.Duration) {
+	m.mu.Lock()
+	m.ttl = d
+	m.mu.Unlock()
 }
 
 // Create creates and registers a new session.
 func (m *SSESessionManager) Create() *SSESession {
 	sess := NewSSESession()
 	m.mu.Lock()
+	sess.ttl = m.ttl
+	sess.idle = time.AfterFunc(m.ttl, func() { m.expire(sess) })
 	m.sessions[sess.ID] = sess
 	m.mu.Unlock()
 	return sess
 }
 
-// Get retrieves a session by ID.
+// Get retrieves a session by ID and restarts its idle timer.
 func (m *SSESessionManager) Get(id string) (*SSESession, bool) {
 	m.mu.RLock()
-	defer m.mu.RUnlock()
 	sess, ok := m.sessions[id]
+	m.mu.RUnlock()
+	if ok {
+		sess.touch()
+	}
 	return sess, ok
 }
 
@@ -73,6 +253,21 @@ func (m *SSESessionManager) Remove(id string) {
 	}
 }
 
+// expire removes sess if it still has no open stream when its timer fires.
+func (m *SSESessionManager) expire(sess *SSESession) {
+	m.mu.Lock()
+	sess.mu.Lock()
+	idle := sess.streams == 0
+	sess.mu.Unlock()
+	if !idle || m.sessions[sess.ID] != sess {
+		m.mu.Unlock()
+		return
+	}
+	delete(m.sessions, sess.ID)
+	m.mu.Unlock()
+	sess.Close()
+}
+
 // Count returns the number of active sessions.
 func (m *SSESessionManager) Count() int {
 	m.mu.RLock()
@@ -6,7 +6,7 @@ import (
 	"github.com/orchestra-mcp/mcp/src/types"
 )
 
-// SSEWriter writes JSON-RPC responses to an SSE session's message channel.
+// SSEWriter publishes JSON-RPC messages as events on an SSE session.
 type SSEWriter struct {
 	session *SSESession
 }
@@ -16,6 +16,8 @@ func NewSSEWriter(sess *SSESession) *SSEWriter {
 	return &SSEWriter{session: sess}
 }
 
+func (w *SSEWriter) Session() *Session { return w.session.State }
+
 func (w *SSEWriter) WriteResult(id, result any) error {
 	resp := types.JSONRPCResponse{JSONRPC: "2.0", ID: id, Result: result}
 	data, err := json.Marshal(resp)
@@ -37,11 +39,31 @@ func (w *SSEWriter) WriteError(id any, code int, msg string) error {
 	return w.send(data)
 }
 
-func (w *SSEWriter) send(data []byte) error {
-	select {
-	case w.session.Messages <- data:
-		return nil
-	case <-w.session.Context().Done():
-		return w.session.Context().Err()
+func (w *SSEWriter) WriteNotification(method string, params any) error {
+	data, err := json.Marshal(types.JSONRPCNotification{JSONRPC: "2.0", Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+	return w.send(data)
+}
+
+func (w *SSEWriter) WriteRequest(id any, method string, params any) error {
+	data, err := json.Marshal(types.JSONRPCOutgoingRequest{JSONRPC: "2.0", ID: id, Method: method, Params: params})
+	if err != nil {
+		return err
 	}
+	return w.send(data)
+}
+
+func (w *SSEWriter) writeBatch(responses []types.JSONRPCResponse) error {
+	data, err := json.Marshal(responses)
+	if err != nil {
+		return err
+	}
+	return w.send(data)
+}
+
+func (w *SSEWriter) send(data []byte) error {
+	_, err := w.session.Publish(data)
+	return err
 }
@@ -0,0 +1,122 @@
+package transport
+
+import (
+	"encoding/json"
+
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+// Subscribe watches paths on behalf of uri and sends
+// notifications/resources/updated whenever one of them changes. Updates
+// are queued behind the session's other notifications and go to the writer
+// the session was initialized on.
+func (s *Session) Subscribe(uri string, paths []string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.watch == nil {
+		s.watch = newWatcher(s.resourceUpdated)
+	}
+	watch := s.watch
+	s.mu.Unlock()
+	watch.add(uri, paths)
+}
+
+// Unsubscribe stops notifications for uri.
+func (s *Session) Unsubscribe(uri string) {
+	if watch := s.watcher(); watch != nil {
+		watch.remove(uri)
+	}
+}
+
+// Subscriptions returns the URIs this session is subscribed to.
+func (s *Session) Subscriptions() []string {
+	if watch := s.watcher(); watch != nil {
+		return watch.uris()
+	}
+	return nil
+}
+
+// Close drops every subscription, fails outstanding server requests and
+// runs close hooks.
+// Transports call it when the connection ends.
+func (s *Session) Close() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	watch, closers := s.watch, s.closers
+	s.closers = nil
+	s.mu.Unlock()
+	if watch != nil {
+		watch.close()
+	}
+	s.failPending()
+	s.outbox.close()
+	for _, fn := range closers {
+		fn()
+	}
+}
+
+// onClose registers fn to run when the session closes.
+func (s *Session) onClose(fn func()) {
+	s.mu.Lock()
+	s.closers = append(s.closers, fn)
+	s.mu.Unlock()
+}
+
+// checkSubscriptions polls subscribed files immediately, so writes made by
+// a tool call are reported without waiting for the next poll tick.
+func (s *Session) checkSubscriptions() {
+	if watch := s.watcher(); watch != nil {
+		watch.poll()
+	}
+}
+
+func (s *Session) watcher() *watcher {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.watch
+}
+
+func (s *Session) resourceUpdated(uri string) {
+	s.mu.RLock()
+	w := s.notifier
+	s.mu.RUnlock()
+	if w != nil {
+		s.notify(w, "notifications/resources/updated", types.ResourceUpdatedParams{URI: uri})
+	}
+}
+
+func (s *MCPServer) handleSubscribeW(req *types.JSONRPCRequest, w ResponseWriter) {
+	var params types.SubscribeParams
+	if err := json.Unmarshal(req.Params, &params); err != nil || params.URI == "" {
+		w.WriteError(req.ID, -32602, "invalid params")
+		return
+	}
+	resource, ok := s.findResource(params.URI)
+	if !ok {
+		w.WriteError(req.ID, -32601, "unknown resource: "+params.URI)
+		return
+	}
+	var paths []string
+	if resource.Paths != nil {
+		paths = resource.Paths(params.URI)
+	}
+	w.Session().Subscribe(params.URI, paths)
+	_ = w.WriteResult(req.ID, map[string]any{})
+}
+
+func (s *MCPServer) handleUnsubscribeW(req *types.JSONRPCRequest, w ResponseWriter) {
+	var params types.SubscribeParams
+	if err := json.Unmarshal(req.Params, &params); err != nil || params.URI == "" {
+		w.WriteError(req.ID, -32602, "invalid params")
+		return
+	}
+	w.Session().Unsubscribe(params.URI)
+	_ = w.WriteResult(req.ID, map[string]any{})
+}
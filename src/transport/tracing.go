@@ -0,0 +1,57 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/orchestra-mcp/mcp/src/tracing"
+	"github.com/orchestra-mcp/mcp/src/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// spanWriter marks a request span as failed when an error response is
+// written for it.
+type spanWriter struct {
+	ResponseWriter
+	span trace.Span
+}
+
+func (w spanWriter) WriteError(id any, code int, msg string) error {
+	w.span.SetAttributes(attribute.Int("rpc.jsonrpc.error_code", code))
+	w.span.SetStatus(codes.Error, msg)
+	return w.ResponseWriter.WriteError(id, code, msg)
+}
+
+// startRequestSpan begins the span for one JSON-RPC request.
+func startRequestSpan(ctx context.Context, req *types.JSONRPCRequest, sess *Session) (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{
+		attribute.String("rpc.system", "jsonrpc"),
+		attribute.String("rpc.method", req.Method),
+	}
+	if req.ID != nil {
+		attrs = append(attrs, attribute.String("rpc.jsonrpc.request_id", fmt.Sprint(req.ID)))
+	}
+	if sess != nil {
+		attrs = append(attrs, attribute.String("mcp.session.id", sess.ID))
+	}
+	return tracing.Start(ctx, req.Method, attrs...)
+}
+
+// Tracing wraps each tool handler in a span named after the tool. Error
+// results count as failures.
+func Tracing() Middleware {
+	return func(next ToolHandler) ToolHandler {
+		return func(ctx context.Context, call *ToolCall) (*types.ToolResult, error) {
+			ctx, span := tracing.Start(ctx, "tool "+call.Definition.Name,
+				attribute.String("mcp.tool.name", call.Definition.Name))
+			result, err := next(ctx, call)
+			if err == nil && result != nil && result.IsError {
+				span.SetStatus(codes.Error, "tool returned an error result")
+			}
+			tracing.End(span, err)
+			return result, err
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package transport
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// WatchInterval is how often files behind subscribed resources are polled.
+var WatchInterval = 2 * time.Second
+
+// fileStamp identifies a version of a file without reading its contents.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+	exists  bool
+}
+
+func (a fileStamp) equal(b fileStamp) bool {
+	return a.exists == b.exists && a.size == b.size && a.modTime.Equal(b.modTime)
+}
+
+func stampOf(path string) fileStamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}
+	}
+	return fileStamp{modTime: info.ModTime(), size: info.Size(), exists: true}
+}
+
+// watcher polls the files behind subscribed URIs and reports the URIs whose
+// files changed. Polling keeps it free of native file-event dependencies.
+type watcher struct {
+	mu     sync.Mutex
+	files  map[string]map[string]fileStamp // uri -> path -> last stamp
+	notify func(uri string)
+	stop   chan struct{}
+}
+
+func newWatcher(notify func(uri string)) *watcher {
+	return &watcher{files: make(map[string]map[string]fileStamp), notify: notify}
+}
+
+// add starts watching paths for uri, replacing any previous set.
+func (w *watcher) add(uri string, paths []string) {
+	stamps := make(map[string]fileStamp, len(paths))
+	for _, p := range paths {
+		stamps[p] = stampOf(p)
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.files[uri] = stamps
+	if w.stop == nil {
+		w.stop = make(chan struct{})
+		go w.loop(w.stop)
+	}
+}
+
+// remove stops watching uri; the poll loop exits once nothing is watched.
+func (w *watcher) remove(uri string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.files, uri)
+	if len(w.files) == 0 && w.stop != nil {
+		close(w.stop)
+		w.stop = nil
+	}
+}
+
+// uris returns the watched URIs.
+func (w *watcher) uris() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	out := make([]string, 0, len(w.files))
+	for uri := range w.files {
+		out = append(out, uri)
+	}
+	return out
+}
+
+// close stops polling and forgets every watch.
+func (w *watcher) close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.files = make(map[string]map[string]fileStamp)
+	if w.stop != nil {
+		close(w.stop)
+		w.stop = nil
+	}
+}
+
+func (w *watcher) loop(stop <-chan struct{}) {
+	ticker := time.NewTicker(WatchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			w.poll()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// poll compares every watched file with its last stamp and notifies each
+// changed URI once. Notifications are sent outside the lock.
+func (w *watcher) poll() {
+	var changed []string
+	w.mu.Lock()
+	for uri, stamps := range w.files {
+		dirty := false
+		for p, old := range stamps {
+			if cur := stampOf(p); !cur.equal(old) {
+				stamps[p] = cur
+				dirty = true
+			}
+		}
+		if dirty {
+			changed = append(changed, uri)
+		}
+	}
+	w.mu.Unlock()
+	for _, uri := range changed {
+		w.notify(uri)
+	}
+}
@@ -2,34 +2,76 @@ package transport
 
 import (
 	"encoding/json"
-	"fmt"
-	"os"
+	"io"
+	"sync"
 
+	"github.com/orchestra-mcp/mcp/src/auth"
 	"github.com/orchestra-mcp/mcp/src/types"
 )
 
 // ResponseWriter abstracts how JSON-RPC responses are sent.
+// WriteNotification sends a server-initiated message such as
+// notifications/progress on the same connection, and WriteRequest a
+// server-initiated request such as sampling/createMessage.
+// Session returns the protocol state of the connection the writer serves.
 type ResponseWriter interface {
 	WriteResult(id, result any) error
 	WriteError(id any, code int, msg string) error
+	WriteNotification(method string, params any) error
+	WriteRequest(id any, method string, params any) error
+	Session() *Session
 }
 
-// StdioWriter writes JSON-RPC responses to stdout (one line per response).
-type StdioWriter struct{}
+// StdioWriter writes JSON-RPC responses as JSON Lines (one line per response).
+// Writes are serialized so concurrent handlers never interleave output.
+type StdioWriter struct {
+	mu      sync.Mutex
+	out     io.Writer
+	session *Session
+}
+
+// NewStdioWriter creates a line writer on out with its own session. The
+// stdio client is whoever started the server, so the session acts as
+// auth.Local and passes any configured token scopes.
+func NewStdioWriter(out io.Writer) *StdioWriter {
+	sess := NewSession("stdio")
+	sess.local = true
+	sess.principal = auth.Local
+	return &StdioWriter{out: out, session: sess}
+}
+
+func (w *StdioWriter) Session() *Session { return w.session }
 
 func (w *StdioWriter) WriteResult(id, result any) error {
-	resp := types.JSONRPCResponse{JSONRPC: "2.0", ID: id, Result: result}
-	data, _ := json.Marshal(resp)
-	_, err := fmt.Fprintf(os.Stdout, "%s\n", data)
-	return err
+	return w.writeLine(types.JSONRPCResponse{JSONRPC: "2.0", ID: id, Result: result})
 }
 
 func (w *StdioWriter) WriteError(id any, code int, msg string) error {
-	resp := types.JSONRPCResponse{
+	return w.writeLine(types.JSONRPCResponse{
 		JSONRPC: "2.0", ID: id,
 		Error: &types.JSONRPCError{Code: code, Message: msg},
+	})
+}
+
+func (w *StdioWriter) WriteNotification(method string, params any) error {
+	return w.writeLine(types.JSONRPCNotification{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+func (w *StdioWriter) WriteRequest(id any, method string, params any) error {
+	return w.writeLine(types.JSONRPCOutgoingRequest{JSONRPC: "2.0", ID: id, Method: method, Params: params})
+}
+
+func (w *StdioWriter) writeBatch(responses []types.JSONRPCResponse) error {
+	return w.writeLine(responses)
+}
+
+func (w *StdioWriter) writeLine(msg any) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
 	}
-	data, _ := json.Marshal(resp)
-	_, err := fmt.Fprintf(os.Stdout, "%s\n", data)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, err = w.out.Write(append(data, '\n'))
 	return err
 }
@@ -0,0 +1,43 @@
+package types
+
+// CompleteParams is the request body of completion/complete.
+type CompleteParams struct {
+	Ref      CompletionRef      `json:"ref"`
+	Argument CompletionArgument `json:"argument"`
+	Context  *CompletionContext `json:"context,omitempty"`
+}
+
+// CompletionRef names the prompt ("ref/prompt") or resource template
+// ("ref/resource") whose argument is being completed.
+type CompletionRef struct {
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
+	URI  string `json:"uri,omitempty"`
+}
+
+// CompletionArgument is the argument being completed and its partial value.
+type CompletionArgument struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// CompletionContext carries arguments the client has already resolved.
+type CompletionContext struct {
+	Arguments map[string]string `json:"arguments,omitempty"`
+}
+
+// CompleteResult is the response of completion/complete.
+type CompleteResult struct {
+	Completion Completion `json:"completion"`
+}
+
+// Completion lists suggested values, at most 100 per response.
+type Completion struct {
+	Values  []string `json:"values"`
+	Total   int      `json:"total,omitempty"`
+	HasMore bool     `json:"hasMore,omitempty"`
+}
+
+// CompletionFunc returns candidate values for the named argument, given the
+// arguments already resolved. The server filters candidates by prefix.
+type CompletionFunc func(name string, args map[string]string) []string
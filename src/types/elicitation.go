@@ -0,0 +1,23 @@
+package types
+
+// ElicitParams is the request body of elicitation/create, sent by the server
+// to ask the user for structured input through the client.
+type ElicitParams struct {
+	Message         string       `json:"message"`
+	RequestedSchema ElicitSchema `json:"requestedSchema"`
+}
+
+// ElicitSchema is the flat object schema of an elicitation form. Properties
+// are primitive schemas: string (optionally with enum), number or boolean.
+type ElicitSchema struct {
+	Type       string         `json:"type"`
+	Properties map[string]any `json:"properties"`
+	Required   []string       `json:"required,omitempty"`
+}
+
+// ElicitResult is the client's answer to elicitation/create. Action is
+// "accept", "decline" or "cancel"; Content is set only on accept.
+type ElicitResult struct {
+	Action  string         `json:"action"`
+	Content map[string]any `json:"content,omitempty"`
+}
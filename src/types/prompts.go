@@ -26,7 +26,9 @@ type PromptMessage struct {
 type PromptHandler func(args map[string]string) (string, []PromptMessage, error)
 
 // Prompt pairs a definition with its handler.
+// Complete, when set, suggests argument values for completion/complete.
 type Prompt struct {
 	Definition PromptDefinition
 	Handler    PromptHandler
+	Complete   CompletionFunc
 }
@@ -18,16 +18,102 @@ type JSONRPCResponse struct {
 	Error   *JSONRPCError `json:"error,omitempty"`
 }
 
+// MarshalJSON always emits id (null when unknown, as JSON-RPC requires for
+// parse errors) and exactly one of result or error; a nil or empty result
+// still appears so successful replies like ping's {} are well-formed.
+func (r JSONRPCResponse) MarshalJSON() ([]byte, error) {
+	if r.Error != nil {
+		return json.Marshal(struct {
+			JSONRPC string        `json:"jsonrpc"`
+			ID      any           `json:"id"`
+			Error   *JSONRPCError `json:"error"`
+		}{"2.0", r.ID, r.Error})
+	}
+	return json.Marshal(struct {
+		JSONRPC string `json:"jsonrpc"`
+		ID      any    `json:"id"`
+		Result  any    `json:"result"`
+	}{"2.0", r.ID, r.Result})
+}
+
 // JSONRPCError carries error info in a JSON-RPC response.
 type JSONRPCError struct {
 	Code    int    `json:"code"`
 	Message string `json:"message"`
 }
 
+// JSONRPCNotification is an outgoing JSON-RPC 2.0 notification (no ID).
+type JSONRPCNotification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+// JSONRPCOutgoingRequest is a server-initiated JSON-RPC 2.0 request, such
+// as sampling/createMessage.
+type JSONRPCOutgoingRequest struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      any    `json:"id"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
 // CallToolParams is the params payload for tools/call.
 type CallToolParams struct {
 	Name      string         `json:"name"`
 	Arguments map[string]any `json:"arguments,omitempty"`
+	Meta      *RequestMeta   `json:"_meta,omitempty"`
+}
+
+// RequestMeta carries the optional _meta object of a request.
+type RequestMeta struct {
+	ProgressToken any `json:"progressToken,omitempty"`
+}
+
+// ProgressParams is the params payload for notifications/progress.
+type ProgressParams struct {
+	ProgressToken any     `json:"progressToken"`
+	Progress      float64 `json:"progress"`
+	Total         float64 `json:"total,omitempty"`
+	Message       string  `json:"message,omitempty"`
+}
+
+// InitializeParams is the params payload for initialize.
+type InitializeParams struct {
+	ProtocolVersion string     `json:"protocolVersion"`
+	Capabilities    ClientCaps `json:"capabilities"`
+	ClientInfo      ClientInfo `json:"clientInfo"`
+}
+
+// ClientCaps declares client capabilities.
+type ClientCaps struct {
+	Roots        *RootsCap       `json:"roots,omitempty"`
+	Sampling     *SamplingCap    `json:"sampling,omitempty"`
+	Elicitation  *ElicitationCap `json:"elicitation,omitempty"`
+	Experimental map[string]any  `json:"experimental,omitempty"`
+}
+
+// RootsCap advertises client support for roots/list.
+type RootsCap struct {
+	ListChanged bool `json:"listChanged,omitempty"`
+}
+
+// SamplingCap advertises client support for sampling/createMessage.
+type SamplingCap struct{}
+
+// ElicitationCap advertises client support for elicitation/create.
+type ElicitationCap struct{}
+
+// ClientInfo identifies the client.
+type ClientInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// CancelledParams is the params payload for notifications/cancelled.
+type CancelledParams struct {
+	RequestID any    `json:"requestId"`
+	Reason    string `json:"reason,omitempty"`
 }
 
 // InitializeResult is returned from the initialize method.
@@ -39,9 +125,11 @@ type InitializeResult struct {
 
 // ServerCaps declares server capabilities.
 type ServerCaps struct {
-	Tools     *ToolsCap     `json:"tools,omitempty"`
-	Resources *ResourcesCap `json:"resources,omitempty"`
-	Prompts   *PromptsCap   `json:"prompts,omitempty"`
+	Tools       *ToolsCap       `json:"tools,omitempty"`
+	Resources   *ResourcesCap   `json:"resources,omitempty"`
+	Prompts     *PromptsCap     `json:"prompts,omitempty"`
+	Completions *CompletionsCap `json:"completions,omitempty"`
+	Logging     *LoggingCap     `json:"logging,omitempty"`
 }
 
 // ToolsCap advertises tool support.
@@ -60,6 +148,24 @@ type PromptsCap struct {
 	ListChanged bool `json:"listChanged,omitempty"`
 }
 
+// CompletionsCap advertises support for completion/complete.
+type CompletionsCap struct{}
+
+// LoggingCap advertises support for logging/setLevel and notifications/message.
+type LoggingCap struct{}
+
+// SetLevelParams is the request body of logging/setLevel.
+type SetLevelParams struct {
+	Level string `json:"level"`
+}
+
+// LoggingMessageParams is the body of notifications/message.
+type LoggingMessageParams struct {
+	Level  string `json:"level"`
+	Logger string `json:"logger,omitempty"`
+	Data   any    `json:"data"`
+}
+
 // ServerInfo identifies the server.
 type ServerInfo struct {
 	Name    string `json:"name"`
@@ -76,11 +182,26 @@ type ReadResourceParams struct {
 	URI string `json:"uri"`
 }
 
+// SubscribeParams is the params payload for resources/subscribe and resources/unsubscribe.
+type SubscribeParams struct {
+	URI string `json:"uri"`
+}
+
+// ResourceUpdatedParams is the params payload for notifications/resources/updated.
+type ResourceUpdatedParams struct {
+	URI string `json:"uri"`
+}
+
 // ListResourcesResult wraps the resources/list response.
 type ListResourcesResult struct {
 	Resources []ResourceDefinition `json:"resources"`
 }
 
+// ListResourceTemplatesResult wraps the resources/templates/list response.
+type ListResourceTemplatesResult struct {
+	ResourceTemplates []ResourceTemplate `json:"resourceTemplates"`
+}
+
 // ReadResourceResult wraps the resources/read response.
 type ReadResourceResult struct {
 	Contents []ResourceContent `json:"contents"`
@@ -20,8 +20,30 @@ type ResourceContent struct {
 // ResourceHandler reads a resource by URI and returns its contents.
 type ResourceHandler func(uri string) ([]ResourceContent, error)
 
-// Resource pairs a definition with its handler.
+// ResourceTemplate describes a parameterized resource (RFC 6570 URI template).
+type ResourceTemplate struct {
+	URITemplate string `json:"uriTemplate"`
+	Name        string `json:"name"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+// ResourcePathsFunc returns the files backing a concrete resource URI.
+type ResourcePathsFunc func(uri string) []string
+
+// ResourceListFunc enumerates the concrete instances of a templated resource.
+type ResourceListFunc func() []ResourceDefinition
+
+// Resource pairs a definition with its handler. A Definition.URI containing
+// {var} placeholders is a template: it is listed by resources/templates/list
+// and its instances come from List.
+// Paths, when set, lets clients subscribe to changes of the backing files.
+// Complete, when set, suggests template variable values for completion/complete.
 type Resource struct {
 	Definition ResourceDefinition
 	Handler    ResourceHandler
+	Paths      ResourcePathsFunc
+	List       ResourceListFunc
+	Complete   CompletionFunc
 }
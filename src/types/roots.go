@@ -0,0 +1,12 @@
+package types
+
+// Root is a directory the client exposes to the server, as a file:// URI.
+type Root struct {
+	URI  string `json:"uri"`
+	Name string `json:"name,omitempty"`
+}
+
+// ListRootsResult is the client's answer to roots/list.
+type ListRootsResult struct {
+	Roots []Root `json:"roots"`
+}
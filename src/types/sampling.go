@@ -0,0 +1,41 @@
+package types
+
+// CreateMessageParams is the request body of sampling/createMessage, sent by
+// the server to ask the client's model for a completion.
+type CreateMessageParams struct {
+	Messages         []SamplingMessage `json:"messages"`
+	ModelPreferences *ModelPreferences `json:"modelPreferences,omitempty"`
+	SystemPrompt     string            `json:"systemPrompt,omitempty"`
+	IncludeContext   string            `json:"includeContext,omitempty"`
+	Temperature      *float64          `json:"temperature,omitempty"`
+	MaxTokens        int               `json:"maxTokens"`
+	StopSequences    []string          `json:"stopSequences,omitempty"`
+}
+
+// SamplingMessage is one conversation turn in a sampling request or result.
+type SamplingMessage struct {
+	Role    string       `json:"role"`
+	Content ContentBlock `json:"content"`
+}
+
+// ModelPreferences hints which model the client should pick.
+// Priorities range from 0 to 1.
+type ModelPreferences struct {
+	Hints                []ModelHint `json:"hints,omitempty"`
+	CostPriority         *float64    `json:"costPriority,omitempty"`
+	SpeedPriority        *float64    `json:"speedPriority,omitempty"`
+	IntelligencePriority *float64    `json:"intelligencePriority,omitempty"`
+}
+
+// ModelHint names a model or model family, e.g. "claude-sonnet".
+type ModelHint struct {
+	Name string `json:"name,omitempty"`
+}
+
+// CreateMessageResult is the client's answer to sampling/createMessage.
+type CreateMessageResult struct {
+	Role       string       `json:"role"`
+	Content    ContentBlock `json:"content"`
+	Model      string       `json:"model"`
+	StopReason string       `json:"stopReason,omitempty"`
+}
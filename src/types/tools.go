@@ -1,11 +1,15 @@
 package types
 
+import "context"
+
 // ToolDefinition describes a single MCP tool.
 type ToolDefinition struct {
-	Name        string      `json:"name"`
-	Description string      `json:"description"`
-	InputSchema InputSchema `json:"inputSchema"`
-	Namespace   string      `json:"-"` // internal only, not serialized
+	Name         string           `json:"name"`
+	Description  string           `json:"description"`
+	InputSchema  InputSchema      `json:"inputSchema"`
+	OutputSchema *OutputSchema    `json:"outputSchema,omitempty"`
+	Annotations  *ToolAnnotations `json:"annotations,omitempty"`
+	Namespace    string           `json:"-"` // internal only, not serialized
 }
 
 // QualifiedName returns "namespace.name" if namespace is set, else just name.
@@ -16,17 +20,41 @@ func (td ToolDefinition) QualifiedName() string {
 	return td.Name
 }
 
-// InputSchema is the JSON Schema for tool input.
+// InputSchema is the JSON Schema for tool input. Arguments not listed in
+// Properties are rejected unless AdditionalProperties is true.
 type InputSchema struct {
+	Type                 string         `json:"type"`
+	Properties           map[string]any `json:"properties,omitempty"`
+	Required             []string       `json:"required,omitempty"`
+	AdditionalProperties *bool          `json:"additionalProperties,omitempty"`
+}
+
+// OutputSchema is the JSON Schema for a tool's structuredContent.
+// MCP requires the root to be an object.
+type OutputSchema struct {
 	Type       string         `json:"type"`
 	Properties map[string]any `json:"properties,omitempty"`
 	Required   []string       `json:"required,omitempty"`
 }
 
+// ToolAnnotations are behavior hints for clients, e.g. to auto-approve
+// read-only calls or confirm destructive ones. Hints are pointers because MCP
+// defaults destructiveHint and openWorldHint to true when omitted.
+type ToolAnnotations struct {
+	Title           string `json:"title,omitempty"`
+	ReadOnlyHint    *bool  `json:"readOnlyHint,omitempty"`
+	DestructiveHint *bool  `json:"destructiveHint,omitempty"`
+	IdempotentHint  *bool  `json:"idempotentHint,omitempty"`
+	OpenWorldHint   *bool  `json:"openWorldHint,omitempty"`
+}
+
 // ToolResult is returned by tool handlers.
+// StructuredContent mirrors the text content as a JSON object for clients
+// that read outputSchema; the text block stays for older clients.
 type ToolResult struct {
-	Content []ContentBlock `json:"content"`
-	IsError bool           `json:"isError,omitempty"`
+	Content           []ContentBlock `json:"content"`
+	StructuredContent any            `json:"structuredContent,omitempty"`
+	IsError           bool           `json:"isError,omitempty"`
 }
 
 // ContentBlock is a single content item in a tool result.
@@ -38,8 +66,30 @@ type ContentBlock struct {
 // ToolHandler processes a tool call and returns a result.
 type ToolHandler func(args map[string]any) (*ToolResult, error)
 
+// ContextToolHandler processes a tool call that honors cancellation via ctx.
+type ContextToolHandler func(ctx context.Context, args map[string]any) (*ToolResult, error)
+
 // Tool pairs a definition with its handler.
+// ContextHandler, when set, is preferred by transports over Handler.
 type Tool struct {
-	Definition ToolDefinition
-	Handler    ToolHandler
+	Definition     ToolDefinition
+	Handler        ToolHandler
+	ContextHandler ContextToolHandler
+}
+
+// NewContextTool builds a Tool whose Handler delegates to fn with a background context.
+func NewContextTool(def ToolDefinition, fn ContextToolHandler) Tool {
+	return Tool{
+		Definition:     def,
+		Handler:        func(args map[string]any) (*ToolResult, error) { return fn(context.Background(), args) },
+		ContextHandler: fn,
+	}
+}
+
+// Call runs the tool, passing ctx through when it has a ContextHandler.
+func (t Tool) Call(ctx context.Context, args map[string]any) (*ToolResult, error) {
+	if t.ContextHandler != nil {
+		return t.ContextHandler(ctx, args)
+	}
+	return t.Handler(args)
 }
@@ -0,0 +1,199 @@
+package audit_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/orchestra-mcp/mcp/src/audit"
+	"github.com/orchestra-mcp/mcp/src/auth"
+	h "github.com/orchestra-mcp/mcp/src/helpers"
+	"github.com/orchestra-mcp/mcp/src/transport"
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+func day(d int, hour int) time.Time {
+	return time.Date(2026, 10, d, hour, 0, 0, 0, time.UTC)
+}
+
+func seed(t *testing.T) string {
+	t.Helper()
+	ws := t.TempDir()
+	for _, e := range []audit.Entry{
+		{Time: day(1, 9), Tool: "create_epic", SessionID: "s1", Issues: []string{"OM-3"}},
+		{Time: day(2, 9), Tool: "update_epic", SessionID: "s2", Issues: []string{"OM-3"}},
+		{Time: day(3, 9), Tool: "delete_epic", SessionID: "s2", Issues: []string{"om-3"}},
+		{Time: day(3, 10), Tool: "list_epics", SessionID: "s1"},
+	} {
+		if err := audit.Append(ws, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return ws
+}
+
+func tools(entries []audit.Entry) []string {
+	var out []string
+	for _, e := range entries {
+		out = append(out, e.Tool)
+	}
+	return out
+}
+
+func TestQueryFilters(t *testing.T) {
+	ws := seed(t)
+	endOf3, err := audit.ParseUntil("2026-10-03")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		f    audit.Filter
+		want int
+	}{
+		{"all", audit.Filter{}, 4},
+		{"tool", audit.Filter{Tool: "delete_epic"}, 1},
+		{"issue", audit.Filter{Issue: "OM-3"}, 3},
+		{"session", audit.Filter{Session: "s1"}, 2},
+		{"since", audit.Filter{Since: day(2, 12)}, 2},
+		{"until", audit.Filter{Until: day(2, 9)}, 2},
+		{"range", audit.Filter{Since: day(2, 0), Until: day(3, 9)}, 2},
+		{"same day", audit.Filter{Since: day(3, 0), Until: endOf3}, 2},
+		{"limit", audit.Filter{Limit: 1}, 1},
+	}
+	for _, tt := range tests {
+		got, err := audit.Query(ws, tt.f)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if len(got) != tt.want {
+			t.Errorf("%s: got %v, want %d entries", tt.name, tools(got), tt.want)
+		}
+	}
+	got, _ := audit.Query(ws, audit.Filter{Limit: 1})
+	if got[0].Tool != "list_epics" {
+		t.Errorf("limit kept %q, want the newest entry", got[0].Tool)
+	}
+}
+
+func TestAppendWritesDailyFiles(t *testing.T) {
+	ws := seed(t)
+	files, _ := filepath.Glob(filepath.Join(audit.Dir(ws), "*.jsonl"))
+	if len(files) != 3 || filepath.Base(files[0]) != "2026-10-01.jsonl" {
+		t.Errorf("files = %v", files)
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	if got, err := audit.ParseTime("2026-10-02"); err != nil || !got.Equal(day(2, 0)) {
+		t.Errorf("date = %v, %v", got, err)
+	}
+	if got, err := audit.ParseTime("2026-10-02T09:00:00Z"); err != nil || !got.Equal(day(2, 9)) {
+		t.Errorf("timestamp = %v, %v", got, err)
+	}
+	if _, err := audit.ParseTime("yesterday"); err == nil {
+		t.Error("expected an error")
+	}
+	if got, err := audit.ParseUntil("2026-10-02"); err != nil || !got.Before(day(3, 0)) || got.Before(day(2, 23)) {
+		t.Errorf("date-only until = %v, %v, want the end of the day", got, err)
+	}
+	if got, err := audit.ParseUntil("2026-10-02T09:00:00Z"); err != nil || !got.Equal(day(2, 9)) {
+		t.Errorf("timestamp until = %v, %v", got, err)
+	}
+}
+
+func auditedServer(ws string) *transport.MCPServer {
+	s := transport.New("s", "1.0")
+	s.Use(audit.Middleware(ws))
+	s.OnReject(audit.Rejections(ws))
+	s.Use(transport.DefaultMiddleware()...)
+	s.RegisterTool(types.Tool{
+		Definition: types.ToolDefinition{Name: "create_epic", InputSchema: types.InputSchema{Type: "object", Properties: map[string]any{
+			"title": map[string]any{"type": "string"}, "api_token": map[string]any{"type": "string"},
+		}}},
+		Handler: func(map[string]any) (*types.ToolResult, error) {
+			return h.StructuredResult(map[string]any{"id": "OM-7"}), nil
+		},
+	})
+	s.RegisterTool(types.Tool{
+		Definition: types.ToolDefinition{Name: "delete_epic", InputSchema: types.InputSchema{Type: "object", Properties: map[string]any{
+			"epic_id": map[string]any{"type": "string"},
+		}, Required: []string{"epic_id"}}},
+		Handler: func(map[string]any) (*types.ToolResult, error) { return h.TextResult("deleted"), nil },
+	})
+	return s
+}
+
+func TestMiddlewareRecordsCalls(t *testing.T) {
+	ws := t.TempDir()
+	os.MkdirAll(h.ProjectsDir(ws), 0o755)
+	s := auditedServer(ws)
+	sess := transport.NewSession("sess-1")
+	ctx := context.Background()
+
+	s.CallTool(ctx, &transport.ToolCall{Name: "create_epic", Session: sess, Arguments: map[string]any{"title": "x", "api_token": "secret"}})
+	s.CallTool(ctx, &transport.ToolCall{Name: "delete_epic", Session: sess, Arguments: map[string]any{"epic_id": "OM-3"}})
+	s.CallTool(ctx, &transport.ToolCall{Name: "delete_epic", Session: sess, Arguments: map[string]any{}})
+
+	entries, err := audit.Query(ws, audit.Filter{})
+	if err != nil || len(entries) != 3 {
+		t.Fatalf("entries = %+v, %v", entries, err)
+	}
+	created := entries[0]
+	if created.SessionID != "sess-1" || created.Arguments["api_token"] != transport.Redacted || created.Arguments["title"] != "x" {
+		t.Errorf("create entry = %+v", created)
+	}
+	if len(created.Issues) != 1 || created.Issues[0] != "OM-7" {
+		t.Errorf("created issues = %v", created.Issues)
+	}
+	if deleted := entries[1]; len(deleted.Issues) != 1 || deleted.Issues[0] != "OM-3" || deleted.ErrorCode != 0 {
+		t.Errorf("delete entry = %+v", deleted)
+	}
+	if rejected := entries[2]; rejected.ErrorCode != -32602 || rejected.Error == "" {
+		t.Errorf("rejected entry = %+v", rejected)
+	}
+	byIssue, _ := audit.Query(ws, audit.Filter{Issue: "OM-3", Tool: "delete_epic"})
+	if len(byIssue) != 1 {
+		t.Errorf("delete_epic on OM-3 = %d entries, want 1", len(byIssue))
+	}
+}
+
+func TestRejectionsRecorded(t *testing.T) {
+	ws := t.TempDir()
+	os.MkdirAll(h.ProjectsDir(ws), 0o755)
+	s := auditedServer(ws)
+	a, err := auth.New([]auth.Token{{Name: "ci", Secret: "x", Scopes: []auth.Scope{auth.ScopeRead}}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetAuthenticator(a)
+	ci := &auth.Principal{Name: "ci", Scopes: []auth.Scope{auth.ScopeRead}}
+	ctx := context.Background()
+
+	s.CallTool(ctx, &transport.ToolCall{Name: "delete_epic", Principal: ci, Arguments: map[string]any{"epic_id": "OM-3", "api_token": "secret"}})
+	s.CallTool(ctx, &transport.ToolCall{Name: "drop_tables", Principal: ci})
+
+	entries, err := audit.Query(ws, audit.Filter{})
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("entries = %+v, %v", entries, err)
+	}
+	if e := entries[0]; e.Caller != "ci" || e.ErrorCode != auth.CodeForbidden || e.Arguments["api_token"] != transport.Redacted || e.Issues[0] != "OM-3" {
+		t.Errorf("forbidden entry = %+v", e)
+	}
+	if e := entries[1]; e.Tool != "drop_tables" || e.ErrorCode != -32601 {
+		t.Errorf("unknown tool entry = %+v", e)
+	}
+}
+
+func TestMiddlewareSkipsUninitializedWorkspace(t *testing.T) {
+	ws := t.TempDir()
+	s := auditedServer(ws)
+	if _, err := s.CallTool(context.Background(), &transport.ToolCall{Name: "delete_epic", Arguments: map[string]any{"epic_id": "OM-3"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(audit.Dir(ws)); !os.IsNotExist(err) {
+		t.Errorf("audit dir created in an uninitialized workspace: %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+package auth_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/orchestra-mcp/mcp/src/auth"
+	"github.com/orchestra-mcp/mcp/src/tools"
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+func hint(b bool) *bool { return &b }
+
+var (
+	readTool    = types.ToolDefinition{Name: "list_epics", Annotations: &types.ToolAnnotations{ReadOnlyHint: hint(true)}}
+	writeTool   = types.ToolDefinition{Name: "create_epic", Annotations: &types.ToolAnnotations{ReadOnlyHint: hint(false), DestructiveHint: hint(false)}}
+	deleteTool  = types.ToolDefinition{Name: "delete_epic", Annotations: &types.ToolAnnotations{ReadOnlyHint: hint(false), DestructiveHint: hint(true)}}
+	unannotated = types.ToolDefinition{Name: "external"}
+)
+
+func newAuth(t *testing.T, overrides map[string]auth.Scope) *auth.Authenticator {
+	t.Helper()
+	a, err := auth.New([]auth.Token{
+		{Name: "ci", Secret: "r-token", Scopes: []auth.Scope{auth.ScopeRead}},
+		{Name: "dev", Secret: "w-token", Scopes: []auth.Scope{auth.ScopeWrite}},
+		{Name: "ops", Secret: "a-token", Scopes: []auth.Scope{auth.ScopeAdmin}},
+	}, overrides)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func TestScopeFor(t *testing.T) {
+	a := newAuth(t, map[string]auth.Scope{"create_epic": auth.ScopeAdmin})
+	cases := map[string]struct {
+		def  types.ToolDefinition
+		want auth.Scope
+	}{
+		"read-only":   {readTool, auth.ScopeRead},
+		"override":    {writeTool, auth.ScopeAdmin},
+		"destructive": {deleteTool, auth.ScopeAdmin},
+		"unannotated": {unannotated, auth.ScopeAdmin},
+	}
+	for name, c := range cases {
+		if got := a.ScopeFor(c.def); got != c.want {
+			t.Errorf("%s: scope = %s, want %s", name, got, c.want)
+		}
+	}
+	if got := newAuth(t, nil).ScopeFor(writeTool); got != auth.ScopeWrite {
+		t.Errorf("additive tool scope = %s, want write", got)
+	}
+}
+
+func TestAuthorizeToolByScope(t *testing.T) {
+	a := newAuth(t, nil)
+	cases := []struct {
+		secret string
+		def    types.ToolDefinition
+		code   int // 0 means allowed
+	}{
+		{"r-token", readTool, 0},
+		{"r-token", writeTool, auth.CodeForbidden},
+		{"w-token", writeTool, 0},
+		{"w-token", deleteTool, auth.CodeForbidden},
+		{"a-token", deleteTool, 0},
+		{"a-token", readTool, 0},
+	}
+	for _, c := range cases {
+		p, err := a.Authenticate(c.secret)
+		if err != nil {
+			t.Fatalf("Authenticate(%s): %v", c.secret, err)
+		}
+		err = a.AuthorizeTool(p, c.def)
+		switch {
+		case c.code == 0 && err != nil:
+			t.Errorf("%s calling %s: %v", p.Name, c.def.Name, err)
+		case c.code != 0 && (err == nil || auth.Code(err) != c.code):
+			t.Errorf("%s calling %s: err = %v, want code %d", p.Name, c.def.Name, err, c.code)
+		}
+	}
+}
+
+func TestAuditLogNeedsAdmin(t *testing.T) {
+	a := newAuth(t, nil)
+	query := tools.Audit(t.TempDir())[0].Definition
+	reader, _ := a.Authenticate("r-token")
+	if err := a.AuthorizeTool(reader, query); err == nil || auth.Code(err) != auth.CodeForbidden {
+		t.Errorf("read token calling %s: err = %v, want forbidden", query.Name, err)
+	}
+	admin, _ := a.Authenticate("a-token")
+	if err := a.AuthorizeTool(admin, query); err != nil {
+		t.Errorf("admin token calling %s: %v", query.Name, err)
+	}
+	override := newAuth(t, map[string]auth.Scope{query.Name: auth.ScopeRead})
+	if got := override.ScopeFor(query); got != auth.ScopeRead {
+		t.Errorf("configured scope = %s, want read", got)
+	}
+}
+
+func TestAuthenticateRejectsUnknownTokens(t *testing.T) {
+	a := newAuth(t, nil)
+	for _, secret := range []string{"", "nope"} {
+		if _, err := a.Authenticate(secret); !errors.Is(err, auth.ErrUnauthorized) {
+			t.Errorf("Authenticate(%q) = %v, want ErrUnauthorized", secret, err)
+		}
+	}
+	if err := a.AuthorizeTool(nil, readTool); auth.Code(err) != auth.CodeUnauthorized {
+		t.Errorf("anonymous call: %v", err)
+	}
+}
+
+func TestDisabledAllowsEverything(t *testing.T) {
+	for _, a := range []*auth.Authenticator{nil, mustNew(t)} {
+		if a.Enabled() {
+			t.Error("authenticator without tokens should be disabled")
+		}
+		if err := a.AuthorizeTool(nil, deleteTool); err != nil {
+			t.Errorf("disabled auth rejected call: %v", err)
+		}
+	}
+}
+
+func mustNew(t *testing.T) *auth.Authenticator {
+	a, err := auth.New(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func TestNewValidatesTokens(t *testing.T) {
+	if _, err := auth.New([]auth.Token{{Name: "x", Scopes: []auth.Scope{auth.ScopeRead}}}, nil); err == nil {
+		t.Error("expected error for token without secret")
+	}
+	if _, err := auth.New([]auth.Token{{Name: "x", Secret: "s"}}, nil); err == nil {
+		t.Error("expected error for token without scopes")
+	}
+	if _, err := auth.ParseScope("root"); err == nil {
+		t.Error("expected error for unknown scope")
+	}
+}
+
+func TestCredential(t *testing.T) {
+	cases := []struct{ authz, key, want string }{
+		{"Bearer abc", "", "abc"},
+		{"bearer abc", "zzz", "abc"},
+		{"", "key", "key"},
+		{"Basic xyz", "", ""},
+	}
+	for _, c := range cases {
+		if got := auth.Credential(c.authz, c.key); got != c.want {
+			t.Errorf("Credential(%q, %q) = %q, want %q", c.authz, c.key, got, c.want)
+		}
+	}
+}
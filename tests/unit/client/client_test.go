@@ -0,0 +1,158 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/orchestra-mcp/mcp/src/client"
+	"github.com/orchestra-mcp/mcp/src/transport"
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+func upstream() *transport.MCPServer {
+	s := transport.New("upstream", "1.0")
+	s.RegisterTool(types.Tool{
+		Definition: types.ToolDefinition{Name: "echo", InputSchema: types.InputSchema{
+			Type: "object", Properties: map[string]any{"text": map[string]any{"type": "string"}},
+		}},
+		Handler: func(args map[string]any) (*types.ToolResult, error) {
+			return &types.ToolResult{Content: []types.ContentBlock{{Type: "text", Text: args["text"].(string)}}}, nil
+		},
+	})
+	s.RegisterResource(types.Resource{
+		Definition: types.ResourceDefinition{URI: "test://readme", Name: "readme"},
+		Handler: func(uri string) ([]types.ResourceContent, error) {
+			return []types.ResourceContent{{URI: uri, Text: "hello"}}, nil
+		},
+	})
+	s.RegisterPrompt(types.Prompt{
+		Definition: types.PromptDefinition{Name: "greet"},
+		Handler: func(args map[string]string) (string, []types.PromptMessage, error) {
+			return "greeting", []types.PromptMessage{{Role: "user", Content: types.ContentBlock{Type: "text", Text: "hi " + args["who"]}}}, nil
+		},
+	})
+	return s
+}
+
+// pipeClient connects a client to s over in-memory JSON Lines pipes.
+func pipeClient(t *testing.T, s *transport.MCPServer) *client.Client {
+	t.Helper()
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	go func() {
+		s.Serve(inR, outW)
+		outW.Close()
+	}()
+	c := client.NewStream(outR, inW)
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func httpClient(t *testing.T, s *transport.MCPServer) *client.Client {
+	t.Helper()
+	srv := httptest.NewServer(transport.NewHTTPHandler(s))
+	c := client.NewHTTP(srv.URL, nil)
+	t.Cleanup(func() {
+		c.Close()
+		srv.Close()
+	})
+	return c
+}
+
+func exercise(t *testing.T, c *client.Client, s *transport.MCPServer) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	changed := make(chan string, 4)
+	c.OnNotification(func(method string, _ json.RawMessage) { changed <- method })
+
+	info, err := c.Initialize(ctx)
+	if err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	if info.ServerInfo.Name != "upstream" || info.Capabilities.Prompts == nil {
+		t.Errorf("initialize result = %+v", info)
+	}
+
+	tools, err := c.ListTools(ctx)
+	if err != nil || len(tools) != 1 || tools[0].Name != "echo" {
+		t.Fatalf("ListTools = %+v, %v", tools, err)
+	}
+	res, err := c.CallTool(ctx, "echo", map[string]any{"text": "ping"})
+	if err != nil || res.Content[0].Text != "ping" {
+		t.Fatalf("CallTool = %+v, %v", res, err)
+	}
+	_, err = c.CallTool(ctx, "missing", nil)
+	var rpcErr *client.Error
+	if !errors.As(err, &rpcErr) || rpcErr.Code != -32601 {
+		t.Errorf("unknown tool error = %v", err)
+	}
+
+	contents, err := c.ReadResource(ctx, "test://readme")
+	if err != nil || contents[0].Text != "hello" {
+		t.Errorf("ReadResource = %+v, %v", contents, err)
+	}
+	prompt, err := c.GetPrompt(ctx, "greet", map[string]string{"who": "bob"})
+	if err != nil || prompt.Messages[0].Content.Text != "hi bob" {
+		t.Errorf("GetPrompt = %+v, %v", prompt, err)
+	}
+	if err := c.Ping(ctx); err != nil {
+		t.Errorf("Ping: %v", err)
+	}
+
+	s.RegisterTool(types.Tool{Definition: types.ToolDefinition{Name: "added", InputSchema: types.InputSchema{Type: "object"}}})
+	select {
+	case m := <-changed:
+		if m != "notifications/tools/list_changed" {
+			t.Errorf("notification = %s", m)
+		}
+	case <-ctx.Done():
+		t.Fatal("no list_changed notification")
+	}
+}
+
+func TestStreamClient(t *testing.T) {
+	s := upstream()
+	exercise(t, pipeClient(t, s), s)
+}
+
+func TestHTTPClient(t *testing.T) {
+	s := upstream()
+	exercise(t, httpClient(t, s), s)
+}
+
+func TestCloseFailsPendingCalls(t *testing.T) {
+	s := transport.New("s", "1.0")
+	started := make(chan struct{})
+	s.RegisterTool(types.NewContextTool(
+		types.ToolDefinition{Name: "block", InputSchema: types.InputSchema{Type: "object"}},
+		func(ctx context.Context, _ map[string]any) (*types.ToolResult, error) {
+			close(started)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	))
+	c := pipeClient(t, s)
+	if _, err := c.Initialize(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	errc := make(chan error, 1)
+	go func() {
+		_, err := c.CallTool(context.Background(), "block", nil)
+		errc <- err
+	}()
+	<-started
+	c.Close()
+	select {
+	case err := <-errc:
+		if !errors.Is(err, client.ErrClosed) {
+			t.Errorf("err = %v, want ErrClosed", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("pending call not failed")
+	}
+}
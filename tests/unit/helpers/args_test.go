@@ -54,3 +54,13 @@ func TestHas(t *testing.T) {
 		t.Error("Has(other) should be false")
 	}
 }
+
+func TestGetBool(t *testing.T) {
+	args := map[string]any{"on": true, "bad": "true"}
+	if !helpers.GetBool(args, "on") {
+		t.Error("GetBool(on) should be true")
+	}
+	if helpers.GetBool(args, "bad") || helpers.GetBool(args, "missing") {
+		t.Error("GetBool should be false for non-bool or missing values")
+	}
+}
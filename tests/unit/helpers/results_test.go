@@ -41,3 +41,28 @@ func TestErrorResult(t *testing.T) {
 		t.Error("IsError should be true")
 	}
 }
+
+func TestStructuredResult(t *testing.T) {
+	v := map[string]any{"id": "T-1"}
+	r := helpers.StructuredResult(v)
+	if r.Content[0].Text == "" {
+		t.Error("text block should carry the JSON")
+	}
+	if got, ok := r.StructuredContent.(map[string]any); !ok || got["id"] != "T-1" {
+		t.Errorf("StructuredContent = %#v", r.StructuredContent)
+	}
+}
+
+func TestListResultWrapsArray(t *testing.T) {
+	r := helpers.ListResult[string]("items", nil)
+	if r.Content[0].Text != "null" {
+		t.Errorf("text = %q, want unchanged null", r.Content[0].Text)
+	}
+	sc, ok := r.StructuredContent.(map[string]any)
+	if !ok {
+		t.Fatalf("StructuredContent = %#v", r.StructuredContent)
+	}
+	if items, ok := sc["items"].([]string); !ok || items == nil {
+		t.Errorf("items = %#v, want empty slice", sc["items"])
+	}
+}
@@ -0,0 +1,49 @@
+package helpers_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/orchestra-mcp/mcp/src/helpers"
+)
+
+type schemaSample struct {
+	Name   string            `json:"name"`
+	Count  int               `json:"count,omitempty"`
+	Tags   []string          `json:"tags"`
+	Labels map[string]string `json:"labels,omitempty"`
+	Skip   string            `json:"-"`
+	hidden string
+}
+
+func TestSchemaOf(t *testing.T) {
+	s := helpers.SchemaOf(schemaSample{hidden: "x"})
+	if s["type"] != "object" {
+		t.Fatalf("type = %v", s["type"])
+	}
+	props := s["properties"].(map[string]any)
+	if len(props) != 4 {
+		t.Errorf("properties = %v, want 4", props)
+	}
+	if props["count"].(map[string]any)["type"] != "integer" {
+		t.Errorf("count = %v", props["count"])
+	}
+	tags := props["tags"].(map[string]any)
+	if !reflect.DeepEqual(tags["type"], []string{"array", "null"}) {
+		t.Errorf("required slice should be nullable, got %v", tags["type"])
+	}
+	if !reflect.DeepEqual(s["required"], []string{"name", "tags"}) {
+		t.Errorf("required = %v", s["required"])
+	}
+}
+
+func TestListOutputSchema(t *testing.T) {
+	o := helpers.ListOutputSchema("items", schemaSample{})
+	if o.Type != "object" || !reflect.DeepEqual(o.Required, []string{"items"}) {
+		t.Fatalf("schema = %+v", o)
+	}
+	arr := o.Properties["items"].(map[string]any)
+	if arr["type"] != "array" || arr["items"].(map[string]any)["type"] != "object" {
+		t.Errorf("items = %v", arr)
+	}
+}
@@ -0,0 +1,60 @@
+package helpers_test
+
+import (
+	"testing"
+
+	"github.com/orchestra-mcp/mcp/src/helpers"
+)
+
+func TestMatchURITemplate(t *testing.T) {
+	tpl := "toon://project/{slug}/task/{epicId}/{storyId}/{taskId}"
+	vars, ok := helpers.MatchURITemplate(tpl, "toon://project/app/task/EPIC-1/STORY-2/TASK-3")
+	if !ok {
+		t.Fatal("expected match")
+	}
+	if vars["slug"] != "app" || vars["epicId"] != "EPIC-1" || vars["taskId"] != "TASK-3" {
+		t.Errorf("vars = %v", vars)
+	}
+
+	for _, uri := range []string{
+		"toon://project/app/task/EPIC-1/STORY-2",
+		"toon://project/app/task/EPIC-1/STORY-2/TASK-3/extra",
+		"toon://project//task/EPIC-1/STORY-2/TASK-3",
+		"file://project/app/task/EPIC-1/STORY-2/TASK-3",
+	} {
+		if _, ok := helpers.MatchURITemplate(tpl, uri); ok {
+			t.Errorf("%q should not match", uri)
+		}
+	}
+}
+
+func TestMatchURITemplateRejectsDotSegments(t *testing.T) {
+	tpl := "toon://project/{slug}/status"
+	for _, uri := range []string{"toon://project/../status", "toon://project/./status"} {
+		if vars, ok := helpers.MatchURITemplate(tpl, uri); ok {
+			t.Errorf("%q matched with %v", uri, vars)
+		}
+	}
+	if _, ok := helpers.MatchURITemplate(tpl, "toon://project/..app/status"); !ok {
+		t.Error("dots inside a value should match")
+	}
+}
+
+func TestMatchURITemplateLiteral(t *testing.T) {
+	if _, ok := helpers.MatchURITemplate("docs://readme.md", "docs://readme.md"); !ok {
+		t.Error("literal URI should match itself")
+	}
+	if _, ok := helpers.MatchURITemplate("docs://readme.md", "docs://readmeXmd"); ok {
+		t.Error("dots must be matched literally")
+	}
+}
+
+func TestExpandURITemplate(t *testing.T) {
+	got := helpers.ExpandURITemplate("toon://project/{slug}/status", map[string]string{"slug": "app"})
+	if got != "toon://project/app/status" {
+		t.Errorf("got %q", got)
+	}
+	if !helpers.IsURITemplate("toon://project/{slug}/prd") || helpers.IsURITemplate(got) {
+		t.Error("IsURITemplate mismatch")
+	}
+}
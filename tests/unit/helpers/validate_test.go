@@ -0,0 +1,103 @@
+package helpers_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/orchestra-mcp/mcp/src/helpers"
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+var taskSchema = types.InputSchema{Type: "object", Properties: map[string]any{
+	"title":    map[string]any{"type": "string", "minLength": 1},
+	"type":     map[string]any{"type": "string", "enum": []string{"task", "bug", "hotfix"}},
+	"estimate": map[string]any{"type": "integer", "minimum": 1, "maximum": 13},
+	"tags":     map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "uniqueItems": true},
+	"events": map[string]any{"type": "array", "items": map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"type": map[string]any{"type": "string"}},
+		"required":   []string{"type"},
+	}},
+}, Required: []string{"title"}}
+
+func TestValidateInput(t *testing.T) {
+	cases := []struct {
+		name string
+		args map[string]any
+		want string // "" means valid
+	}{
+		{"valid", map[string]any{"title": "x", "type": "bug", "estimate": 3.0, "tags": []any{"a", "b"}}, ""},
+		{"missing required", map[string]any{}, "title: is required"},
+		{"nil args", nil, "title: is required"},
+		{"wrong type", map[string]any{"title": 5.0}, "title: must be string"},
+		{"enum", map[string]any{"title": "x", "type": "foo"}, "type: must be one of task, bug, hotfix"},
+		{"integer", map[string]any{"title": "x", "estimate": 2.5}, "estimate: must be integer"},
+		{"minimum", map[string]any{"title": "x", "estimate": 0.0}, "estimate: must be >= 1"},
+		{"maximum", map[string]any{"title": "x", "estimate": 21.0}, "estimate: must be <= 13"},
+		{"minLength", map[string]any{"title": ""}, "title: must be at least 1 characters"},
+		{"items", map[string]any{"title": "x", "tags": []any{"a", 1.0}}, "tags[1]: must be string"},
+		{"uniqueItems", map[string]any{"title": "x", "tags": []any{"a", "a"}}, "tags: items 0 and 1 are equal"},
+		{"nested path", map[string]any{"title": "x", "events": []any{
+			map[string]any{"type": "a"}, map[string]any{"type": "b"}, map[string]any{"type": 3.0},
+		}}, "events[2].type: must be string"},
+		{"nested required", map[string]any{"title": "x", "events": []any{map[string]any{}}}, "events[0].type: is required"},
+		{"unknown property", map[string]any{"title": "x", "colour": "red"}, "colour: unknown property"},
+	}
+	for _, c := range cases {
+		err := helpers.ValidateInput(c.args, taskSchema)
+		switch {
+		case c.want == "" && err != nil:
+			t.Errorf("%s: unexpected error %v", c.name, err)
+		case c.want != "" && (err == nil || err.Error() != c.want):
+			t.Errorf("%s: error = %v, want %q", c.name, err, c.want)
+		}
+	}
+}
+
+func TestValidateInputAllowsExtraWhenDeclared(t *testing.T) {
+	open := true
+	s := types.InputSchema{Type: "object", AdditionalProperties: &open}
+	if err := helpers.ValidateInput(map[string]any{"anything": 1.0}, s); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestValidateInputReportsEveryError(t *testing.T) {
+	err := helpers.ValidateInput(map[string]any{"type": "foo", "estimate": "3"}, taskSchema)
+	var verrs helpers.ValidationErrors
+	if !errors.As(err, &verrs) || len(verrs) != 3 {
+		t.Fatalf("errors = %v, want 3", err)
+	}
+	if !strings.Contains(err.Error(), "; ") {
+		t.Errorf("errors not joined: %q", err)
+	}
+}
+
+func TestValidateSchemaCombinators(t *testing.T) {
+	cases := []struct {
+		name   string
+		schema map[string]any
+		value  any
+		ok     bool
+	}{
+		{"type list", map[string]any{"type": []any{"string", "null"}}, nil, true},
+		{"type list mismatch", map[string]any{"type": []string{"string", "null"}}, 1.0, false},
+		{"const", map[string]any{"const": "a"}, "b", false},
+		{"pattern", map[string]any{"type": "string", "pattern": "^[a-z-]+$"}, "my-app", true},
+		{"pattern mismatch", map[string]any{"type": "string", "pattern": "^[a-z-]+$"}, "My App", false},
+		{"exclusiveMinimum", map[string]any{"exclusiveMinimum": 0}, 0.0, false},
+		{"multipleOf", map[string]any{"multipleOf": 0.5}, 1.5, true},
+		{"anyOf", map[string]any{"anyOf": []any{map[string]any{"type": "string"}, map[string]any{"type": "number"}}}, true, false},
+		{"oneOf", map[string]any{"oneOf": []any{map[string]any{"type": "number"}, map[string]any{"type": "integer"}}}, 2.0, false},
+		{"not", map[string]any{"not": map[string]any{"type": "string"}}, 1.0, true},
+		{"prefixItems", map[string]any{"prefixItems": []any{map[string]any{"type": "string"}}, "items": map[string]any{"type": "number"}}, []any{"a", 1.0, 2.0}, true},
+		{"maxItems", map[string]any{"maxItems": 1}, []any{"a", "b"}, false},
+		{"additionalProperties schema", map[string]any{"additionalProperties": map[string]any{"type": "string"}}, map[string]any{"a": 1.0}, false},
+	}
+	for _, c := range cases {
+		if err := helpers.ValidateSchema(c.schema, c.value); (err == nil) != c.ok {
+			t.Errorf("%s: err = %v, want ok=%v", c.name, err, c.ok)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package hooks_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/orchestra-mcp/mcp/src/hooks"
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+func TestEventFromPayload(t *testing.T) {
+	e := hooks.EventFromPayload(map[string]any{"hook_event_name": "PreToolUse", "session_id": "s1", "tool_name": "Bash"})
+	if e.EventType != "PreToolUse" || e.SessionID != "s1" || e.ToolName != "Bash" || e.Data["tool_name"] != "Bash" {
+		t.Errorf("event = %+v", e)
+	}
+	if e := hooks.EventFromPayload(map[string]any{}); e.EventType != "unknown" {
+		t.Errorf("event type = %q, want unknown", e.EventType)
+	}
+}
+
+func TestAppendKeepsLastEvents(t *testing.T) {
+	ws := t.TempDir()
+	for i := 0; i < hooks.MaxEvents+5; i++ {
+		if err := hooks.Append(ws, types.HookEvent{EventType: fmt.Sprint(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	events := hooks.Read(ws)
+	if len(events) != hooks.MaxEvents || events[0].EventType != "5" {
+		t.Errorf("got %d events starting at %q", len(events), events[0].EventType)
+	}
+}
+
+func TestConcurrentIngestLosesNothing(t *testing.T) {
+	ws := t.TempDir()
+	var wg sync.WaitGroup
+	for i := 0; i < 30; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, err := hooks.Ingest(ws, strings.NewReader(fmt.Sprintf(`{"hook_event_name":"E%d"}`, i))); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if n := len(hooks.Read(ws)); n != 30 {
+		t.Errorf("stored %d events, want 30", n)
+	}
+}
+
+func TestIngestThroughDaemon(t *testing.T) {
+	ws := t.TempDir()
+	d, err := hooks.Listen(ws)
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	t.Cleanup(func() { d.Close() })
+	go d.Serve(context.Background())
+
+	if _, err := hooks.Listen(ws); err != hooks.ErrDaemonRunning {
+		t.Errorf("second Listen = %v, want ErrDaemonRunning", err)
+	}
+	via, err := hooks.Ingest(ws, strings.NewReader("{\n  \"hook_event_name\": \"Stop\",\n  \"session_id\": \"s\"\n}"))
+	if err != nil || via != "daemon" {
+		t.Fatalf("Ingest = %q, %v; want daemon", via, err)
+	}
+	if events := hooks.Read(ws); len(events) != 1 || events[0].EventType != "Stop" {
+		t.Errorf("events = %+v", events)
+	}
+	if err := hooks.Forward(ws, []byte("not json")); err == nil || !strings.Contains(err.Error(), "daemon: error") {
+		t.Errorf("Forward(bad) = %v, want daemon error", err)
+	}
+}
+
+func TestSocketIsPrivate(t *testing.T) {
+	for _, runtime := range []string{"", t.TempDir()} {
+		t.Setenv("XDG_RUNTIME_DIR", runtime)
+		ws := t.TempDir()
+		path := hooks.SocketPath(ws)
+		if runtime != "" && !strings.HasPrefix(path, runtime) {
+			t.Errorf("socket %s not under XDG_RUNTIME_DIR", path)
+		}
+		d, err := hooks.Listen(ws)
+		if err != nil {
+			t.Fatalf("Listen: %v", err)
+		}
+		for p, want := range map[string]os.FileMode{path: 0o600, filepath.Dir(path): 0o700} {
+			info, err := os.Stat(p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Mode().Perm() != want {
+				t.Errorf("%s mode = %v, want %v", p, info.Mode().Perm(), want)
+			}
+		}
+		d.Close()
+	}
+}
+
+func TestIngestLateAckDoesNotDuplicate(t *testing.T) {
+	ws := t.TempDir()
+	d, err := hooks.Listen(ws)
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	d.Close()
+	// A daemon that stores the event but replies after the deadline.
+	ln, err := net.Listen("unix", hooks.SocketPath(ws))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, _ = bufio.NewReader(conn).ReadString('\n')
+		_ = hooks.Append(ws, types.HookEvent{EventType: "Stop"})
+		time.Sleep(2 * hooks.ForwardTimeout)
+	}()
+
+	via, err := hooks.Ingest(ws, strings.NewReader(`{"hook_event_name":"Stop"}`))
+	if err == nil || via != "daemon" {
+		t.Fatalf("Ingest = %q, %v; want daemon error", via, err)
+	}
+	if events := hooks.Read(ws); len(events) != 1 {
+		t.Errorf("events = %d, want 1", len(events))
+	}
+}
+
+func TestIngestFallsBackWithoutDaemon(t *testing.T) {
+	ws := t.TempDir()
+	via, err := hooks.Ingest(ws, strings.NewReader(`{"hook_event_name":"Stop"}`))
+	if err != nil || via != "direct" {
+		t.Fatalf("Ingest = %q, %v; want direct", via, err)
+	}
+	if len(hooks.Read(ws)) != 1 {
+		t.Error("event not written")
+	}
+	if _, err := hooks.Ingest(ws, strings.NewReader("nope")); err == nil {
+		t.Error("expected error for invalid payload")
+	}
+}
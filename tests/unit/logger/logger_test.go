@@ -0,0 +1,85 @@
+package logger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/orchestra-mcp/mcp/src/logger"
+)
+
+func TestParseLevel(t *testing.T) {
+	for i, name := range []string{"debug", "info", "notice", "warning", "error", "critical", "alert", "emergency"} {
+		l, err := logger.ParseLevel(name)
+		if err != nil || int(l) != i || l.String() != name {
+			t.Errorf("ParseLevel(%q) = %v, %v", name, l, err)
+		}
+	}
+	if _, err := logger.ParseLevel("verbose"); err == nil {
+		t.Error("unknown level should fail")
+	}
+}
+
+func TestLogFieldsAndSinks(t *testing.T) {
+	l := logger.New()
+	var got []logger.Entry
+	remove := l.AddSink(logger.SinkFunc(func(e logger.Entry) { got = append(got, e) }))
+	l.Log(logger.LevelWarning, "memory", "fallback", "tool", "save_memory", "error", errors.New("boom"))
+	remove()
+	l.Log(logger.LevelError, "memory", "dropped")
+
+	if len(got) != 1 {
+		t.Fatalf("entries = %d, want 1", len(got))
+	}
+	data := got[0].Data()
+	if data["message"] != "fallback" || data["tool"] != "save_memory" || data["error"] != "boom" {
+		t.Errorf("data = %v", data)
+	}
+}
+
+func TestWriterSinkFiltersLevel(t *testing.T) {
+	var buf bytes.Buffer
+	l := logger.New()
+	l.AddSink(logger.NewWriterSink(&buf, logger.LevelInfo))
+	l.Log(logger.LevelDebug, "engine", "hidden")
+	l.Log(logger.LevelInfo, "engine", "running", "addr", "127.0.0.1:50051")
+	if got := buf.String(); got != "[Orchestra MCP] info engine: running addr=127.0.0.1:50051\n" {
+		t.Errorf("output = %q", got)
+	}
+}
+
+func TestFileSinkWritesJSONAndRotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".logs", "mcp.log")
+	fs, err := logger.NewFileSink(path, logger.LevelDebug, 200, 2)
+	if err != nil {
+		t.Fatalf("NewFileSink: %v", err)
+	}
+	l := logger.New()
+	l.AddSink(fs)
+	for i := 0; i < 10; i++ {
+		l.Log(logger.LevelInfo, "test", "entry", "i", i)
+	}
+	fs.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	line := strings.SplitN(strings.TrimSpace(string(data)), "\n", 2)[0]
+	var e map[string]any
+	if err := json.Unmarshal([]byte(line), &e); err != nil || e["level"] != "info" || e["message"] != "entry" {
+		t.Errorf("line = %s (%v)", line, err)
+	}
+	for _, name := range []string{"mcp.log.1", "mcp.log.2"} {
+		if _, err := os.Stat(filepath.Join(filepath.Dir(path), name)); err != nil {
+			t.Errorf("%s missing: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); err == nil {
+		t.Error("only 2 backups should be kept")
+	}
+}
@@ -0,0 +1,93 @@
+package metrics_test
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/orchestra-mcp/mcp/src/metrics"
+)
+
+func exposition(t *testing.T, r *metrics.Registry) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if _, err := r.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+	return buf.String()
+}
+
+func TestCounterExposition(t *testing.T) {
+	r := metrics.NewRegistry()
+	c := r.NewCounter("calls", "Calls by tool.", "tool")
+	c.Inc("b")
+	c.Add(2, "a")
+	c.Inc(`q"x`)
+	want := "# TYPE calls counter\n# HELP calls Calls by tool.\n" +
+		"calls_total{tool=\"a\"} 2\ncalls_total{tool=\"b\"} 1\ncalls_total{tool=\"q\\\"x\"} 1\n# EOF\n"
+	if got := exposition(t, r); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+	if c.Value("a") != 2 || c.Value("missing") != 0 {
+		t.Errorf("values = %v, %v", c.Value("a"), c.Value("missing"))
+	}
+}
+
+func TestGaugeFunc(t *testing.T) {
+	r := metrics.NewRegistry()
+	g := r.NewGauge("sessions", "Open sessions.", "transport")
+	n := 3
+	g.SetFunc(func() float64 { return float64(n) }, "sse")
+	g.Set(1, "http")
+	n = 5
+	out := exposition(t, r)
+	if !strings.Contains(out, "sessions{transport=\"sse\"} 5\n") || !strings.Contains(out, "sessions{transport=\"http\"} 1\n") {
+		t.Errorf("exposition = %s", out)
+	}
+}
+
+func TestHistogramBuckets(t *testing.T) {
+	r := metrics.NewRegistry()
+	hg := r.NewHistogram("latency_seconds", "Latency.", []float64{1, 0.1})
+	for _, v := range []float64{0.05, 0.5, 3} {
+		hg.Observe(v)
+	}
+	want := "# TYPE latency_seconds histogram\n# HELP latency_seconds Latency.\n" +
+		"latency_seconds_bucket{le=\"0.1\"} 1\nlatency_seconds_bucket{le=\"1\"} 2\nlatency_seconds_bucket{le=\"+Inf\"} 3\n" +
+		"latency_seconds_count 3\nlatency_seconds_sum 3.55\n# EOF\n"
+	if got := exposition(t, r); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+	if hg.Count() != 3 {
+		t.Errorf("count = %d", hg.Count())
+	}
+}
+
+func TestDuplicateMetricPanics(t *testing.T) {
+	r := metrics.NewRegistry()
+	r.NewCounter("x", "")
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic")
+		}
+	}()
+	r.NewGauge("x", "")
+}
+
+func TestHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	metrics.Default.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); ct != metrics.ContentType {
+		t.Errorf("content type = %q", ct)
+	}
+	body := rec.Body.String()
+	for _, name := range []string{"orchestra_mcp_tool_calls", "orchestra_mcp_bridge_fallbacks", "orchestra_mcp_engine_up"} {
+		if !strings.Contains(body, "# TYPE "+name+" ") {
+			t.Errorf("missing %s in\n%s", name, body)
+		}
+	}
+	if !strings.HasSuffix(body, "# EOF\n") {
+		t.Error("missing # EOF")
+	}
+}
@@ -1,10 +1,20 @@
 package providers_test
 
 import (
+	"context"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/gofiber/fiber/v3"
 	"github.com/orchestra-mcp/framework/app/plugins"
 	"github.com/orchestra-mcp/mcp/providers"
+	"github.com/orchestra-mcp/mcp/src/transport"
+	"github.com/orchestra-mcp/mcp/src/types"
 	"github.com/orchestra-mcp/mcp/src/version"
 	"github.com/rs/zerolog"
 )
@@ -65,7 +75,250 @@ func TestMcpToolsCount(t *testing.T) {
 	}
 	p.Activate(ctx)
 	tools := p.McpTools()
-	if len(tools) != 42 {
-		t.Errorf("McpTools count = %d, want 42", len(tools))
+	if len(tools) != 43 {
+		t.Errorf("McpTools count = %d, want 43", len(tools))
+	}
+}
+
+func TestMcpToolAnnotations(t *testing.T) {
+	p := providers.NewMcpPlugin()
+	p.Activate(&plugins.PluginContext{PluginID: "orchestra/mcp", Config: map[string]any{}, Logger: zerolog.Nop()})
+	ann := p.McpToolAnnotations()
+	if len(ann) != len(p.McpTools()) {
+		t.Errorf("annotations for %d tools, want %d", len(ann), len(p.McpTools()))
+	}
+	if a := ann["delete_epic"]; a == nil || a.DestructiveHint == nil || !*a.DestructiveHint {
+		t.Errorf("delete_epic annotations = %+v", a)
+	}
+	if a := ann["list_tasks"]; a == nil || a.ReadOnlyHint == nil || !*a.ReadOnlyHint {
+		t.Errorf("list_tasks annotations = %+v", a)
+	}
+}
+
+func TestMcpToolsCallThroughServer(t *testing.T) {
+	ws := t.TempDir()
+	os.MkdirAll(filepath.Join(ws, ".projects"), 0o755)
+	p := providers.NewMcpPlugin()
+	p.Activate(&plugins.PluginContext{PluginID: "orchestra/mcp", Config: map[string]any{"workspace": ws}, Logger: zerolog.Nop()})
+	handlers := map[string]func(map[string]any) (any, error){}
+	for _, tool := range p.McpTools() {
+		handlers[tool.Name] = tool.Handler
+	}
+
+	if _, err := handlers["create_project"](map[string]any{"name": 42}); err == nil || transport.ErrorCode(err) != -32602 {
+		t.Errorf("invalid arguments err = %v, want a validation error", err)
+	}
+	res, err := handlers["list_projects"](map[string]any{})
+	if r, ok := res.(*types.ToolResult); err != nil || !ok || r.IsError {
+		t.Fatalf("list_projects = %+v, %v", res, err)
+	}
+	entries, _ := os.ReadDir(filepath.Join(ws, ".projects", ".audit"))
+	if len(entries) != 1 {
+		t.Errorf("calls not audited: %v", entries)
+	}
+}
+
+func TestRESTToolCallValidatesArguments(t *testing.T) {
+	p := providers.NewMcpPlugin()
+	p.Activate(&plugins.PluginContext{PluginID: "orchestra/mcp", Config: map[string]any{"workspace": t.TempDir()}, Logger: zerolog.Nop()})
+	app := fiber.New()
+	p.RegisterRoutes(app.Group("/api"))
+
+	body := `{"name":"create_task","arguments":{"project":"p","epic_id":"E1","story_id":"S1","title":"x","type":"foo"}}`
+	req := httptest.NewRequest("POST", "/api/mcp/tools/call", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 400 || !strings.Contains(string(data), "type: must be one of") {
+		t.Errorf("status %d body %s, want 400 enum error", resp.StatusCode, data)
+	}
+}
+
+// authApp serves the plugin's routes with a read-only and an admin token.
+func authApp(t *testing.T) *fiber.App {
+	t.Helper()
+	p := providers.NewMcpPlugin()
+	err := p.Activate(&plugins.PluginContext{PluginID: "orchestra/mcp", Logger: zerolog.Nop(), Config: map[string]any{
+		"workspace": t.TempDir(),
+		"auth": map[string]any{"tokens": []any{
+			map[string]any{"name": "reader", "token": "r", "scopes": []any{"read"}},
+			map[string]any{"name": "ops", "token": "a", "scopes": []any{"admin"}},
+		}},
+	}})
+	if err != nil {
+		t.Fatalf("Activate: %v", err)
+	}
+	app := fiber.New()
+	p.RegisterRoutes(app.Group("/api"))
+	return app
+}
+
+func callTool(t *testing.T, app *fiber.App, header, value, body string) (int, string) {
+	t.Helper()
+	req := httptest.NewRequest("POST", "/api/mcp/tools/call", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if header != "" {
+		req.Header.Set(header, value)
+	}
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(data)
+}
+
+func TestRESTAuthScopes(t *testing.T) {
+	app := authApp(t)
+	list := `{"name":"list_projects","arguments":{}}`
+	del := `{"name":"delete_epic","arguments":{"project":"x","epic_id":"E1"}}`
+
+	if status, body := callTool(t, app, "", "", list); status != 401 || !strings.Contains(body, "-32001") {
+		t.Errorf("anonymous: %d %s, want 401", status, body)
+	}
+	if status, _ := callTool(t, app, "Authorization", "Bearer wrong", list); status != 401 {
+		t.Errorf("bad token: %d, want 401", status)
+	}
+	if status, body := callTool(t, app, "Authorization", "Bearer r", list); status != 200 {
+		t.Errorf("reader list: %d %s, want 200", status, body)
+	}
+	if status, body := callTool(t, app, "Authorization", "Bearer r", del); status != 403 || !strings.Contains(body, "-32003") {
+		t.Errorf("reader delete: %d %s, want 403", status, body)
+	}
+	if status, body := callTool(t, app, "X-API-Key", "a", del); status == 401 || status == 403 {
+		t.Errorf("admin delete: %d %s, want authorized", status, body)
+	}
+}
+
+func TestPluginToolsets(t *testing.T) {
+	ws := t.TempDir()
+	os.MkdirAll(filepath.Join(ws, ".orchestra"), 0o755)
+	os.WriteFile(filepath.Join(ws, ".orchestra", "config.yaml"), []byte("tools:\n  disabled: [get_next_task]\n"), 0o644)
+	p := providers.NewMcpPlugin()
+	err := p.Activate(&plugins.PluginContext{PluginID: "orchestra/mcp", Logger: zerolog.Nop(), Config: map[string]any{
+		"workspace": ws, "toolsets": []any{"workflow"},
+	}})
+	if err != nil {
+		t.Fatalf("Activate: %v", err)
+	}
+	names := map[string]bool{}
+	for _, tool := range p.McpTools() {
+		names[tool.Name] = true
+	}
+	if !names["get_workflow_status"] || names["get_next_task"] || names["create_project"] {
+		t.Errorf("tools = %v", names)
+	}
+
+	bad := providers.NewMcpPlugin()
+	if err := bad.Activate(&plugins.PluginContext{PluginID: "orchestra/mcp", Logger: zerolog.Nop(), Config: map[string]any{
+		"workspace": ws, "profile": "nobody",
+	}}); err == nil {
+		t.Error("unknown profile accepted")
+	}
+}
+
+func TestActivateRejectsBadAuthConfig(t *testing.T) {
+	p := providers.NewMcpPlugin()
+	err := p.Activate(&plugins.PluginContext{PluginID: "orchestra/mcp", Logger: zerolog.Nop(), Config: map[string]any{
+		"auth": map[string]any{"tokens": []any{map[string]any{"name": "x", "token": "t", "scopes": []any{"root"}}}},
+	}})
+	if err == nil || p.IsActive() {
+		t.Errorf("Activate = %v, active %v; want error", err, p.IsActive())
+	}
+}
+
+func TestSSEResumeUnknownSession(t *testing.T) {
+	p := providers.NewMcpPlugin()
+	p.Activate(&plugins.PluginContext{PluginID: "orchestra/mcp", Config: map[string]any{"workspace": t.TempDir()}, Logger: zerolog.Nop()})
+	app := fiber.New()
+	p.RegisterRoutes(app.Group("/api"))
+
+	req := httptest.NewRequest("GET", "/api/mcp/sse?sessionId=missing", nil)
+	req.Header.Set("Last-Event-ID", "4")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 404 {
+		t.Errorf("status = %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestRESTToolCallRunsMiddleware(t *testing.T) {
+	p := providers.NewMcpPlugin()
+	p.Activate(&plugins.PluginContext{PluginID: "orchestra/mcp", Config: map[string]any{"workspace": t.TempDir()}, Logger: zerolog.Nop()})
+	p.UseToolMiddleware(func(next transport.ToolHandler) transport.ToolHandler {
+		return func(ctx context.Context, call *transport.ToolCall) (*types.ToolResult, error) {
+			return nil, fmt.Errorf("policy blocks %s", call.Name)
+		}
+	})
+	app := fiber.New()
+	p.RegisterRoutes(app.Group("/api"))
+
+	req := httptest.NewRequest("POST", "/api/mcp/tools/call", strings.NewReader(`{"name":"list_projects","arguments":{}}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 500 || !strings.Contains(string(data), "policy blocks list_projects") {
+		t.Errorf("status %d body %s, want 500 policy error", resp.StatusCode, data)
+	}
+}
+
+func TestMetricsRoute(t *testing.T) {
+	p := providers.NewMcpPlugin()
+	p.Activate(&plugins.PluginContext{PluginID: "orchestra/mcp", Config: map[string]any{"workspace": t.TempDir()}, Logger: zerolog.Nop()})
+	app := fiber.New()
+	p.RegisterRoutes(app.Group("/api"))
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/mcp/metrics", nil))
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 || !strings.HasPrefix(resp.Header.Get("Content-Type"), "application/openmetrics-text") {
+		t.Fatalf("status %d, content type %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	if !strings.Contains(string(data), `orchestra_mcp_sse_sessions{transport="sse"} 0`) {
+		t.Errorf("body = %s", data)
+	}
+}
+
+func TestMcpToolsTrustedWithAuth(t *testing.T) {
+	ws := t.TempDir()
+	os.MkdirAll(filepath.Join(ws, ".projects"), 0o755)
+	p := providers.NewMcpPlugin()
+	err := p.Activate(&plugins.PluginContext{PluginID: "orchestra/mcp", Logger: zerolog.Nop(), Config: map[string]any{
+		"workspace": ws,
+		"auth": map[string]any{"tokens": []any{
+			map[string]any{"name": "reader", "token": "r", "scopes": []any{"read"}},
+		}},
+	}})
+	if err != nil {
+		t.Fatalf("Activate: %v", err)
+	}
+	handlers := map[string]func(map[string]any) (any, error){}
+	for _, tool := range p.McpTools() {
+		handlers[tool.Name] = tool.Handler
+	}
+
+	res, err := handlers["list_projects"](map[string]any{})
+	if r, ok := res.(*types.ToolResult); err != nil || !ok || r.IsError {
+		t.Errorf("list_projects = %+v, %v", res, err)
+	}
+	// delete_epic needs the admin scope; an unknown epic is a tool error,
+	// not an auth error.
+	if _, err := handlers["delete_epic"](map[string]any{"project": "x", "epic_id": "E1"}); err != nil {
+		t.Errorf("delete_epic refused: %v", err)
 	}
 }
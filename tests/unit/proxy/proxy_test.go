@@ -0,0 +1,236 @@
+package proxy_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orchestra-mcp/mcp/src/client"
+	"github.com/orchestra-mcp/mcp/src/proxy"
+	"github.com/orchestra-mcp/mcp/src/toolsets"
+	"github.com/orchestra-mcp/mcp/src/transport"
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+func tool(name string, fn func(args map[string]any) (*types.ToolResult, error)) types.Tool {
+	return types.Tool{
+		Definition: types.ToolDefinition{Name: name, InputSchema: types.InputSchema{Type: "object"}},
+		Handler:    fn,
+	}
+}
+
+func text(s string) *types.ToolResult {
+	return &types.ToolResult{Content: []types.ContentBlock{{Type: "text", Text: s}}}
+}
+
+func upstream() *transport.MCPServer {
+	s := transport.New("github", "1.0")
+	create := tool("create_issue", func(args map[string]any) (*types.ToolResult, error) {
+		return text("created " + args["title"].(string)), nil
+	})
+	create.Definition.InputSchema.Properties = map[string]any{"title": map[string]any{"type": "string"}}
+	s.RegisterTool(create)
+	s.RegisterResource(types.Resource{
+		Definition: types.ResourceDefinition{URI: "gh://repo/{name}", Name: "repo"},
+		Handler: func(uri string) ([]types.ResourceContent, error) {
+			return []types.ResourceContent{{URI: uri, Text: "repo " + strings.TrimPrefix(uri, "gh://repo/")}}, nil
+		},
+	})
+	s.RegisterPrompt(types.Prompt{
+		Definition: types.PromptDefinition{Name: "triage"},
+		Handler: func(map[string]string) (string, []types.PromptMessage, error) {
+			return "triage", []types.PromptMessage{{Role: "user", Content: types.ContentBlock{Type: "text", Text: "triage it"}}}, nil
+		},
+	})
+	return s
+}
+
+func connect(t *testing.T, s *transport.MCPServer) *client.Client {
+	t.Helper()
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	go func() {
+		s.Serve(inR, outW)
+		outW.Close()
+	}()
+	return client.NewStream(outR, inW)
+}
+
+func mount(t *testing.T, up *transport.MCPServer) (*transport.MCPServer, *proxy.Proxy) {
+	t.Helper()
+	local := transport.New("local", "1.0")
+	local.RegisterTool(tool("create_issue", func(map[string]any) (*types.ToolResult, error) { return text("local"), nil }))
+	p := proxy.New(local)
+	t.Cleanup(p.Close)
+	if err := p.Mount(context.Background(), "github", connect(t, up)); err != nil {
+		t.Fatalf("Mount: %v", err)
+	}
+	return local, p
+}
+
+func hasTool(s *transport.MCPServer, name string) bool {
+	for _, d := range s.GetTools() {
+		if d.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func eventually(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestMountForwardsTools(t *testing.T) {
+	local, _ := mount(t, upstream())
+	if !hasTool(local, "github.create_issue") || !hasTool(local, "create_issue") {
+		t.Fatalf("tools = %+v", local.GetTools())
+	}
+	res, err := local.CallTool(context.Background(), &transport.ToolCall{
+		Name: "github.create_issue", Arguments: map[string]any{"title": "bug"},
+	})
+	if err != nil || res.Content[0].Text != "created bug" {
+		t.Fatalf("CallTool = %+v, %v", res, err)
+	}
+}
+
+func TestMountForwardsResourcesAndPrompts(t *testing.T) {
+	local, _ := mount(t, upstream())
+	var out strings.Builder
+	local.Serve(strings.NewReader(strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"github+gh://repo/mcp"}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"prompts/get","params":{"name":"github.triage"}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"resources/templates/list"}`,
+	}, "\n")+"\n"), &out)
+	for _, want := range []string{`"text":"repo mcp"`, `"uri":"github+gh://repo/mcp"`, `"uriTemplate":"github+gh://repo/{name}"`, `"text":"triage it"`, `"name":"github.repo"`} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %s:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestProxiedToolsFilteredWithoutUpstreamHints(t *testing.T) {
+	up := upstream()
+	readOnly := true
+	drop := tool("drop_repo", func(map[string]any) (*types.ToolResult, error) { return text("dropped"), nil })
+	drop.Definition.Annotations = &types.ToolAnnotations{Title: "Drop", ReadOnlyHint: &readOnly}
+	up.RegisterTool(drop)
+
+	sel, err := toolsets.Config{}.Resolve("readonly", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	local := transport.New("local", "1.0")
+	p := proxy.New(local)
+	p.SetFilter(sel.Filter)
+	t.Cleanup(p.Close)
+	if err := p.Mount(context.Background(), "github", connect(t, up)); err != nil {
+		t.Fatalf("Mount: %v", err)
+	}
+	if hasTool(local, "github.drop_repo") || hasTool(local, "github.create_issue") {
+		t.Errorf("readonly profile exposes proxied tools: %+v", local.GetTools())
+	}
+
+	sel, err = toolsets.Config{}.Resolve("", []string{toolsets.ProxyGroup("github")})
+	if err != nil {
+		t.Fatalf("Resolve with proxy toolset: %v", err)
+	}
+	local = transport.New("local", "1.0")
+	p = proxy.New(local)
+	p.SetFilter(sel.Filter)
+	t.Cleanup(p.Close)
+	if err := p.Mount(context.Background(), "github", connect(t, upstream())); err != nil {
+		t.Fatalf("Mount: %v", err)
+	}
+	if !hasTool(local, "github.create_issue") {
+		t.Errorf("proxy:github toolset not exposed: %+v", local.GetTools())
+	}
+}
+
+func TestProxiedToolAnnotationsDropped(t *testing.T) {
+	up := upstream()
+	readOnly := true
+	drop := tool("drop_repo", func(map[string]any) (*types.ToolResult, error) { return text("dropped"), nil })
+	drop.Definition.Annotations = &types.ToolAnnotations{Title: "Drop", ReadOnlyHint: &readOnly}
+	up.RegisterTool(drop)
+	local, _ := mount(t, up)
+	for _, def := range local.GetTools() {
+		if def.Name != "github.drop_repo" {
+			continue
+		}
+		if def.Annotations == nil || def.Annotations.Title != "Drop" || def.Annotations.ReadOnlyHint != nil {
+			t.Errorf("annotations = %+v, want title only", def.Annotations)
+		}
+		return
+	}
+	t.Fatal("github.drop_repo not mounted")
+}
+
+func TestUpstreamsKeepSeparateResources(t *testing.T) {
+	local, p := mount(t, upstream())
+	if err := p.Mount(context.Background(), "mirror", connect(t, upstream())); err != nil {
+		t.Fatalf("Mount: %v", err)
+	}
+	if len(local.GetResourceTemplates()) != 2 {
+		t.Fatalf("templates = %+v, want one per upstream", local.GetResourceTemplates())
+	}
+	p.Unmount("mirror")
+	templates := local.GetResourceTemplates()
+	if len(templates) != 1 || templates[0].URITemplate != "github+gh://repo/{name}" {
+		t.Errorf("templates after unmount = %+v", templates)
+	}
+}
+
+func TestUpstreamListChanges(t *testing.T) {
+	up := upstream()
+	local, _ := mount(t, up)
+	up.RegisterTool(tool("close_issue", func(map[string]any) (*types.ToolResult, error) { return text("closed"), nil }))
+	eventually(t, "github.close_issue", func() bool { return hasTool(local, "github.close_issue") })
+	up.UnregisterTool("create_issue")
+	eventually(t, "github.create_issue removal", func() bool { return !hasTool(local, "github.create_issue") })
+	if !hasTool(local, "create_issue") {
+		t.Error("local tool removed with the upstream one")
+	}
+}
+
+func TestUnmountRemovesEntries(t *testing.T) {
+	local, p := mount(t, upstream())
+	p.Unmount("github")
+	if hasTool(local, "github.create_issue") || len(local.GetPrompts()) != 0 || len(local.GetResourceTemplates()) != 0 {
+		t.Errorf("entries left after unmount: %+v %+v", local.GetTools(), local.GetPrompts())
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "proxy.json")
+	os.WriteFile(jsonPath, []byte(`{"mcpServers":{"github":{"command":"gh-mcp","args":["stdio"]},"docs":{"url":"http://localhost:9000/mcp"}}}`), 0o644)
+	cfg, err := proxy.Load(jsonPath)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if names := cfg.Names(); len(names) != 2 || names[0] != "docs" || cfg.Servers["github"].Args[0] != "stdio" {
+		t.Errorf("config = %+v", cfg)
+	}
+
+	yamlPath := filepath.Join(dir, "proxy.yaml")
+	os.WriteFile(yamlPath, []byte("mcpServers:\n  bad.name:\n    command: x\n"), 0o644)
+	if _, err := proxy.Load(yamlPath); err == nil {
+		t.Error("dotted server name accepted")
+	}
+	os.WriteFile(yamlPath, []byte("mcpServers:\n  both:\n    command: x\n    url: http://x\n"), 0o644)
+	if _, err := proxy.Load(yamlPath); err == nil {
+		t.Error("server with command and url accepted")
+	}
+}
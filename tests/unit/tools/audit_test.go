@@ -0,0 +1,34 @@
+package tools_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/orchestra-mcp/mcp/src/audit"
+	"github.com/orchestra-mcp/mcp/src/tools"
+)
+
+func TestQueryAuditLog(t *testing.T) {
+	ws := t.TempDir()
+	now := time.Now().UTC()
+	audit.Append(ws, audit.Entry{Time: now.Add(-48 * time.Hour), Tool: "delete_epic", Issues: []string{"OM-3"}})
+	audit.Append(ws, audit.Entry{Time: now, Tool: "delete_epic", Issues: []string{"OM-4"}})
+	audit.Append(ws, audit.Entry{Time: now, Tool: "list_epics"})
+	query := tools.Audit(ws)[0]
+
+	res, err := query.Handler(map[string]any{"tool": "delete_epic", "since": now.Add(-time.Hour).Format(time.RFC3339)})
+	if err != nil || res.IsError {
+		t.Fatalf("query: %v %+v", err, res)
+	}
+	var entries []audit.Entry
+	json.Unmarshal([]byte(res.Content[0].Text), &entries)
+	if len(entries) != 1 || entries[0].Issues[0] != "OM-4" {
+		t.Errorf("entries = %+v", entries)
+	}
+
+	res, _ = query.Handler(map[string]any{"since": "last week"})
+	if !res.IsError {
+		t.Error("expected an error for an invalid time")
+	}
+}
@@ -0,0 +1,82 @@
+package tools_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/orchestra-mcp/mcp/src/tools"
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+func reviewTaskPrompt(t *testing.T, ws string) types.Prompt {
+	t.Helper()
+	for _, p := range tools.Prompts(ws) {
+		if p.Definition.Name == "review_task" {
+			return p
+		}
+	}
+	t.Fatal("review_task prompt not found")
+	return types.Prompt{}
+}
+
+func TestCompletePromptIDs(t *testing.T) {
+	ws, epicID, storyID := setupStory(t)
+	res, _ := tools.Task(ws)[1].Handler(map[string]any{
+		"project": "test-app", "epic_id": epicID, "story_id": storyID, "title": "Build API", "type": "task",
+	})
+	var task map[string]any
+	json.Unmarshal([]byte(res.Content[0].Text), &task)
+
+	complete := reviewTaskPrompt(t, ws).Complete
+	cases := []struct {
+		arg  string
+		args map[string]string
+		want []string
+	}{
+		{"project", nil, []string{"test-app"}},
+		{"epic_id", map[string]string{"project": "test-app"}, []string{epicID}},
+		{"story_id", map[string]string{"project": "test-app", "epic_id": epicID}, []string{storyID}},
+		{"task_id", map[string]string{"project": "test-app", "epic_id": epicID, "story_id": storyID}, []string{task["id"].(string)}},
+		{"epic_id", nil, nil},
+	}
+	for _, c := range cases {
+		if got := complete(c.arg, c.args); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("complete(%s, %v) = %v, want %v", c.arg, c.args, got, c.want)
+		}
+	}
+}
+
+func TestCompleteRejectsPathArguments(t *testing.T) {
+	ws, epicID, _ := setupStory(t)
+	os.MkdirAll(filepath.Join(ws, "outside", "epics", "EVIL-1", "stories", "EVIL-2"), 0o755)
+	complete := reviewTaskPrompt(t, ws).Complete
+	for _, args := range []map[string]string{
+		{"project": "../outside", "epic_id": "EVIL-1"},
+		{"project": "test-app", "epic_id": "../../../outside/epics/EVIL-1"},
+	} {
+		if got := complete("story_id", args); got != nil {
+			t.Errorf("complete(story_id, %v) = %v, want nothing", args, got)
+		}
+	}
+	if got := complete("story_id", map[string]string{"project": "test-app", "epic_id": epicID}); len(got) != 1 {
+		t.Errorf("valid arguments rejected: %v", got)
+	}
+}
+
+func TestCompleteResourceTemplateVars(t *testing.T) {
+	ws, epicID, _ := setupStory(t)
+	for _, r := range tools.Resources(ws) {
+		if r.Definition.Name != "task_detail" {
+			continue
+		}
+		if got := r.Complete("slug", nil); !reflect.DeepEqual(got, []string{"test-app"}) {
+			t.Errorf("slug = %v", got)
+		}
+		if got := r.Complete("epicId", map[string]string{"slug": "test-app"}); !reflect.DeepEqual(got, []string{epicID}) {
+			t.Errorf("epicId = %v", got)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/orchestra-mcp/mcp/src/engine"
+	"github.com/orchestra-mcp/mcp/src/metrics"
 	"github.com/orchestra-mcp/mcp/src/tools"
 )
 
@@ -167,3 +168,38 @@ func TestGetSession(t *testing.T) {
 		t.Errorf("session_id = %v", session["session_id"])
 	}
 }
+
+func TestMemoryFallbackCounted(t *testing.T) {
+	ws := setupProject(t)
+	search := tools.Memory(ws, memoryBridge(ws))[1]
+	before := metrics.BridgeFallbacks.Value("search_memory", "engine_down")
+	if _, err := search.Handler(map[string]any{"project": "test-app", "query": "auth"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := metrics.BridgeFallbacks.Value("search_memory", "engine_down") - before; got != 1 {
+		t.Errorf("fallbacks = %v, want 1", got)
+	}
+}
+
+func TestMemoryOutputSchemasTyped(t *testing.T) {
+	ws := t.TempDir()
+	memTools := tools.Memory(ws, memoryBridge(ws))
+
+	for _, tool := range memTools {
+		s := tool.Definition.OutputSchema
+		if len(s.Properties) == 0 {
+			t.Errorf("%s: output schema has no properties", tool.Definition.Name)
+		}
+	}
+	if _, ok := memTools[0].Definition.OutputSchema.Properties["summary"]; !ok {
+		t.Errorf("save_memory schema = %+v", memTools[0].Definition.OutputSchema.Properties)
+	}
+	results := memTools[1].Definition.OutputSchema.Properties["results"].(map[string]any)
+	item := results["items"].(map[string]any)["properties"].(map[string]any)
+	if _, ok := item["chunk"]; !ok {
+		t.Errorf("search_memory item = %+v", item)
+	}
+	if _, ok := memTools[5].Definition.OutputSchema.Properties["session_id"]; !ok {
+		t.Errorf("get_session schema = %+v", memTools[5].Definition.OutputSchema.Properties)
+	}
+}
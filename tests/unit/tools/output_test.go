@@ -0,0 +1,52 @@
+package tools_test
+
+import (
+	"testing"
+
+	"github.com/orchestra-mcp/mcp/src/tools"
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+func TestBuiltinToolsDeclareOutputSchemaAndAnnotations(t *testing.T) {
+	ws := t.TempDir()
+	groups := [][]types.Tool{
+		tools.Project(ws), tools.Epic(ws), tools.Story(ws), tools.Task(ws),
+		tools.Workflow(ws), tools.Prd(ws), tools.Bugfix(ws), tools.Usage(ws),
+		tools.Readme(ws), tools.Artifacts(ws), tools.Lifecycle(ws), tools.Claude(ws),
+		tools.Memory(ws, memoryBridge(ws)),
+	}
+	for _, g := range groups {
+		for _, tool := range g {
+			if s := tool.Definition.OutputSchema; s == nil || s.Type != "object" {
+				t.Errorf("%s: missing object outputSchema", tool.Definition.Name)
+			}
+			a := tool.Definition.Annotations
+			if a == nil || a.Title == "" || a.ReadOnlyHint == nil || a.OpenWorldHint == nil || *a.OpenWorldHint {
+				t.Errorf("%s: incomplete annotations %+v", tool.Definition.Name, a)
+				continue
+			}
+			if !*a.ReadOnlyHint && (a.DestructiveHint == nil || a.IdempotentHint == nil) {
+				t.Errorf("%s: write tool needs destructive and idempotent hints", tool.Definition.Name)
+			}
+		}
+	}
+}
+
+func TestListEpicsStructuredContent(t *testing.T) {
+	ws := setupProject(t)
+	epicTools := tools.Epic(ws)
+	epicTools[1].Handler(map[string]any{"project": "test-app", "title": "Auth"})
+
+	res, err := epicTools[0].Handler(map[string]any{"project": "test-app"})
+	if err != nil || res.IsError {
+		t.Fatalf("list_epics failed: %v", err)
+	}
+	sc, ok := res.StructuredContent.(map[string]any)
+	if !ok {
+		t.Fatalf("StructuredContent = %#v", res.StructuredContent)
+	}
+	epics, ok := sc["epics"].([]types.IssueData)
+	if !ok || len(epics) != 1 || epics[0].Title != "Auth" {
+		t.Errorf("epics = %#v", sc["epics"])
+	}
+}
@@ -0,0 +1,138 @@
+package tools_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	h "github.com/orchestra-mcp/mcp/src/helpers"
+	"github.com/orchestra-mcp/mcp/src/tools"
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+// setupPrd starts a PRD session and answers the first question tersely.
+func setupPrd(t *testing.T) []types.Tool {
+	t.Helper()
+	ws := setupProject(t)
+	prd := tools.Prd(ws)
+	if res, err := prd[0].Handler(map[string]any{"project": "test-app"}); err != nil || res.IsError {
+		t.Fatalf("start_prd_session failed: %v", err)
+	}
+	if res, err := prd[1].Handler(map[string]any{"project": "test-app", "answer": "todo app"}); err != nil || res.IsError {
+		t.Fatalf("answer_prd_question failed: %v", err)
+	}
+	return prd
+}
+
+func TestPreviewPrdExpandsWithSampling(t *testing.T) {
+	prd := setupPrd(t)
+	var sent types.CreateMessageParams
+	ctx := h.WithSampler(context.Background(), func(_ context.Context, p types.CreateMessageParams) (*types.CreateMessageResult, error) {
+		sent = p
+		return &types.CreateMessageResult{Role: "assistant", Content: types.ContentBlock{Type: "text", Text: "# Expanded"}}, nil
+	})
+
+	res, err := prd[6].Call(ctx, map[string]any{"project": "test-app", "expand": true})
+	if err != nil || res.IsError {
+		t.Fatalf("preview_prd failed: %v", err)
+	}
+	if res.Content[0].Text != "# Expanded" {
+		t.Errorf("preview = %q, want the sampled text", res.Content[0].Text)
+	}
+	if len(sent.Messages) != 1 || !strings.Contains(sent.Messages[0].Content.Text, "todo app") {
+		t.Errorf("sampling request did not carry the answers: %+v", sent)
+	}
+}
+
+func TestPreviewPrdFallsBackWithoutSampling(t *testing.T) {
+	prd := setupPrd(t)
+	failing := h.WithSampler(context.Background(), func(context.Context, types.CreateMessageParams) (*types.CreateMessageResult, error) {
+		return nil, errors.New("rejected")
+	})
+	for name, ctx := range map[string]context.Context{"unsupported": context.Background(), "failed": failing} {
+		res, err := prd[6].Call(ctx, map[string]any{"project": "test-app", "expand": true})
+		if err != nil || res.IsError {
+			t.Fatalf("%s: preview_prd failed: %v", name, err)
+		}
+		if !strings.Contains(res.Content[0].Text, "todo app") {
+			t.Errorf("%s: preview = %q, want plain answers", name, res.Content[0].Text)
+		}
+	}
+}
+
+// formAnswers fills every field of an elicitation form, picking the first
+// enum value where there is one.
+func formAnswers(p types.ElicitParams) map[string]any {
+	content := map[string]any{}
+	for key, raw := range p.RequestedSchema.Properties {
+		prop := raw.(map[string]any)
+		if enum, ok := prop["enum"].([]string); ok {
+			content[key] = enum[0]
+		} else {
+			content[key] = "answer for " + key
+		}
+	}
+	return content
+}
+
+func TestStartPrdElicitsEachSection(t *testing.T) {
+	ws := setupProject(t)
+	var forms []types.ElicitParams
+	ctx := h.WithElicitor(context.Background(), func(_ context.Context, p types.ElicitParams) (*types.ElicitResult, error) {
+		forms = append(forms, p)
+		return &types.ElicitResult{Action: "accept", Content: formAnswers(p)}, nil
+	})
+
+	res, err := tools.Prd(ws)[0].Call(ctx, map[string]any{"project": "test-app"})
+	if err != nil || res.IsError {
+		t.Fatalf("start_prd_session failed: %v", err)
+	}
+	if st := res.StructuredContent.(map[string]any); st["status"] != "complete" || st["file"] != "prd.md" {
+		t.Errorf("result = %v, want complete", st)
+	}
+	if len(forms) != 5 {
+		t.Fatalf("sent %d forms, want one per section (5)", len(forms))
+	}
+	overview := forms[0].RequestedSchema
+	if len(overview.Properties) != 3 || strings.Join(overview.Required, ",") != "project_name,project_description,target_audience" {
+		t.Errorf("overview schema = %+v", overview)
+	}
+	audience := overview.Properties["target_audience"].(map[string]any)
+	if enum, _ := audience["enum"].([]string); len(enum) != 4 || enum[0] != "Developers" {
+		t.Errorf("target_audience enum = %v", audience["enum"])
+	}
+	md, _ := os.ReadFile(filepath.Join(ws, ".projects", "test-app", "prd.md"))
+	if !strings.Contains(string(md), "answer for primary_goals") || !strings.Contains(string(md), "Developers") {
+		t.Errorf("prd.md missing elicited answers:\n%s", md)
+	}
+}
+
+func TestStartPrdFallsBackWhenDeclined(t *testing.T) {
+	ws := setupProject(t)
+	calls := 0
+	ctx := h.WithElicitor(context.Background(), func(_ context.Context, p types.ElicitParams) (*types.ElicitResult, error) {
+		calls++
+		if calls == 1 {
+			return &types.ElicitResult{Action: "accept", Content: formAnswers(p)}, nil
+		}
+		return &types.ElicitResult{Action: "decline"}, nil
+	})
+
+	prd := tools.Prd(ws)
+	res, err := prd[0].Call(ctx, map[string]any{"project": "test-app"})
+	if err != nil || res.IsError {
+		t.Fatalf("start_prd_session failed: %v", err)
+	}
+	st := res.StructuredContent.(map[string]any)
+	if st["status"] != "in_progress" || st["key"] != "primary_goals" {
+		t.Errorf("result = %v, want the first goals question", st)
+	}
+	// The step-by-step tools continue from there.
+	res, _ = prd[1].Handler(map[string]any{"project": "test-app", "answer": "ship it"})
+	if st := res.StructuredContent.(map[string]any); st["key"] != "success_metrics" {
+		t.Errorf("after answer = %v", st)
+	}
+}
@@ -0,0 +1,58 @@
+package tools_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	h "github.com/orchestra-mcp/mcp/src/helpers"
+	"github.com/orchestra-mcp/mcp/src/tools"
+)
+
+func TestRegenerateReadme(t *testing.T) {
+	ws := setupProject(t)
+	tools.Epic(ws)[1].Handler(map[string]any{"project": "test-app", "title": "Auth System"})
+
+	res, err := tools.Readme(ws)[0].Call(context.Background(), map[string]any{"project": "test-app"})
+	if err != nil || res.IsError {
+		t.Fatalf("regenerate_readme: %v %+v", err, res)
+	}
+	data, err := os.ReadFile(filepath.Join(h.ProjectDir(ws, "test-app"), "README.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "Auth System") {
+		t.Errorf("README missing epic:\n%s", data)
+	}
+}
+
+func TestRegenerateReadmeCancelledKeepsFile(t *testing.T) {
+	ws := setupProject(t)
+	epics := tools.Epic(ws)
+	for _, title := range []string{"One", "Two", "Three"} {
+		epics[1].Handler(map[string]any{"project": "test-app", "title": title})
+	}
+	readme := filepath.Join(h.ProjectDir(ws, "test-app"), "README.md")
+	if err := os.WriteFile(readme, []byte("original\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// Cancel once the scan has reached the first epic.
+	ctx = h.WithProgress(ctx, func(progress, total float64, message string) { cancel() })
+	_, err := tools.Readme(ws)[0].Call(ctx, map[string]any{"project": "test-app"})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	data, err := os.ReadFile(readme)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "original\n" {
+		t.Errorf("README.md rewritten after cancel:\n%s", data)
+	}
+}
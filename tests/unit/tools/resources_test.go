@@ -0,0 +1,53 @@
+package tools_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/orchestra-mcp/mcp/src/tools"
+)
+
+func TestResourcesEnumerateInstances(t *testing.T) {
+	ws, epicID, storyID := setupStory(t)
+	tools.Task(ws)[1].Handler(map[string]any{
+		"project": "test-app", "epic_id": epicID, "story_id": storyID,
+		"title": "Build API", "type": "task",
+	})
+
+	byName := map[string]int{}
+	for _, r := range tools.Resources(ws) {
+		if r.List == nil {
+			t.Fatalf("%s has no List func", r.Definition.Name)
+		}
+		for _, inst := range r.List() {
+			if strings.Contains(inst.URI, "{") {
+				t.Errorf("instance URI still templated: %s", inst.URI)
+			}
+			contents, err := r.Handler(inst.URI)
+			if err != nil || len(contents) == 0 {
+				t.Errorf("read %s: %v", inst.URI, err)
+			}
+			byName[r.Definition.Name]++
+		}
+	}
+	if byName["project_status"] != 1 || byName["task_detail"] != 1 {
+		t.Errorf("instances = %v", byName)
+	}
+}
+
+func TestResourcesRejectTraversal(t *testing.T) {
+	ws := setupProject(t)
+	os.WriteFile(filepath.Join(ws, "project-status.toon"), []byte("project: outside\n"), 0o644)
+	for _, r := range tools.Resources(ws) {
+		if r.Definition.Name != "project_status" {
+			continue
+		}
+		if contents, err := r.Handler("toon://project/../status"); err == nil {
+			t.Errorf("read outside .projects: %+v", contents)
+		}
+		return
+	}
+	t.Fatal("project_status resource not found")
+}
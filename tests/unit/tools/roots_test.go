@@ -0,0 +1,65 @@
+package tools_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	h "github.com/orchestra-mcp/mcp/src/helpers"
+	"github.com/orchestra-mcp/mcp/src/tools"
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+// getProject calls the rooted get_project_status tool with the given roots.
+func projectStatus(t *testing.T, ws string, roots []types.Root, args map[string]any) *types.ToolResult {
+	t.Helper()
+	ctx := h.WithRoots(context.Background(), roots)
+	res, err := tools.Rooted(ws, tools.Project)[2].Call(ctx, args)
+	if err != nil {
+		t.Fatalf("get_project_status: %v", err)
+	}
+	return res
+}
+
+func TestRootedAddsWorkspaceArgument(t *testing.T) {
+	for _, tool := range tools.Rooted(t.TempDir(), tools.Project) {
+		if _, ok := tool.Definition.InputSchema.Properties["workspace"]; !ok {
+			t.Errorf("%s: missing workspace property", tool.Definition.Name)
+		}
+	}
+	if _, ok := tools.Project(t.TempDir())[2].Definition.InputSchema.Properties["workspace"]; ok {
+		t.Error("Rooted must not modify the wrapped tool's schema")
+	}
+}
+
+func TestRootedFindsProjectInRoot(t *testing.T) {
+	launch, other := t.TempDir(), setupProject(t)
+	roots := []types.Root{{URI: "file://" + other, Name: "other"}}
+
+	res := projectStatus(t, launch, roots, map[string]any{"project": "test-app"})
+	if res.IsError || !strings.Contains(res.Content[0].Text, "Test App") {
+		t.Errorf("expected project from root, got %q", res.Content[0].Text)
+	}
+	if res := projectStatus(t, launch, nil, map[string]any{"project": "test-app"}); !res.IsError {
+		t.Error("without roots the project should not be found")
+	}
+}
+
+func TestRootedAmbiguousProject(t *testing.T) {
+	launch, other := setupProject(t), setupProject(t)
+	roots := []types.Root{{URI: "file://" + other, Name: "other"}}
+
+	res := projectStatus(t, launch, roots, map[string]any{"project": "test-app"})
+	if !res.IsError || !strings.Contains(res.Content[0].Text, "pass workspace") {
+		t.Errorf("expected ambiguity error, got %q", res.Content[0].Text)
+	}
+	for _, ws := range []string{"other", other} {
+		if res := projectStatus(t, launch, roots, map[string]any{"project": "test-app", "workspace": ws}); res.IsError {
+			t.Errorf("workspace %q: %s", ws, res.Content[0].Text)
+		}
+	}
+	res = projectStatus(t, launch, roots, map[string]any{"project": "test-app", "workspace": "missing"})
+	if !res.IsError || !strings.Contains(res.Content[0].Text, "unknown workspace") {
+		t.Errorf("expected unknown workspace error, got %q", res.Content[0].Text)
+	}
+}
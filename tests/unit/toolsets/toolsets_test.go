@@ -0,0 +1,136 @@
+package toolsets_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/orchestra-mcp/mcp/src/tools"
+	"github.com/orchestra-mcp/mcp/src/toolsets"
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+func names(ts []types.Tool) []string {
+	out := make([]string, len(ts))
+	for i, tool := range ts {
+		out[i] = tool.Definition.Name
+	}
+	return out
+}
+
+func resolve(t *testing.T, cfg toolsets.Config, profile string, sets ...string) toolsets.Selection {
+	t.Helper()
+	sel, err := cfg.Resolve(profile, sets)
+	if err != nil {
+		t.Fatalf("Resolve(%q, %v): %v", profile, sets, err)
+	}
+	return sel
+}
+
+func TestProfiles(t *testing.T) {
+	ws := t.TempDir()
+	project := tools.Project(ws)
+
+	admin := names(resolve(t, toolsets.Config{}, "").Filter("project", project))
+	if len(admin) != len(project) {
+		t.Errorf("admin = %v, want all %d project tools", admin, len(project))
+	}
+
+	readonly := names(resolve(t, toolsets.Config{}, "readonly").Filter("project", project))
+	if !slices.Contains(readonly, "list_projects") || slices.Contains(readonly, "create_project") {
+		t.Errorf("readonly = %v", readonly)
+	}
+	if n := len(resolve(t, toolsets.Config{}, "readonly").Filter("audit", tools.Audit(ws))); n != 0 {
+		t.Errorf("readonly exposes %d audit tools", n)
+	}
+
+	agent := resolve(t, toolsets.Config{}, "agent")
+	got := names(agent.Filter("project", project))
+	if !slices.Contains(got, "create_project") || slices.Contains(got, "write_prd") {
+		t.Errorf("agent project tools = %v", got)
+	}
+	if n := len(agent.Filter("claude", tools.Claude(ws))); n != 0 {
+		t.Errorf("agent exposes %d claude tools", n)
+	}
+}
+
+func TestToolsetsAndToolRules(t *testing.T) {
+	ws := t.TempDir()
+	cfg := toolsets.Config{Selection: toolsets.Selection{
+		Tools: toolsets.ToolRules{Enabled: []string{"list_projects"}, Disabled: []string{"get_next_task"}},
+	}}
+	sel := resolve(t, cfg, "", "workflow")
+	if n := len(sel.Filter("epic", tools.Epic(ws))); n != 0 {
+		t.Errorf("epic tools exposed with --toolsets workflow: %d", n)
+	}
+	if got := names(sel.Filter("project", tools.Project(ws))); !slices.Equal(got, []string{"list_projects"}) {
+		t.Errorf("enabled tool outside toolsets: %v", got)
+	}
+	if got := names(sel.Filter("workflow", tools.Workflow(ws))); slices.Contains(got, "get_next_task") || len(got) == 0 {
+		t.Errorf("workflow tools = %v", got)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	ws := t.TempDir()
+	if cfg, err := toolsets.Load(ws); err != nil || cfg.Profile != "" {
+		t.Fatalf("missing config = %+v, %v", cfg, err)
+	}
+	os.MkdirAll(filepath.Join(ws, ".orchestra"), 0o755)
+	os.WriteFile(toolsets.ConfigPath(ws), []byte(`profile: reviewer
+disabled_toolsets: [audit]
+profiles:
+  reviewer:
+    toolsets: [project, epic, audit]
+    read_only: true
+`), 0o644)
+	cfg, err := toolsets.Load(ws)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	sel := resolve(t, cfg, "")
+	if !sel.ReadOnly || !slices.Equal(sel.Toolsets, []string{"project", "epic", "audit"}) {
+		t.Errorf("selection = %+v", sel)
+	}
+	if n := len(sel.Filter("audit", tools.Audit(ws))); n != 0 {
+		t.Errorf("disabled toolset exposes %d tools", n)
+	}
+	if sel := resolve(t, cfg, "admin"); sel.ReadOnly {
+		t.Error("--profile admin did not override the config profile")
+	}
+
+	if _, err := cfg.Resolve("nobody", nil); err == nil {
+		t.Error("unknown profile accepted")
+	}
+	if _, err := cfg.Resolve("", []string{"workflow", "nope"}); err == nil {
+		t.Error("unknown toolset accepted")
+	}
+}
+
+func TestParseList(t *testing.T) {
+	if got := toolsets.ParseList(" workflow, ,memory "); !slices.Equal(got, []string{"workflow", "memory"}) {
+		t.Errorf("ParseList = %v", got)
+	}
+}
+
+func TestValidateToolRules(t *testing.T) {
+	ws := t.TempDir()
+	groups := map[string][]types.Tool{"project": tools.Project(ws), "workflow": tools.Workflow(ws)}
+
+	ok := toolsets.Selection{Tools: toolsets.ToolRules{
+		Enabled: []string{"list_projects"}, Disabled: []string{"get_next_task", "github.create_issue"},
+	}}
+	if err := ok.ValidateTools(groups); err != nil {
+		t.Errorf("ValidateTools: %v", err)
+	}
+	for _, rules := range []toolsets.ToolRules{
+		{Enabled: []string{"list_projectz"}},
+		{Disabled: []string{"delete_everything"}},
+	} {
+		sel := toolsets.Selection{Tools: rules}
+		if err := sel.ValidateTools(groups); err == nil {
+			t.Errorf("ValidateTools(%+v) accepted an unknown tool", rules)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package tracing_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orchestra-mcp/mcp/src/engine"
+	pb "github.com/orchestra-mcp/mcp/src/gen/memoryv1"
+	"github.com/orchestra-mcp/mcp/src/tracing"
+	"github.com/orchestra-mcp/mcp/src/transport"
+	"github.com/orchestra-mcp/mcp/src/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// span is the part of a stdouttrace record the tests look at.
+type span struct {
+	Name        string
+	SpanContext struct{ TraceID string }
+	Parent      struct{ SpanID string }
+}
+
+func readSpans(t *testing.T, path string) []span {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var out []span
+	dec := json.NewDecoder(bufio.NewReader(f))
+	for dec.More() {
+		var s span
+		if err := dec.Decode(&s); err != nil {
+			t.Fatalf("decode span: %v", err)
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// memoryServer records the metadata of each SearchMemory call.
+type memoryServer struct {
+	pb.UnimplementedMemoryServiceServer
+	md chan metadata.MD
+}
+
+func (m *memoryServer) SearchMemory(ctx context.Context, _ *pb.SearchRequest) (*pb.SearchResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	m.md <- md
+	return &pb.SearchResponse{}, nil
+}
+
+func TestSpansFromRequestToEngine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spans.jsonl")
+	shutdown, err := tracing.Setup(context.Background(), tracing.Config{File: path})
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mem := &memoryServer{md: make(chan metadata.MD, 1)}
+	gs := grpc.NewServer()
+	pb.RegisterMemoryServiceServer(gs, mem)
+	go gs.Serve(lis)
+	defer gs.Stop()
+	client, err := engine.Dial(lis.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	s := transport.New("s", "1.0")
+	s.Use(transport.Tracing())
+	s.RegisterTool(types.NewContextTool(
+		types.ToolDefinition{Name: "search", InputSchema: types.InputSchema{Type: "object"}},
+		func(ctx context.Context, _ map[string]any) (*types.ToolResult, error) {
+			_, err := client.SearchMemory(ctx, "p", "q", 1)
+			return &types.ToolResult{}, err
+		},
+	))
+	var out strings.Builder
+	s.Serve(strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"search","arguments":{}}}`+"\n"), &out)
+	if !strings.Contains(out.String(), `"result"`) {
+		t.Fatalf("response = %s", out.String())
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	md := <-mem.md
+	traceparent := md.Get("traceparent")
+	if len(traceparent) != 1 {
+		t.Fatalf("engine metadata has no traceparent: %v", md)
+	}
+
+	spans := readSpans(t, path)
+	byName := map[string]span{}
+	for _, sp := range spans {
+		byName[sp.Name] = sp
+	}
+	for _, name := range []string{"jsonrpc.message", "jsonrpc.decode", "tools/call", "tool search"} {
+		sp, ok := byName[name]
+		if !ok {
+			t.Fatalf("missing span %q in %+v", name, spans)
+		}
+		if sp.SpanContext.TraceID != byName["jsonrpc.message"].SpanContext.TraceID {
+			t.Errorf("span %q is in another trace", name)
+		}
+	}
+	if !strings.Contains(traceparent[0], byName["jsonrpc.message"].SpanContext.TraceID) {
+		t.Errorf("traceparent %q does not carry trace %s", traceparent[0], byName["jsonrpc.message"].SpanContext.TraceID)
+	}
+}
+
+func TestDisabledByDefault(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	if (tracing.Config{}).Enabled() {
+		t.Error("empty config should not enable tracing")
+	}
+	if !(tracing.Config{OTLPEndpoint: "localhost:4317"}).Enabled() {
+		t.Error("endpoint should enable tracing")
+	}
+}
+
+func TestSetupReleasesExporterOnError(t *testing.T) {
+	before := runtime.NumGoroutine()
+	_, err := tracing.Setup(context.Background(), tracing.Config{
+		OTLPEndpoint: "127.0.0.1:1",
+		File:         filepath.Join(t.TempDir(), "missing", "spans.json"),
+	})
+	if err == nil {
+		t.Fatal("Setup succeeded with an unwritable trace file")
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := runtime.NumGoroutine(); n > before {
+		t.Errorf("goroutines = %d after failed Setup, want %d: the OTLP exporter was not shut down", n, before)
+	}
+}
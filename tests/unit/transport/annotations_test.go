@@ -0,0 +1,38 @@
+package transport_test
+
+import (
+	"testing"
+
+	"github.com/orchestra-mcp/mcp/src/transport"
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+func annotatedServer() *transport.MCPServer {
+	readOnly, open := true, false
+	s := transport.New("s", "1.0")
+	s.RegisterTool(types.Tool{
+		Definition: types.ToolDefinition{
+			Name:        "names",
+			InputSchema: types.InputSchema{Type: "object"},
+			Annotations: &types.ToolAnnotations{Title: "Names", ReadOnlyHint: &readOnly, OpenWorldHint: &open},
+		},
+		Handler: func(map[string]any) (*types.ToolResult, error) { return nil, nil },
+	})
+	return s
+}
+
+func TestToolAnnotationsByVersion(t *testing.T) {
+	cases := map[string]bool{"2024-11-05": false, "2025-03-26": true, "2025-06-18": true}
+	for version, want := range cases {
+		s := annotatedServer()
+		w := newRecorder()
+		initialize(t, s, w, `{"protocolVersion":"`+version+`"}`)
+		a := listTools(t, s, w)[0].Annotations
+		if (a != nil) != want {
+			t.Errorf("%s: annotations present = %v, want %v", version, a != nil, want)
+		}
+		if want && (a.Title != "Names" || !*a.ReadOnlyHint) {
+			t.Errorf("%s: annotations = %+v", version, a)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package transport_test
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/orchestra-mcp/mcp/src/auth"
+	h "github.com/orchestra-mcp/mcp/src/helpers"
+	"github.com/orchestra-mcp/mcp/src/transport"
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+func authServer(t *testing.T) *transport.MCPServer {
+	t.Helper()
+	s := transport.New("s", "1.0")
+	readOnly, destructive := true, true
+	s.RegisterTools([]types.Tool{
+		{Definition: types.ToolDefinition{Name: "peek", InputSchema: types.InputSchema{Type: "object"},
+			Annotations: &types.ToolAnnotations{ReadOnlyHint: &readOnly}},
+			Handler: func(map[string]any) (*types.ToolResult, error) { return h.TextResult("ok"), nil }},
+		{Definition: types.ToolDefinition{Name: "wipe", InputSchema: types.InputSchema{Type: "object"},
+			Annotations: &types.ToolAnnotations{ReadOnlyHint: new(bool), DestructiveHint: &destructive}},
+			Handler: func(map[string]any) (*types.ToolResult, error) { return h.TextResult("wiped"), nil }},
+	})
+	a, err := auth.New([]auth.Token{{Name: "reader", Secret: "r", Scopes: []auth.Scope{auth.ScopeRead}}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetAuthenticator(a)
+	return s
+}
+
+func callAs(s *transport.MCPServer, p *auth.Principal, tool string) types.JSONRPCResponse {
+	w := newRecorder()
+	w.session.SetPrincipal(p)
+	s.HandleRequest(&types.JSONRPCRequest{
+		JSONRPC: "2.0", ID: 1, Method: "tools/call", Params: json.RawMessage(`{"name":"` + tool + `"}`),
+	}, w)
+	return w.responses[len(w.responses)-1]
+}
+
+func TestToolCallAuthorization(t *testing.T) {
+	s := authServer(t)
+	reader := &auth.Principal{Name: "reader", Scopes: []auth.Scope{auth.ScopeRead}}
+
+	if resp := callAs(s, reader, "peek"); resp.Error != nil {
+		t.Errorf("reader peek: %+v", resp.Error)
+	}
+	if resp := callAs(s, reader, "wipe"); resp.Error == nil || resp.Error.Code != auth.CodeForbidden {
+		t.Errorf("reader wipe: %+v, want %d", resp.Error, auth.CodeForbidden)
+	}
+	if resp := callAs(s, nil, "peek"); resp.Error == nil || resp.Error.Code != auth.CodeUnauthorized {
+		t.Errorf("anonymous peek: %+v, want %d", resp.Error, auth.CodeUnauthorized)
+	}
+}
+
+func TestStdioTrustedWithAuth(t *testing.T) {
+	s := authServer(t)
+	s.RegisterPrompt(types.Prompt{Definition: types.PromptDefinition{Name: "hello"},
+		Handler: func(map[string]string) (string, []types.PromptMessage, error) { return "hi", nil, nil }})
+	in, lines := stdioPipe(t, s)
+	io.WriteString(in, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`+"\n")
+	next(t, lines)
+
+	io.WriteString(in, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"wipe"}}`+"\n")
+	io.WriteString(in, `{"jsonrpc":"2.0","id":3,"method":"prompts/get","params":{"name":"hello"}}`+"\n")
+	for range 2 {
+		if msg := next(t, lines); msg["error"] != nil || msg["result"] == nil {
+			t.Errorf("stdio request %v refused: %v", msg["id"], msg["error"])
+		}
+	}
+}
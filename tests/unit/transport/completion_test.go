@@ -0,0 +1,89 @@
+package transport_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/orchestra-mcp/mcp/src/transport"
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+func completionServer() *transport.MCPServer {
+	s := transport.New("s", "1.0")
+	s.RegisterPrompt(types.Prompt{
+		Definition: types.PromptDefinition{Name: "review"},
+		Handler:    func(map[string]string) (string, []types.PromptMessage, error) { return "", nil, nil },
+		Complete: func(name string, args map[string]string) []string {
+			if name == "task" && args["project"] == "app" {
+				var ids []string
+				for i := 1; i <= 150; i++ {
+					ids = append(ids, fmt.Sprintf("TASK-%d", i))
+				}
+				return ids
+			}
+			return []string{"app", "api", "web"}
+		},
+	})
+	s.RegisterResource(types.Resource{
+		Definition: types.ResourceDefinition{URI: "toon://project/{slug}", Name: "project"},
+		Handler:    func(string) ([]types.ResourceContent, error) { return nil, nil },
+	})
+	return s
+}
+
+func complete(t *testing.T, s *transport.MCPServer, params string) types.JSONRPCResponse {
+	t.Helper()
+	w := newRecorder()
+	s.HandleRequest(&types.JSONRPCRequest{
+		JSONRPC: "2.0", ID: 1, Method: "completion/complete", Params: json.RawMessage(params),
+	}, w)
+	return w.last(t)
+}
+
+func TestCompletePromptFiltersByPrefix(t *testing.T) {
+	resp := complete(t, completionServer(), `{"ref":{"type":"ref/prompt","name":"review"},"argument":{"name":"project","value":"A"}}`)
+	c := resp.Result.(types.CompleteResult).Completion
+	if len(c.Values) != 2 || c.Values[0] != "app" || c.Values[1] != "api" || c.HasMore {
+		t.Errorf("completion = %+v", c)
+	}
+}
+
+func TestCompleteUsesContextAndCaps(t *testing.T) {
+	resp := complete(t, completionServer(), `{"ref":{"type":"ref/prompt","name":"review"},"argument":{"name":"task","value":""},"context":{"arguments":{"project":"app"}}}`)
+	c := resp.Result.(types.CompleteResult).Completion
+	if len(c.Values) != 100 || c.Total != 150 || !c.HasMore {
+		t.Errorf("values=%d total=%d hasMore=%v", len(c.Values), c.Total, c.HasMore)
+	}
+}
+
+func TestCompleteResourceWithoutCompleter(t *testing.T) {
+	resp := complete(t, completionServer(), `{"ref":{"type":"ref/resource","uri":"toon://project/{slug}"},"argument":{"name":"slug","value":"x"}}`)
+	if resp.Error != nil {
+		t.Fatalf("error: %v", resp.Error)
+	}
+	if c := resp.Result.(types.CompleteResult).Completion; c.Values == nil || len(c.Values) != 0 {
+		t.Errorf("values = %#v, want empty array", c.Values)
+	}
+}
+
+func TestCompleteUnknownRef(t *testing.T) {
+	for _, params := range []string{
+		`{"ref":{"type":"ref/prompt","name":"nope"},"argument":{"name":"a","value":""}}`,
+		`{"ref":{"type":"ref/other"},"argument":{"name":"a","value":""}}`,
+	} {
+		if resp := complete(t, completionServer(), params); resp.Error == nil || resp.Error.Code != -32602 {
+			t.Errorf("%s: response = %+v", params, resp)
+		}
+	}
+}
+
+func TestCompletionsCapabilityByVersion(t *testing.T) {
+	s := completionServer()
+	if res := initialize(t, s, newRecorder(), `{"protocolVersion":"2025-03-26"}`); res.Capabilities.Completions == nil {
+		t.Error("completions capability missing for 2025-03-26")
+	}
+	if res := initialize(t, s, newRecorder(), `{"protocolVersion":"2024-11-05"}`); res.Capabilities.Completions != nil {
+		t.Error("completions capability advertised to 2024-11-05 client")
+	}
+}
@@ -0,0 +1,105 @@
+package transport_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/orchestra-mcp/mcp/src/transport"
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+// stdioPipe runs Serve over pipes and returns the request writer and a
+// channel of decoded response lines.
+func stdioPipe(t *testing.T, s *transport.MCPServer) (io.WriteCloser, <-chan map[string]any) {
+	t.Helper()
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	go func() {
+		s.Serve(inR, outW)
+		outW.Close()
+	}()
+	lines := make(chan map[string]any, 16)
+	go func() {
+		sc := bufio.NewScanner(outR)
+		for sc.Scan() {
+			var m map[string]any
+			_ = json.Unmarshal(sc.Bytes(), &m)
+			lines <- m
+		}
+		close(lines)
+	}()
+	t.Cleanup(func() { inW.Close() })
+	return inW, lines
+}
+
+func next(t *testing.T, lines <-chan map[string]any) map[string]any {
+	t.Helper()
+	select {
+	case m, ok := <-lines:
+		if !ok {
+			t.Fatal("output closed")
+		}
+		return m
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for response")
+	}
+	return nil
+}
+
+func TestSlowToolDoesNotBlockPing(t *testing.T) {
+	s := transport.New("s", "1.0")
+	started := make(chan struct{})
+	finished := make(chan error, 1)
+	s.RegisterTool(types.NewContextTool(
+		types.ToolDefinition{Name: "slow", InputSchema: types.InputSchema{Type: "object"}},
+		func(ctx context.Context, args map[string]any) (*types.ToolResult, error) {
+			close(started)
+			<-ctx.Done()
+			finished <- ctx.Err()
+			return nil, ctx.Err()
+		},
+	))
+	in, lines := stdioPipe(t, s)
+
+	io.WriteString(in, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"slow"}}`+"\n")
+	<-started
+	io.WriteString(in, `{"jsonrpc":"2.0","id":2,"method":"ping"}`+"\n")
+	if resp := next(t, lines); resp["id"] != float64(2) {
+		t.Fatalf("expected ping response first, got %v", resp)
+	}
+
+	io.WriteString(in, `{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":1,"reason":"user"}}`+"\n")
+	select {
+	case err := <-finished:
+		if err != context.Canceled {
+			t.Errorf("handler ctx err = %v, want context.Canceled", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("cancellation did not reach handler")
+	}
+
+	io.WriteString(in, `{"jsonrpc":"2.0","id":3,"method":"ping"}`+"\n")
+	if resp := next(t, lines); resp["id"] != float64(3) {
+		t.Errorf("cancelled call must not be answered, got %v", resp)
+	}
+}
+
+func TestPlainHandlerStillCalled(t *testing.T) {
+	s := transport.New("s", "1.0")
+	s.RegisterTool(types.Tool{
+		Definition: types.ToolDefinition{Name: "plain", InputSchema: types.InputSchema{Type: "object"}},
+		Handler: func(map[string]any) (*types.ToolResult, error) {
+			return &types.ToolResult{Content: []types.ContentBlock{{Type: "text", Text: "ok"}}}, nil
+		},
+	})
+	in, lines := stdioPipe(t, s)
+	io.WriteString(in, `{"jsonrpc":"2.0","id":"a","method":"tools/call","params":{"name":"plain"}}`+"\n")
+	resp := next(t, lines)
+	if resp["id"] != "a" || resp["result"] == nil {
+		t.Errorf("unexpected response: %v", resp)
+	}
+}
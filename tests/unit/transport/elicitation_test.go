@@ -0,0 +1,67 @@
+package transport_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	h "github.com/orchestra-mcp/mcp/src/helpers"
+	"github.com/orchestra-mcp/mcp/src/transport"
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+func elicitationServer() *transport.MCPServer {
+	s := transport.New("s", "1.0")
+	s.RegisterTool(types.NewContextTool(
+		types.ToolDefinition{Name: "form", InputSchema: types.InputSchema{Type: "object"}},
+		func(ctx context.Context, args map[string]any) (*types.ToolResult, error) {
+			res, err := h.Elicit(ctx, types.ElicitParams{
+				Message: "Name?",
+				RequestedSchema: types.ElicitSchema{Type: "object", Properties: map[string]any{
+					"name": map[string]any{"type": "string"},
+				}, Required: []string{"name"}},
+			})
+			if err != nil {
+				return h.ErrorResult(err.Error()), nil
+			}
+			return h.TextResult(res.Action + ":" + h.GetString(res.Content, "name")), nil
+		},
+	))
+	return s
+}
+
+func TestElicitationRoundTrip(t *testing.T) {
+	in, lines := stdioPipe(t, elicitationServer())
+	io.WriteString(in, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{"elicitation":{}}}}`+"\n")
+	next(t, lines)
+	io.WriteString(in, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"form"}}`+"\n")
+
+	req := next(t, lines)
+	params, _ := req["params"].(map[string]any)
+	if req["method"] != "elicitation/create" || params["message"] != "Name?" || params["requestedSchema"] == nil {
+		t.Fatalf("expected elicitation request, got %v", req)
+	}
+	id, _ := json.Marshal(req["id"])
+	io.WriteString(in, `{"jsonrpc":"2.0","id":`+string(id)+`,"result":{"action":"accept","content":{"name":"Ada"}}}`+"\n")
+
+	if text, isErr := toolText(t, next(t, lines)); text != "accept:Ada" || isErr {
+		t.Errorf("tool text = %q (isError %v)", text, isErr)
+	}
+}
+
+func TestElicitationRequiresProtocolVersion(t *testing.T) {
+	s := elicitationServer()
+	w := newRecorder()
+	initialize(t, s, w, `{"protocolVersion":"2025-03-26","capabilities":{"elicitation":{}}}`)
+	s.HandleRequest(&types.JSONRPCRequest{
+		JSONRPC: "2.0", ID: 2, Method: "tools/call", Params: json.RawMessage(`{"name":"form"}`),
+	}, w)
+	if len(w.requests) != 0 {
+		t.Errorf("sent %d requests to a 2025-03-26 session", len(w.requests))
+	}
+	res, _ := w.last(t).Result.(*types.ToolResult)
+	if res == nil || res.Content[0].Text != h.ErrElicitationUnsupported.Error() {
+		t.Errorf("result = %#v", w.last(t).Result)
+	}
+}
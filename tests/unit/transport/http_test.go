@@ -0,0 +1,205 @@
+package transport_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/orchestra-mcp/mcp/src/auth"
+	"github.com/orchestra-mcp/mcp/src/transport"
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+func newHTTPServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	s := transport.New("test-server", "1.0.0")
+	s.RegisterTool(types.Tool{
+		Definition: types.ToolDefinition{Name: "echo", InputSchema: types.InputSchema{Type: "object"}},
+		Handler: func(args map[string]any) (*types.ToolResult, error) {
+			return &types.ToolResult{Content: []types.ContentBlock{{Type: "text", Text: "hi"}}}, nil
+		},
+	})
+	srv := httptest.NewServer(transport.NewHTTPHandler(s))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func post(t *testing.T, url, session, accept, body string) *http.Response {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", accept)
+	if session != "" {
+		req.Header.Set(transport.SessionHeader, session)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestHTTPInitializeAssignsSession(t *testing.T) {
+	srv := newHTTPServer(t)
+	resp := post(t, srv.URL, "", "application/json, text/event-stream",
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d", resp.StatusCode)
+	}
+	if resp.Header.Get(transport.SessionHeader) == "" {
+		t.Fatal("missing Mcp-Session-Id header")
+	}
+	var out types.JSONRPCResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if out.Error != nil || out.Result == nil {
+		t.Errorf("unexpected response: %+v", out)
+	}
+}
+
+func TestHTTPRequiresSession(t *testing.T) {
+	srv := newHTTPServer(t)
+	resp := post(t, srv.URL, "", "application/json", `{"jsonrpc":"2.0","id":1,"method":"ping"}`)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("missing session status = %d, want 400", resp.StatusCode)
+	}
+	resp = post(t, srv.URL, "nope", "application/json", `{"jsonrpc":"2.0","id":1,"method":"ping"}`)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown session status = %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestHTTPToolCallStreams(t *testing.T) {
+	srv := newHTTPServer(t)
+	init := post(t, srv.URL, "", "application/json", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`)
+	sid := init.Header.Get(transport.SessionHeader)
+
+	resp := post(t, srv.URL, sid, "application/json, text/event-stream",
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"echo"}}`)
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q, want text/event-stream", ct)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(body), "event: message") || !strings.Contains(string(body), `"hi"`) {
+		t.Errorf("unexpected stream: %s", body)
+	}
+}
+
+func TestHTTPNotificationAccepted(t *testing.T) {
+	srv := newHTTPServer(t)
+	init := post(t, srv.URL, "", "application/json", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`)
+	sid := init.Header.Get(transport.SessionHeader)
+
+	resp := post(t, srv.URL, sid, "application/json", `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Errorf("status = %d, want 202", resp.StatusCode)
+	}
+
+	req, _ := http.NewRequest(http.MethodDelete, srv.URL, nil)
+	req.Header.Set(transport.SessionHeader, sid)
+	del, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("DELETE: %v", err)
+	}
+	del.Body.Close()
+	if del.StatusCode != http.StatusNoContent {
+		t.Errorf("DELETE status = %d, want 204", del.StatusCode)
+	}
+}
+
+func TestHTTPRejectsForeignOrigin(t *testing.T) {
+	s := transport.New("test-server", "1.0.0")
+	s.SetAllowedOrigins("https://app.example.com")
+	srv := httptest.NewServer(transport.NewHTTPHandler(s))
+	t.Cleanup(srv.Close)
+
+	for origin, want := range map[string]int{
+		"":                        http.StatusOK,
+		"http://localhost:3000":   http.StatusOK,
+		"http://127.0.0.1":        http.StatusOK,
+		"https://app.example.com": http.StatusOK,
+		"https://evil.example":    http.StatusForbidden,
+	} {
+		req, _ := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`))
+		req.Header.Set("Content-Type", "application/json")
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("Origin %q: status = %d, want %d", origin, resp.StatusCode, want)
+		}
+	}
+}
+
+func TestHTTPAuthenticatesSessions(t *testing.T) {
+	srv := httptest.NewServer(transport.NewHTTPHandler(authServer(t)))
+	t.Cleanup(srv.Close)
+	send := func(token, session, body string) *http.Response {
+		req, _ := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		if session != "" {
+			req.Header.Set(transport.SessionHeader, session)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+	initialize := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`
+	if resp := send("", "", initialize); resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("no token: status = %d, want 401", resp.StatusCode)
+	}
+	resp := send("r", "", initialize)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("initialize: status = %d", resp.StatusCode)
+	}
+	session := resp.Header.Get(transport.SessionHeader)
+
+	resp = send("r", session, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"wipe"}}`)
+	var out types.JSONRPCResponse
+	json.NewDecoder(resp.Body).Decode(&out)
+	if out.Error == nil || out.Error.Code != auth.CodeForbidden {
+		t.Errorf("reader wipe = %+v, want %d", out.Error, auth.CodeForbidden)
+	}
+	if resp := send("wrong", session, `{"jsonrpc":"2.0","id":3,"method":"ping"}`); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("unknown token: status = %d, want 401", resp.StatusCode)
+	}
+}
+
+func TestHTTPFailedInitializeLeavesNoSession(t *testing.T) {
+	s := transport.New("test-server", "1.0.0")
+	hh := transport.NewHTTPHandler(s)
+	srv := httptest.NewServer(hh)
+	t.Cleanup(srv.Close)
+
+	resp := post(t, srv.URL, "", "application/json", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":5}}`)
+	var out types.JSONRPCResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil || out.Error == nil {
+		t.Fatalf("response = %+v, %v, want an error", out, err)
+	}
+	if sid := resp.Header.Get(transport.SessionHeader); sid != "" {
+		t.Errorf("failed initialize advertised session %q", sid)
+	}
+	if n := hh.Sessions().Count(); n != 0 {
+		t.Errorf("sessions = %d, want 0", n)
+	}
+	if s.Peers() != 0 {
+		t.Errorf("peers = %d, want 0", s.Peers())
+	}
+}
@@ -0,0 +1,178 @@
+package transport_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/orchestra-mcp/mcp/src/transport"
+)
+
+// rawPipe runs Serve over pipes and returns the input and raw output lines,
+// so batch replies (JSON arrays) can be inspected.
+func rawPipe(t *testing.T) (io.Writer, <-chan []byte) {
+	t.Helper()
+	s := transport.New("s", "1.0")
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	go func() {
+		s.Serve(inR, outW)
+		outW.Close()
+	}()
+	lines := make(chan []byte, 16)
+	go func() {
+		sc := bufio.NewScanner(outR)
+		for sc.Scan() {
+			lines <- append([]byte(nil), sc.Bytes()...)
+		}
+		close(lines)
+	}()
+	t.Cleanup(func() { inW.Close() })
+	return inW, lines
+}
+
+func nextRaw(t *testing.T, lines <-chan []byte) []byte {
+	t.Helper()
+	select {
+	case l, ok := <-lines:
+		if !ok {
+			t.Fatal("output closed")
+		}
+		return l
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for output")
+	}
+	return nil
+}
+
+// send writes one line and decodes the single reply.
+func send(t *testing.T, in io.Writer, lines <-chan []byte, line string) map[string]any {
+	t.Helper()
+	io.WriteString(in, line+"\n")
+	var m map[string]any
+	if raw := nextRaw(t, lines); json.Unmarshal(raw, &m) != nil {
+		t.Fatalf("reply is not an object: %s", raw)
+	}
+	return m
+}
+
+func errorCode(m map[string]any) float64 {
+	e, _ := m["error"].(map[string]any)
+	code, _ := e["code"].(float64)
+	return code
+}
+
+func TestConformanceErrors(t *testing.T) {
+	in, lines := rawPipe(t)
+	cases := []struct {
+		name, line string
+		code       float64
+		id         any
+	}{
+		{"malformed JSON", `{"jsonrpc":"2.0","id":1,`, -32700, nil},
+		{"empty batch", `[]`, -32600, nil},
+		{"not an object", `"ping"`, -32600, nil},
+		{"wrong version", `{"jsonrpc":"1.0","id":1,"method":"ping"}`, -32600, float64(1)},
+		{"missing version", `{"id":"a","method":"ping"}`, -32600, "a"},
+		{"missing method", `{"jsonrpc":"2.0","id":2}`, -32600, float64(2)},
+		{"bad id type", `{"jsonrpc":"2.0","id":true,"method":"ping"}`, -32600, nil},
+		{"null id", `{"jsonrpc":"2.0","id":null,"method":"ping"}`, -32600, nil},
+		{"scalar params", `{"jsonrpc":"2.0","id":3,"method":"ping","params":5}`, -32600, float64(3)},
+		{"unknown method", `{"jsonrpc":"2.0","id":4,"method":"nope"}`, -32601, float64(4)},
+	}
+	for _, c := range cases {
+		m := send(t, in, lines, c.line)
+		if errorCode(m) != c.code {
+			t.Errorf("%s: error = %v, want code %v", c.name, m["error"], c.code)
+		}
+		id, present := m["id"]
+		if !present || id != c.id {
+			t.Errorf("%s: id = %v (present %v), want %v", c.name, id, present, c.id)
+		}
+		if m["jsonrpc"] != "2.0" {
+			t.Errorf("%s: jsonrpc = %v", c.name, m["jsonrpc"])
+		}
+	}
+}
+
+func TestConformanceSuccessHasResult(t *testing.T) {
+	in, lines := rawPipe(t)
+	m := send(t, in, lines, `{"jsonrpc":"2.0","id":1,"method":"ping"}`)
+	if _, ok := m["result"]; !ok {
+		t.Errorf("ping reply lacks result: %v", m)
+	}
+	if _, ok := m["error"]; ok {
+		t.Errorf("ping reply has error: %v", m)
+	}
+}
+
+func TestConformanceNotificationsNeverAnswered(t *testing.T) {
+	in, lines := rawPipe(t)
+	io.WriteString(in, `{"jsonrpc":"2.0","method":"nope"}`+"\n")
+	io.WriteString(in, `{"jsonrpc":"2.0","method":"tools/call","params":{"name":"missing"}}`+"\n")
+	io.WriteString(in, `{"jsonrpc":"2.0","id":9,"result":{}}`+"\n") // a client response
+	io.WriteString(in, `[{"jsonrpc":"2.0","method":"notifications/initialized"}]`+"\n")
+	m := send(t, in, lines, `{"jsonrpc":"2.0","id":"last","method":"ping"}`)
+	if m["id"] != "last" {
+		t.Errorf("first reply = %v, want the ping", m)
+	}
+}
+
+func TestConformanceBatch(t *testing.T) {
+	in, lines := rawPipe(t)
+	io.WriteString(in, `[{"jsonrpc":"2.0","id":1,"method":"ping"},`+
+		`{"jsonrpc":"2.0","method":"notifications/initialized"},`+
+		`{"jsonrpc":"2.0","id":2,"method":"nope"},`+
+		`{"jsonrpc":"2.0","id":3,"method":"initialize","params":{}},`+
+		`42]`+"\n")
+	var replies []map[string]any
+	if raw := nextRaw(t, lines); json.Unmarshal(raw, &replies) != nil {
+		t.Fatalf("batch reply is not an array: %s", raw)
+	}
+	if len(replies) != 4 {
+		t.Fatalf("replies = %v, want 4", replies)
+	}
+	byID := map[any]map[string]any{}
+	for _, r := range replies {
+		byID[r["id"]] = r
+	}
+	if _, ok := byID[float64(1)]["result"]; !ok {
+		t.Errorf("ping = %v", byID[float64(1)])
+	}
+	if errorCode(byID[float64(2)]) != -32601 {
+		t.Errorf("unknown method = %v", byID[float64(2)])
+	}
+	if errorCode(byID[float64(3)]) != -32600 {
+		t.Errorf("batched initialize = %v", byID[float64(3)])
+	}
+	if errorCode(byID[nil]) != -32600 {
+		t.Errorf("invalid member = %v", byID[nil])
+	}
+}
+
+func TestHTTPBatchAndParseError(t *testing.T) {
+	srv := newHTTPServer(t)
+	resp := post(t, srv.URL, "", "application/json", `{not json`)
+	var out map[string]any
+	json.NewDecoder(resp.Body).Decode(&out)
+	if resp.StatusCode != http.StatusBadRequest || errorCode(out) != -32700 {
+		t.Errorf("parse error: status %d, body %v", resp.StatusCode, out)
+	}
+
+	init := post(t, srv.URL, "", "application/json", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`)
+	sid := init.Header.Get(transport.SessionHeader)
+	resp = post(t, srv.URL, sid, "application/json",
+		`[{"jsonrpc":"2.0","id":2,"method":"ping"},{"jsonrpc":"2.0","id":3,"method":"tools/list"}]`)
+	var replies []map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&replies); err != nil || len(replies) != 2 {
+		t.Errorf("batch replies = %v (%v)", replies, err)
+	}
+
+	resp = post(t, srv.URL, sid, "application/json", `[{"jsonrpc":"2.0","method":"notifications/initialized"}]`)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Errorf("notification-only batch status = %d, want 202", resp.StatusCode)
+	}
+}
@@ -0,0 +1,121 @@
+package transport_test
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/orchestra-mcp/mcp/src/transport"
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+func dummyTool(name string) types.Tool {
+	return types.Tool{
+		Definition: types.ToolDefinition{Name: name, InputSchema: types.InputSchema{Type: "object"}},
+		Handler:    func(map[string]any) (*types.ToolResult, error) { return nil, nil },
+	}
+}
+
+func TestListChangedAfterInitialize(t *testing.T) {
+	s := transport.New("s", "1.0")
+	s.RegisterTool(dummyTool("a"))
+	in, lines := stdioPipe(t, s)
+
+	io.WriteString(in, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`+"\n")
+	init := next(t, lines)
+	caps := init["result"].(map[string]any)["capabilities"].(map[string]any)
+	if caps["tools"].(map[string]any)["listChanged"] != true {
+		t.Errorf("tools.listChanged not advertised: %v", caps)
+	}
+	if s.Peers() != 1 {
+		t.Fatalf("peers = %d, want 1", s.Peers())
+	}
+
+	cases := []struct {
+		change func()
+		method string
+	}{
+		{func() { s.RegisterTool(dummyTool("b")) }, "notifications/tools/list_changed"},
+		{func() { s.UnregisterTool("b") }, "notifications/tools/list_changed"},
+		{func() {
+			s.RegisterPrompt(types.Prompt{Definition: types.PromptDefinition{Name: "p"}})
+		}, "notifications/prompts/list_changed"},
+		{func() {
+			s.RegisterResource(types.Resource{Definition: types.ResourceDefinition{URI: "x://r"}})
+		}, "notifications/resources/list_changed"},
+	}
+	for _, c := range cases {
+		c.change()
+		if msg := next(t, lines); msg["method"] != c.method {
+			t.Errorf("got %v, want %s", msg, c.method)
+		}
+	}
+
+	s.UnregisterTool("missing")
+	io.WriteString(in, `{"jsonrpc":"2.0","id":2,"method":"ping"}`+"\n")
+	if msg := next(t, lines); msg["id"] != float64(2) {
+		t.Errorf("removing an unknown tool must not notify, got %v", msg)
+	}
+}
+
+func TestListChangedInOrder(t *testing.T) {
+	s := transport.New("s", "1.0")
+	in, lines := stdioPipe(t, s)
+	io.WriteString(in, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`+"\n")
+	next(t, lines)
+
+	var want []string
+	for i := 0; i < 30; i++ {
+		switch i % 3 {
+		case 0:
+			s.RegisterTool(dummyTool(fmt.Sprintf("t%d", i)))
+			want = append(want, "notifications/tools/list_changed")
+		case 1:
+			s.RegisterPrompt(types.Prompt{Definition: types.PromptDefinition{Name: fmt.Sprintf("p%d", i)}})
+			want = append(want, "notifications/prompts/list_changed")
+		case 2:
+			s.RegisterResource(types.Resource{Definition: types.ResourceDefinition{URI: fmt.Sprintf("x://r%d", i)}})
+			want = append(want, "notifications/resources/list_changed")
+		}
+	}
+	for i, method := range want {
+		if msg := next(t, lines); msg["method"] != method {
+			t.Fatalf("notification %d = %v, want %s", i, msg["method"], method)
+		}
+	}
+}
+
+func TestPeerRemovedOnClose(t *testing.T) {
+	s := transport.New("s", "1.0")
+	m := transport.NewSSESessionManager()
+	sess := m.Create()
+	initialize(t, s, &recorder{session: sess.State}, `{}`)
+	if s.Peers() != 1 {
+		t.Fatalf("peers = %d, want 1", s.Peers())
+	}
+	m.Remove(sess.ID)
+	if s.Peers() != 0 {
+		t.Errorf("peers after close = %d, want 0", s.Peers())
+	}
+}
+
+func TestRegistryConcurrentAccess(t *testing.T) {
+	s := transport.New("s", "1.0")
+	w := newRecorder()
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("t%d", i)
+			s.RegisterTool(dummyTool(name))
+			s.UnregisterTool(name)
+		}(i)
+		go func() {
+			defer wg.Done()
+			s.HandleRequest(&types.JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "tools/list"}, w)
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,113 @@
+package transport_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/orchestra-mcp/mcp/src/logger"
+	"github.com/orchestra-mcp/mcp/src/transport"
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+func (r *recorder) messages() []types.LoggingMessageParams {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []types.LoggingMessageParams
+	for _, n := range r.notifications {
+		if n.Method == "notifications/message" {
+			out = append(out, n.Params.(types.LoggingMessageParams))
+		}
+	}
+	return out
+}
+
+func waitMessages(r *recorder, n int) []types.LoggingMessageParams {
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if m := r.messages(); len(m) >= n {
+			return m
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return r.messages()
+}
+
+func TestLoggingSetLevelAndNotify(t *testing.T) {
+	l := logger.New()
+	s := transport.New("s", "1.0")
+	s.SetLogger(l)
+	w := newRecorder()
+	if res := initialize(t, s, w, `{"protocolVersion":"2025-06-18"}`); res.Capabilities.Logging == nil {
+		t.Fatal("logging capability not advertised")
+	}
+
+	l.Log(logger.LevelDebug, "engine", "below default level", "session", "test")
+	l.Log(logger.LevelInfo, "engine", "running", "addr", ":50051", "session", "test")
+	msgs := waitMessages(w, 1)
+	if len(msgs) != 1 || msgs[0].Level != "info" || msgs[0].Logger != "engine" {
+		t.Fatalf("messages = %+v", msgs)
+	}
+	if data := msgs[0].Data.(map[string]any); data["message"] != "running" || data["addr"] != ":50051" {
+		t.Errorf("data = %v", data)
+	}
+
+	s.HandleRequest(&types.JSONRPCRequest{
+		JSONRPC: "2.0", ID: 2, Method: "logging/setLevel", Params: json.RawMessage(`{"level":"error"}`),
+	}, w)
+	if resp := w.last(t); resp.Error != nil {
+		t.Fatalf("setLevel error: %v", resp.Error)
+	}
+	l.Log(logger.LevelWarning, "memory", "suppressed", "session", "test")
+	l.Log(logger.LevelCritical, "memory", "sent", "session", "test")
+	msgs = waitMessages(w, 2)
+	if len(msgs) != 2 || msgs[1].Level != "critical" {
+		t.Errorf("messages = %+v", msgs)
+	}
+
+	w.Session().Close()
+	l.Log(logger.LevelEmergency, "memory", "after close", "session", "test")
+	time.Sleep(20 * time.Millisecond)
+	if got := len(w.messages()); got != 2 {
+		t.Errorf("closed session still receives logs: %d", got)
+	}
+}
+
+func TestLoggingScopedToSession(t *testing.T) {
+	l := logger.New()
+	s := transport.New("s", "1.0")
+	s.SetLogger(l)
+	remote, other := newRecorder(), &recorder{session: transport.NewSession("other")}
+	initialize(t, s, remote, `{"protocolVersion":"2025-06-18"}`)
+	initialize(t, s, other, `{"protocolVersion":"2025-06-18"}`)
+
+	l.Log(logger.LevelInfo, "server", "process-wide")
+	l.Log(logger.LevelInfo, "server", "for other", "session", "other")
+	for i := 0; i < 50; i++ {
+		l.Log(logger.LevelInfo, "server", fmt.Sprint(i), "session", "test")
+	}
+	msgs := waitMessages(remote, 50)
+	if len(msgs) != 50 {
+		t.Fatalf("remote session got %d messages, want its own 50", len(msgs))
+	}
+	for i, m := range msgs {
+		if got := m.Data.(map[string]any)["message"]; got != fmt.Sprint(i) {
+			t.Fatalf("message %d = %v: out of order", i, got)
+		}
+	}
+	if msgs := waitMessages(other, 1); len(msgs) != 1 || msgs[0].Data.(map[string]any)["message"] != "for other" {
+		t.Errorf("other session messages = %+v", msgs)
+	}
+}
+
+func TestLoggingSetLevelRejectsUnknown(t *testing.T) {
+	s := transport.New("s", "1.0")
+	w := newRecorder()
+	s.HandleRequest(&types.JSONRPCRequest{
+		JSONRPC: "2.0", ID: 1, Method: "logging/setLevel", Params: json.RawMessage(`{"level":"loud"}`),
+	}, w)
+	if resp := w.last(t); resp.Error == nil || resp.Error.Code != -32602 {
+		t.Errorf("response = %+v", resp)
+	}
+}
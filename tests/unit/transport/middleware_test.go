@@ -0,0 +1,256 @@
+package transport_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orchestra-mcp/mcp/src/auth"
+	h "github.com/orchestra-mcp/mcp/src/helpers"
+	"github.com/orchestra-mcp/mcp/src/metrics"
+	"github.com/orchestra-mcp/mcp/src/transport"
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+func middlewareServer() *transport.MCPServer {
+	s := transport.New("s", "1.0")
+	s.RegisterTool(types.Tool{
+		Definition: types.ToolDefinition{Name: "echo", InputSchema: types.InputSchema{
+			Type:       "object",
+			Properties: map[string]any{"text": map[string]any{"type": "string"}},
+		}},
+		Handler: func(args map[string]any) (*types.ToolResult, error) {
+			return &types.ToolResult{Content: []types.ContentBlock{{Type: "text", Text: h.GetString(args, "text")}}}, nil
+		},
+	})
+	s.RegisterTool(types.Tool{
+		Definition: types.ToolDefinition{Name: "boom", InputSchema: types.InputSchema{Type: "object"}},
+		Handler:    func(map[string]any) (*types.ToolResult, error) { panic("kaboom") },
+	})
+	s.RegisterTool(types.NewContextTool(
+		types.ToolDefinition{Name: "slow", InputSchema: types.InputSchema{Type: "object"}},
+		func(ctx context.Context, _ map[string]any) (*types.ToolResult, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	))
+	return s
+}
+
+// tag appends name to the order slice before and after the call.
+func tag(order *[]string, name string) transport.Middleware {
+	return func(next transport.ToolHandler) transport.ToolHandler {
+		return func(ctx context.Context, call *transport.ToolCall) (*types.ToolResult, error) {
+			*order = append(*order, name+">")
+			r, err := next(ctx, call)
+			*order = append(*order, "<"+name)
+			return r, err
+		}
+	}
+}
+
+func TestMiddlewareOrder(t *testing.T) {
+	s := middlewareServer()
+	var order []string
+	s.Use(tag(&order, "a"), tag(&order, "b"))
+	if _, err := s.CallTool(context.Background(), &transport.ToolCall{Name: "echo"}); err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	if got := strings.Join(order, " "); got != "a> b> <b <a" {
+		t.Errorf("order = %q", got)
+	}
+}
+
+func TestMiddlewareRunsForJSONRPCCalls(t *testing.T) {
+	s := middlewareServer()
+	var seen *transport.ToolCall
+	s.Use(func(next transport.ToolHandler) transport.ToolHandler {
+		return func(ctx context.Context, call *transport.ToolCall) (*types.ToolResult, error) {
+			seen = call
+			return next(ctx, call)
+		}
+	})
+	w := newRecorder()
+	s.HandleRequest(&types.JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "tools/call",
+		Params: json.RawMessage(`{"name":"echo","arguments":{"text":"hi"}}`)}, w)
+	if seen == nil || seen.Definition.Name != "echo" || seen.Session != w.session {
+		t.Fatalf("middleware saw %+v", seen)
+	}
+	if w.last(t).Result == nil {
+		t.Error("missing result")
+	}
+}
+
+func TestMiddlewareCanReject(t *testing.T) {
+	s := middlewareServer()
+	s.Use(func(next transport.ToolHandler) transport.ToolHandler {
+		return func(ctx context.Context, call *transport.ToolCall) (*types.ToolResult, error) {
+			return nil, errors.New("denied by policy")
+		}
+	})
+	w := newRecorder()
+	s.HandleRequest(&types.JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "tools/call",
+		Params: json.RawMessage(`{"name":"echo","arguments":{}}`)}, w)
+	if resp := w.last(t); resp.Error == nil || resp.Error.Code != -32000 || resp.Error.Message != "denied by policy" {
+		t.Errorf("response = %+v", resp)
+	}
+}
+
+func TestCallToolErrors(t *testing.T) {
+	s := middlewareServer()
+	var seen []error
+	s.Use(func(next transport.ToolHandler) transport.ToolHandler {
+		return func(ctx context.Context, call *transport.ToolCall) (*types.ToolResult, error) {
+			r, err := next(ctx, call)
+			seen = append(seen, err)
+			return r, err
+		}
+	})
+	_, err := s.CallTool(context.Background(), &transport.ToolCall{Name: "nope"})
+	if !errors.Is(err, transport.ErrUnknownTool) {
+		t.Errorf("unknown tool err = %v", err)
+	}
+	_, err = s.CallTool(context.Background(), &transport.ToolCall{Name: "echo", Arguments: map[string]any{"text": 1}})
+	var invalid h.ValidationErrors
+	if !errors.As(err, &invalid) || transport.ErrorCode(err) != -32602 {
+		t.Errorf("invalid args err = %v", err)
+	}
+	// Unknown tools never reach the chain; rejected arguments do.
+	if len(seen) != 1 || !errors.As(seen[0], &invalid) {
+		t.Errorf("middleware saw %v", seen)
+	}
+}
+
+func TestAuthorizeBeforeChain(t *testing.T) {
+	s := authServer(t)
+	var reached []string
+	s.Use(func(next transport.ToolHandler) transport.ToolHandler {
+		return func(ctx context.Context, call *transport.ToolCall) (*types.ToolResult, error) {
+			reached = append(reached, call.Name)
+			call.Principal = &auth.Principal{Name: "forged", Scopes: []auth.Scope{auth.ScopeAdmin}}
+			return next(ctx, call)
+		}
+	})
+	var rejected []error
+	s.OnReject(func(_ context.Context, call *transport.ToolCall, err error) {
+		if call.RedactedArguments()["token"] != transport.Redacted {
+			t.Errorf("rejected call arguments not redacted: %v", call.RedactedArguments())
+		}
+		rejected = append(rejected, err)
+	})
+	reader := &auth.Principal{Name: "reader", Scopes: []auth.Scope{auth.ScopeRead}}
+	args := map[string]any{"token": "s3cret"}
+
+	if _, err := s.CallTool(context.Background(), &transport.ToolCall{Name: "wipe", Principal: reader, Arguments: args}); !errors.Is(err, auth.ErrForbidden) {
+		t.Errorf("reader wipe err = %v, want forbidden", err)
+	}
+	if _, err := s.CallTool(context.Background(), &transport.ToolCall{Name: "nope", Principal: reader, Arguments: args}); !errors.Is(err, transport.ErrUnknownTool) {
+		t.Errorf("unknown tool err = %v", err)
+	}
+	if _, err := s.CallTool(context.Background(), &transport.ToolCall{Name: "peek", Principal: reader}); err != nil {
+		t.Errorf("reader peek err = %v", err)
+	}
+	if len(reached) != 1 || reached[0] != "peek" {
+		t.Errorf("middleware ran for %v, want only the authorized call", reached)
+	}
+	if len(rejected) != 2 || !errors.Is(rejected[0], auth.ErrForbidden) || !errors.Is(rejected[1], transport.ErrUnknownTool) {
+		t.Errorf("rejections = %v", rejected)
+	}
+}
+
+func TestRecoverMiddleware(t *testing.T) {
+	s := middlewareServer()
+	s.Use(transport.Recover())
+	_, err := s.CallTool(context.Background(), &transport.ToolCall{Name: "boom"})
+	if err == nil || !strings.Contains(err.Error(), "tool boom panicked: kaboom") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestTimeoutMiddleware(t *testing.T) {
+	s := middlewareServer()
+	s.Use(transport.Timeout(20 * time.Millisecond))
+	_, err := s.CallTool(context.Background(), &transport.ToolCall{Name: "slow"})
+	if err == nil || !strings.Contains(err.Error(), "timed out after 20ms") {
+		t.Errorf("err = %v", err)
+	}
+	if _, err := s.CallTool(context.Background(), &transport.ToolCall{Name: "echo"}); err != nil {
+		t.Errorf("fast call: %v", err)
+	}
+}
+
+func TestTimingMiddleware(t *testing.T) {
+	s := middlewareServer()
+	var name string
+	var elapsed time.Duration = -1
+	s.Use(transport.Timing(func(call *transport.ToolCall, d time.Duration, err error) {
+		name, elapsed = call.Name, d
+	}))
+	if _, err := s.CallTool(context.Background(), &transport.ToolCall{Name: "echo"}); err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	if name != "echo" || elapsed < 0 {
+		t.Errorf("reported %q after %v", name, elapsed)
+	}
+}
+
+func TestRedactMiddleware(t *testing.T) {
+	s := middlewareServer()
+	var logged map[string]any
+	s.Use(transport.Redact(), transport.Timing(func(call *transport.ToolCall, _ time.Duration, _ error) {
+		logged = call.RedactedArguments()
+	}))
+	s.RegisterTool(types.Tool{
+		Definition: types.ToolDefinition{Name: "login", InputSchema: types.InputSchema{Type: "object", AdditionalProperties: ptr(true)}},
+		Handler: func(args map[string]any) (*types.ToolResult, error) {
+			if args["password"] != "hunter2" {
+				t.Errorf("handler got password %v", args["password"])
+			}
+			return nil, nil
+		},
+	})
+	args := map[string]any{
+		"user":     "ada",
+		"password": "hunter2",
+		"nested":   map[string]any{"GitHub_Token": "ghp_x"},
+		"list":     []any{map[string]any{"api_key": "k"}},
+	}
+	if _, err := s.CallTool(context.Background(), &transport.ToolCall{Name: "login", Arguments: args}); err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	if logged["user"] != "ada" || logged["password"] != transport.Redacted {
+		t.Errorf("logged = %v", logged)
+	}
+	if logged["nested"].(map[string]any)["GitHub_Token"] != transport.Redacted {
+		t.Errorf("nested = %v", logged["nested"])
+	}
+	if logged["list"].([]any)[0].(map[string]any)["api_key"] != transport.Redacted {
+		t.Errorf("list = %v", logged["list"])
+	}
+	if args["password"] != "hunter2" {
+		t.Error("redaction modified the caller's arguments")
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestMetricsMiddleware(t *testing.T) {
+	s := middlewareServer()
+	s.Use(transport.Metrics())
+	calls := metrics.ToolCalls.Value("echo")
+	invalid := metrics.ToolErrors.Value("-32602")
+	s.CallTool(context.Background(), &transport.ToolCall{Name: "echo"})
+	s.CallTool(context.Background(), &transport.ToolCall{Name: "echo", Arguments: map[string]any{"text": 1}})
+	if got := metrics.ToolCalls.Value("echo") - calls; got != 2 {
+		t.Errorf("calls = %v, want 2", got)
+	}
+	if got := metrics.ToolErrors.Value("-32602") - invalid; got != 1 {
+		t.Errorf("-32602 errors = %v, want 1", got)
+	}
+	if metrics.ToolDuration.Count("echo") < 2 {
+		t.Error("latency not observed")
+	}
+}
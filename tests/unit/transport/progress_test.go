@@ -0,0 +1,79 @@
+package transport_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	h "github.com/orchestra-mcp/mcp/src/helpers"
+	"github.com/orchestra-mcp/mcp/src/transport"
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+func progressServer() *transport.MCPServer {
+	s := transport.New("s", "1.0")
+	s.RegisterTool(types.NewContextTool(
+		types.ToolDefinition{Name: "steps", InputSchema: types.InputSchema{Type: "object"}},
+		func(ctx context.Context, args map[string]any) (*types.ToolResult, error) {
+			h.ReportProgress(ctx, 1, 2, "half")
+			h.ReportProgress(ctx, 2, 2, "done")
+			return h.TextResult("ok"), nil
+		},
+	))
+	return s
+}
+
+func TestProgressNotificationsOverStdio(t *testing.T) {
+	in, lines := stdioPipe(t, progressServer())
+	io.WriteString(in, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"steps","_meta":{"progressToken":"tok"}}}`+"\n")
+
+	for i, want := range []float64{1, 2} {
+		msg := next(t, lines)
+		if msg["method"] != "notifications/progress" {
+			t.Fatalf("message %d = %v, want progress notification", i, msg)
+		}
+		params, _ := msg["params"].(map[string]any)
+		if params["progressToken"] != "tok" || params["progress"] != want || params["total"] != float64(2) {
+			t.Errorf("progress params = %v", params)
+		}
+	}
+	if resp := next(t, lines); resp["id"] != float64(1) || resp["result"] == nil {
+		t.Errorf("expected final result, got %v", resp)
+	}
+}
+
+func TestNoProgressWithoutToken(t *testing.T) {
+	s := progressServer()
+	w := newRecorder()
+	s.HandleRequest(&types.JSONRPCRequest{
+		JSONRPC: "2.0", ID: 1, Method: "tools/call",
+		Params: json.RawMessage(`{"name":"steps"}`),
+	}, w)
+	if len(w.notifications) != 0 {
+		t.Errorf("got %d notifications without a progress token", len(w.notifications))
+	}
+	if w.last(t).Result == nil {
+		t.Error("missing result")
+	}
+}
+
+func TestSSEWriterNotification(t *testing.T) {
+	m := transport.NewSSESessionManager()
+	sess := m.Create()
+	defer m.Remove(sess.ID)
+	if err := transport.NewSSEWriter(sess).WriteNotification("notifications/progress", types.ProgressParams{ProgressToken: 7, Progress: 1}); err != nil {
+		t.Fatalf("WriteNotification: %v", err)
+	}
+	evs := sess.EventsAfter(0)
+	if len(evs) != 1 {
+		t.Fatalf("events = %d, want 1", len(evs))
+	}
+	var got types.JSONRPCNotification
+	if err := json.Unmarshal(evs[0].Data, &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got.Method != "notifications/progress" || got.JSONRPC != "2.0" {
+		t.Errorf("notification = %+v", got)
+	}
+}
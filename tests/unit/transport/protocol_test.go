@@ -0,0 +1,124 @@
+package transport_test
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/orchestra-mcp/mcp/src/transport"
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+// recorder is an in-memory ResponseWriter that captures every response.
+type recorder struct {
+	mu            sync.Mutex
+	session       *transport.Session
+	responses     []types.JSONRPCResponse
+	notifications []types.JSONRPCNotification
+	requests      []types.JSONRPCOutgoingRequest
+}
+
+func newRecorder() *recorder { return &recorder{session: transport.NewSession("test")} }
+
+func (r *recorder) Session() *transport.Session { return r.session }
+
+func (r *recorder) WriteResult(id, result any) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.responses = append(r.responses, types.JSONRPCResponse{JSONRPC: "2.0", ID: id, Result: result})
+	return nil
+}
+
+func (r *recorder) WriteError(id any, code int, msg string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.responses = append(r.responses, types.JSONRPCResponse{
+		JSONRPC: "2.0", ID: id, Error: &types.JSONRPCError{Code: code, Message: msg},
+	})
+	return nil
+}
+
+func (r *recorder) WriteNotification(method string, params any) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.notifications = append(r.notifications, types.JSONRPCNotification{JSONRPC: "2.0", Method: method, Params: params})
+	return nil
+}
+
+func (r *recorder) WriteRequest(id any, method string, params any) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, types.JSONRPCOutgoingRequest{JSONRPC: "2.0", ID: id, Method: method, Params: params})
+	return nil
+}
+
+func (r *recorder) last(t *testing.T) types.JSONRPCResponse {
+	t.Helper()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.responses) == 0 {
+		t.Fatal("no response written")
+	}
+	return r.responses[len(r.responses)-1]
+}
+
+func initialize(t *testing.T, s *transport.MCPServer, w *recorder, params string) types.InitializeResult {
+	t.Helper()
+	s.HandleRequest(&types.JSONRPCRequest{
+		JSONRPC: "2.0", ID: 1, Method: "initialize", Params: json.RawMessage(params),
+	}, w)
+	res, ok := w.last(t).Result.(types.InitializeResult)
+	if !ok {
+		t.Fatalf("initialize result = %#v", w.last(t))
+	}
+	return res
+}
+
+func TestNegotiateVersion(t *testing.T) {
+	cases := map[string]string{
+		"2025-06-18": "2025-06-18",
+		"2025-03-26": "2025-03-26",
+		"2024-11-05": "2024-11-05",
+		"2099-01-01": "2025-06-18",
+		"2025-05-01": "2025-03-26",
+		"2023-01-01": "2025-06-18",
+		"":           "2025-06-18",
+	}
+	for requested, want := range cases {
+		if got := transport.NegotiateVersion(requested); got != want {
+			t.Errorf("NegotiateVersion(%q) = %q, want %q", requested, got, want)
+		}
+	}
+}
+
+func TestInitializeStoresSession(t *testing.T) {
+	s := transport.New("s", "1.0")
+	w := newRecorder()
+	res := initialize(t, s, w, `{"protocolVersion":"2025-03-26","capabilities":{"sampling":{}},"clientInfo":{"name":"editor","version":"2"}}`)
+	if res.ProtocolVersion != "2025-03-26" {
+		t.Errorf("protocolVersion = %q", res.ProtocolVersion)
+	}
+	sess := w.Session()
+	if sess.ProtocolVersion() != "2025-03-26" {
+		t.Errorf("session version = %q", sess.ProtocolVersion())
+	}
+	if sess.ClientCapabilities().Sampling == nil {
+		t.Error("sampling capability not stored")
+	}
+	if sess.ClientInfo().Name != "editor" {
+		t.Errorf("client name = %q", sess.ClientInfo().Name)
+	}
+	if !sess.Supports(transport.FeatureToolAnnotations) {
+		t.Error("2025-03-26 should support tool annotations")
+	}
+	if sess.Supports(transport.FeatureStructuredOutput) {
+		t.Error("2025-03-26 should not support structured output")
+	}
+}
+
+func TestSessionNilSafe(t *testing.T) {
+	var sess *transport.Session
+	if sess.ProtocolVersion() != "" || sess.Supports(transport.FeatureElicitation) {
+		t.Error("nil session should report nothing")
+	}
+}
@@ -0,0 +1,166 @@
+package transport_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orchestra-mcp/mcp/src/transport"
+)
+
+func publishN(t *testing.T, sess *transport.SSESession, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		if _, err := sess.Publish([]byte(fmt.Sprintf(`{"n":%d}`, i+1))); err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+	}
+}
+
+// streamOnce runs Stream with a cancelled context, which writes whatever is
+// pending and returns.
+func streamOnce(t *testing.T, sess *transport.SSESession, lastEventID string) string {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var buf bytes.Buffer
+	if err := sess.Stream(ctx, &buf, func() error { return nil }, lastEventID); err != nil {
+		t.Fatalf("Stream: %v", err)
+	}
+	return buf.String()
+}
+
+func TestSSEEventIDsAreMonotonic(t *testing.T) {
+	m := transport.NewSSESessionManager()
+	sess := m.Create()
+	defer m.Remove(sess.ID)
+	publishN(t, sess, 3)
+	evs := sess.EventsAfter(1)
+	if len(evs) != 2 || evs[0].ID != 2 || evs[1].ID != 3 {
+		t.Errorf("events after 1 = %+v", evs)
+	}
+}
+
+func TestSSEReplayBufferIsBounded(t *testing.T) {
+	m := transport.NewSSESessionManager()
+	sess := m.Create()
+	defer m.Remove(sess.ID)
+	publishN(t, sess, transport.ReplayBufferSize+10)
+	evs := sess.EventsAfter(0)
+	if len(evs) != transport.ReplayBufferSize {
+		t.Fatalf("buffered = %d, want %d", len(evs), transport.ReplayBufferSize)
+	}
+	if evs[0].ID != 11 {
+		t.Errorf("oldest ID = %d, want 11", evs[0].ID)
+	}
+}
+
+func TestSSEStreamResumesAfterLastEventID(t *testing.T) {
+	m := transport.NewSSESessionManager()
+	sess := m.Create()
+	defer m.Remove(sess.ID)
+	publishN(t, sess, 3)
+	out := streamOnce(t, sess, "1")
+	if strings.Contains(out, "id: 1\n") || !strings.Contains(out, "id: 2\nevent: message\ndata: {\"n\":2}\n\n") || !strings.Contains(out, "id: 3\n") {
+		t.Errorf("stream = %q", out)
+	}
+}
+
+func TestSSEStreamStartsAfterDelivered(t *testing.T) {
+	m := transport.NewSSESessionManager()
+	sess := m.Create()
+	defer m.Remove(sess.ID)
+	publishN(t, sess, 2)
+	if out := streamOnce(t, sess, ""); !strings.Contains(out, "id: 2\n") {
+		t.Fatalf("first stream = %q", out)
+	}
+	publishN(t, sess, 1)
+	out := streamOnce(t, sess, "")
+	if strings.Contains(out, "id: 2\n") || !strings.Contains(out, "id: 3\n") {
+		t.Errorf("second stream = %q", out)
+	}
+	// An explicit Last-Event-ID still replays already delivered events.
+	if out := streamOnce(t, sess, "0"); !strings.Contains(out, "id: 1\n") {
+		t.Errorf("replay = %q", out)
+	}
+}
+
+func waitCount(t *testing.T, m *transport.SSESessionManager, want int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for m.Count() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("sessions = %d, want %d", m.Count(), want)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestSSESessionExpiresWhenIdle(t *testing.T) {
+	m := transport.NewSSESessionManager()
+	m.SetTTL(20 * time.Millisecond)
+	sess := m.Create()
+	waitCount(t, m, 0)
+	if sess.Context().Err() == nil {
+		t.Error("expired session was not closed")
+	}
+	if _, err := sess.Publish([]byte(`{}`)); err == nil {
+		t.Error("Publish on an expired session succeeded")
+	}
+}
+
+func TestSSESessionKeptWhileStreaming(t *testing.T) {
+	m := transport.NewSSESessionManager()
+	m.SetTTL(50 * time.Millisecond)
+	sess := m.Create()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = sess.Stream(ctx, &bytes.Buffer{}, func() error { return nil }, "")
+	}()
+	time.Sleep(150 * time.Millisecond) // several TTLs with the stream attached
+	if m.Count() != 1 {
+		t.Fatal("session expired while a stream was open")
+	}
+	cancel()
+	<-done
+	waitCount(t, m, 0)
+}
+
+func TestHTTPStreamHonorsLastEventID(t *testing.T) {
+	hh := transport.NewHTTPHandler(transport.New("test-server", "1.0.0"))
+	srv := httptest.NewServer(hh)
+	t.Cleanup(srv.Close)
+	resp := post(t, srv.URL, "", "application/json, text/event-stream",
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`)
+	id := resp.Header.Get(transport.SessionHeader)
+	sess, ok := hh.Sessions().Get(id)
+	if !ok {
+		t.Fatal("session not registered")
+	}
+	publishN(t, sess, 3)
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set(transport.SessionHeader, id)
+	req.Header.Set("Last-Event-ID", "2")
+	stream, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	defer stream.Body.Close()
+	line, err := bufio.NewReader(stream.Body).ReadString('\n')
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if line != "id: 3\n" {
+		t.Errorf("first line = %q, want id: 3", line)
+	}
+}
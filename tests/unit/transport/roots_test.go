@@ -0,0 +1,116 @@
+package transport_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	h "github.com/orchestra-mcp/mcp/src/helpers"
+	"github.com/orchestra-mcp/mcp/src/transport"
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+func rootsServer() *transport.MCPServer {
+	s := transport.New("s", "1.0")
+	s.RegisterTool(types.NewContextTool(
+		types.ToolDefinition{Name: "roots", InputSchema: types.InputSchema{Type: "object"}},
+		func(ctx context.Context, args map[string]any) (*types.ToolResult, error) {
+			var names []string
+			for _, r := range h.Roots(ctx) {
+				names = append(names, r.Name)
+			}
+			return h.TextResult(strings.Join(names, ",")), nil
+		},
+	))
+	return s
+}
+
+// answerRoots reads a roots/list request and replies with the given roots.
+func answerRoots(t *testing.T, in io.Writer, lines <-chan map[string]any, roots string) {
+	t.Helper()
+	req := next(t, lines)
+	if req["method"] != "roots/list" {
+		t.Fatalf("expected roots/list, got %v", req)
+	}
+	id, _ := json.Marshal(req["id"])
+	io.WriteString(in, `{"jsonrpc":"2.0","id":`+string(id)+`,"result":{"roots":`+roots+`}}`+"\n")
+}
+
+// waitRoots calls the roots tool until it sees want. Roots are stored
+// asynchronously once the client's reply arrives.
+func waitRoots(t *testing.T, in io.Writer, lines <-chan map[string]any, want string) {
+	t.Helper()
+	var got string
+	for i := 0; i < 50; i++ {
+		io.WriteString(in, `{"jsonrpc":"2.0","id":9,"method":"tools/call","params":{"name":"roots"}}`+"\n")
+		if got, _ = toolText(t, next(t, lines)); got == want {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Errorf("roots = %s, want %s", got, want)
+}
+
+func TestRootsListedAfterInitializedAndOnChange(t *testing.T) {
+	in, lines := stdioPipe(t, rootsServer())
+	io.WriteString(in, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{"roots":{"listChanged":true}}}}`+"\n")
+	next(t, lines)
+	io.WriteString(in, `{"jsonrpc":"2.0","method":"notifications/initialized"}`+"\n")
+	answerRoots(t, in, lines, `[{"uri":"file:///a","name":"a"}]`)
+	waitRoots(t, in, lines, "a")
+
+	io.WriteString(in, `{"jsonrpc":"2.0","method":"notifications/roots/list_changed"}`+"\n")
+	answerRoots(t, in, lines, `[{"uri":"file:///a","name":"a"},{"uri":"file:///b","name":"b"}]`)
+	waitRoots(t, in, lines, "a,b")
+}
+
+func TestNoRootsRequestWithoutCapability(t *testing.T) {
+	s := rootsServer()
+	w := newRecorder()
+	initialize(t, s, w, `{"protocolVersion":"2025-06-18","capabilities":{}}`)
+	s.HandleRequest(&types.JSONRPCRequest{JSONRPC: "2.0", Method: "notifications/initialized"}, w)
+	if len(w.requests) != 0 {
+		t.Errorf("sent %v to a client without roots", w.requests)
+	}
+}
+
+func TestRemoteRootsLimitedToRootDirs(t *testing.T) {
+	allowed := t.TempDir()
+	s := rootsServer()
+	s.SetRootDirs(allowed)
+	w := newRecorder()
+	initialize(t, s, w, `{"protocolVersion":"2025-06-18","capabilities":{"roots":{}}}`)
+	s.HandleRequest(&types.JSONRPCRequest{JSONRPC: "2.0", Method: "notifications/initialized"}, w)
+
+	var id any
+	for i := 0; i < 100 && id == nil; i++ {
+		w.mu.Lock()
+		if len(w.requests) > 0 {
+			id = w.requests[0].ID
+		}
+		w.mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+	}
+	if id == nil {
+		t.Fatal("no roots/list request")
+	}
+	rawID, _ := json.Marshal(id)
+	reply := `{"jsonrpc":"2.0","id":` + string(rawID) + `,"result":{"roots":[` +
+		`{"uri":"file:///etc","name":"etc"},{"uri":"file://` + allowed + `/../..","name":"up"},` +
+		`{"uri":"file://` + allowed + `/proj","name":"proj"}]}}`
+	s.HandleMessage(context.Background(), []byte(reply), w)
+
+	for i := 0; i < 100; i++ {
+		if roots := w.session.Roots(); len(roots) > 0 {
+			if len(roots) != 1 || roots[0].Name != "proj" {
+				t.Errorf("roots = %+v, want only proj", roots)
+			}
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("allowed root not stored")
+}
@@ -0,0 +1,146 @@
+package transport_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	h "github.com/orchestra-mcp/mcp/src/helpers"
+	"github.com/orchestra-mcp/mcp/src/transport"
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+func samplingServer() *transport.MCPServer {
+	s := transport.New("s", "1.0")
+	s.RegisterTool(types.NewContextTool(
+		types.ToolDefinition{Name: "ask", InputSchema: types.InputSchema{Type: "object"}},
+		func(ctx context.Context, args map[string]any) (*types.ToolResult, error) {
+			res, err := h.Sample(ctx, types.CreateMessageParams{
+				Messages:  []types.SamplingMessage{{Role: "user", Content: types.ContentBlock{Type: "text", Text: "hi"}}},
+				MaxTokens: 10,
+			})
+			if err != nil {
+				return h.ErrorResult(err.Error()), nil
+			}
+			return h.TextResult(res.Content.Text), nil
+		},
+	))
+	return s
+}
+
+// samplingClient initializes a stdio session that declares sampling and
+// calls the ask tool, returning the outbound sampling request.
+func samplingClient(t *testing.T) (io.Writer, <-chan map[string]any, map[string]any) {
+	t.Helper()
+	in, lines := stdioPipe(t, samplingServer())
+	io.WriteString(in, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{"sampling":{}}}}`+"\n")
+	next(t, lines)
+	io.WriteString(in, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"ask"}}`+"\n")
+	req := next(t, lines)
+	if req["method"] != "sampling/createMessage" {
+		t.Fatalf("expected sampling request, got %v", req)
+	}
+	return in, lines, req
+}
+
+func toolText(t *testing.T, resp map[string]any) (string, bool) {
+	t.Helper()
+	res, _ := resp["result"].(map[string]any)
+	content, _ := res["content"].([]any)
+	if len(content) == 0 {
+		t.Fatalf("no content in %v", resp)
+	}
+	block, _ := content[0].(map[string]any)
+	isErr, _ := res["isError"].(bool)
+	return block["text"].(string), isErr
+}
+
+func TestSamplingRoundTrip(t *testing.T) {
+	in, lines, req := samplingClient(t)
+	params, _ := req["params"].(map[string]any)
+	if params["maxTokens"] != float64(10) {
+		t.Errorf("params = %v", params)
+	}
+	id, _ := json.Marshal(req["id"])
+	io.WriteString(in, `{"jsonrpc":"2.0","id":`+string(id)+`,"result":{"role":"assistant","content":{"type":"text","text":"hello"},"model":"m"}}`+"\n")
+
+	resp := next(t, lines)
+	if resp["id"] != float64(2) {
+		t.Fatalf("expected tool result, got %v", resp)
+	}
+	if text, isErr := toolText(t, resp); text != "hello" || isErr {
+		t.Errorf("tool text = %q (isError %v), want hello", text, isErr)
+	}
+}
+
+func TestSamplingClientError(t *testing.T) {
+	in, lines, req := samplingClient(t)
+	id, _ := json.Marshal(req["id"])
+	io.WriteString(in, `{"jsonrpc":"2.0","id":`+string(id)+`,"error":{"code":-1,"message":"user rejected"}}`+"\n")
+
+	text, isErr := toolText(t, next(t, lines))
+	if !isErr || !strings.Contains(text, "user rejected") {
+		t.Errorf("tool text = %q (isError %v), want client error", text, isErr)
+	}
+}
+
+func TestSamplingTimeoutCancels(t *testing.T) {
+	old := transport.RequestTimeout
+	transport.RequestTimeout = 50 * time.Millisecond
+	t.Cleanup(func() { transport.RequestTimeout = old })
+
+	_, lines, req := samplingClient(t)
+	cancel := next(t, lines)
+	params, _ := cancel["params"].(map[string]any)
+	if cancel["method"] != "notifications/cancelled" || params["requestId"] != req["id"] {
+		t.Fatalf("expected cancellation of %v, got %v", req["id"], cancel)
+	}
+	if text, isErr := toolText(t, next(t, lines)); !isErr || !strings.Contains(text, "deadline") {
+		t.Errorf("tool text = %q (isError %v), want timeout", text, isErr)
+	}
+}
+
+func TestSamplingUnsupportedWithoutCapability(t *testing.T) {
+	s := samplingServer()
+	w := newRecorder()
+	initialize(t, s, w, `{"protocolVersion":"2025-06-18","capabilities":{}}`)
+	s.HandleRequest(&types.JSONRPCRequest{
+		JSONRPC: "2.0", ID: 2, Method: "tools/call", Params: json.RawMessage(`{"name":"ask"}`),
+	}, w)
+	if len(w.requests) != 0 {
+		t.Errorf("sent %d requests to a client without sampling", len(w.requests))
+	}
+	res, _ := w.last(t).Result.(*types.ToolResult)
+	if res == nil || !res.IsError || res.Content[0].Text != h.ErrSamplingUnsupported.Error() {
+		t.Errorf("result = %#v", w.last(t).Result)
+	}
+}
+
+func TestSessionCloseFailsPendingRequests(t *testing.T) {
+	w := newRecorder()
+	done := make(chan error, 1)
+	go func() { done <- w.session.Request(context.Background(), w, "roots/list", nil, nil) }()
+	for i := 0; i < 100; i++ {
+		w.mu.Lock()
+		n := len(w.requests)
+		w.mu.Unlock()
+		if n > 0 {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	w.session.Close()
+	select {
+	case err := <-done:
+		var rpcErr *transport.RPCError
+		if !errors.As(err, &rpcErr) || rpcErr.Message != "session closed" {
+			t.Errorf("err = %v, want session closed", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("request still pending after Close")
+	}
+}
@@ -1,6 +1,7 @@
 package transport_test
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/orchestra-mcp/mcp/src/transport"
@@ -51,3 +52,28 @@ func TestGetToolsReturnsDefinitions(t *testing.T) {
 		t.Errorf("GetTools = %+v", defs)
 	}
 }
+
+func TestToolCallValidatesArguments(t *testing.T) {
+	s := transport.New("s", "0.1")
+	called := false
+	s.RegisterTool(types.Tool{
+		Definition: types.ToolDefinition{Name: "log", InputSchema: types.InputSchema{Type: "object", Properties: map[string]any{
+			"events": map[string]any{"type": "array", "items": map[string]any{
+				"type": "object", "properties": map[string]any{"type": map[string]any{"type": "string"}},
+			}},
+		}}},
+		Handler: func(map[string]any) (*types.ToolResult, error) { called = true; return nil, nil },
+	})
+	w := newRecorder()
+	s.HandleRequest(&types.JSONRPCRequest{
+		JSONRPC: "2.0", ID: 1, Method: "tools/call",
+		Params: json.RawMessage(`{"name":"log","arguments":{"events":[{"type":"a"},{"type":"b"},{"type":1}]}}`),
+	}, w)
+	resp := w.last(t)
+	if resp.Error == nil || resp.Error.Code != -32602 || resp.Error.Message != "events[2].type: must be string" {
+		t.Errorf("response = %+v", resp.Error)
+	}
+	if called {
+		t.Error("handler ran despite invalid arguments")
+	}
+}
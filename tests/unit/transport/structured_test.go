@@ -0,0 +1,81 @@
+package transport_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	h "github.com/orchestra-mcp/mcp/src/helpers"
+	"github.com/orchestra-mcp/mcp/src/transport"
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+func structuredServer() *transport.MCPServer {
+	s := transport.New("s", "1.0")
+	s.RegisterTool(types.Tool{
+		Definition: types.ToolDefinition{
+			Name:         "names",
+			InputSchema:  types.InputSchema{Type: "object"},
+			OutputSchema: h.ListOutputSchema("names", ""),
+		},
+		Handler: func(map[string]any) (*types.ToolResult, error) {
+			return h.ListResult("names", []string{"a", "b"}), nil
+		},
+	})
+	return s
+}
+
+func callNames(t *testing.T, s *transport.MCPServer, w *recorder) *types.ToolResult {
+	t.Helper()
+	s.HandleRequest(&types.JSONRPCRequest{
+		JSONRPC: "2.0", ID: 2, Method: "tools/call", Params: json.RawMessage(`{"name":"names"}`),
+	}, w)
+	res, ok := w.last(t).Result.(*types.ToolResult)
+	if !ok {
+		t.Fatalf("result = %#v", w.last(t))
+	}
+	return res
+}
+
+func listTools(t *testing.T, s *transport.MCPServer, w *recorder) []types.ToolDefinition {
+	t.Helper()
+	s.HandleRequest(&types.JSONRPCRequest{JSONRPC: "2.0", ID: 3, Method: "tools/list"}, w)
+	return w.last(t).Result.(types.ListToolsResult).Tools
+}
+
+func TestStructuredOutputForNewClients(t *testing.T) {
+	s := structuredServer()
+	w := newRecorder()
+	initialize(t, s, w, `{"protocolVersion":"2025-06-18"}`)
+
+	if defs := listTools(t, s, w); defs[0].OutputSchema == nil {
+		t.Error("outputSchema missing for 2025-06-18 client")
+	}
+	res := callNames(t, s, w)
+	sc, ok := res.StructuredContent.(map[string]any)
+	if !ok || len(sc["names"].([]string)) != 2 {
+		t.Errorf("structuredContent = %#v", res.StructuredContent)
+	}
+	if res.Content[0].Text == "" {
+		t.Error("text block must still be present")
+	}
+}
+
+func TestStructuredOutputHiddenFromOlderClients(t *testing.T) {
+	s := structuredServer()
+	w := newRecorder()
+	initialize(t, s, w, `{"protocolVersion":"2025-03-26"}`)
+
+	if defs := listTools(t, s, w); defs[0].OutputSchema != nil {
+		t.Error("outputSchema should be omitted before 2025-06-18")
+	}
+	if res := callNames(t, s, w); res.StructuredContent != nil {
+		t.Errorf("structuredContent should be omitted, got %#v", res.StructuredContent)
+	}
+
+	// The registered definition itself is left untouched.
+	w2 := newRecorder()
+	initialize(t, s, w2, `{"protocolVersion":"2025-06-18"}`)
+	if defs := listTools(t, s, w2); defs[0].OutputSchema == nil {
+		t.Error("stripping leaked into the shared definition")
+	}
+}
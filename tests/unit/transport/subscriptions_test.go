@@ -0,0 +1,125 @@
+package transport_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	h "github.com/orchestra-mcp/mcp/src/helpers"
+	"github.com/orchestra-mcp/mcp/src/transport"
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+func watchedServer(t *testing.T) (*transport.MCPServer, string) {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "status.toon")
+	os.WriteFile(file, []byte("status: todo\n"), 0o644)
+	s := transport.New("s", "1.0")
+	s.RegisterResource(types.Resource{
+		Definition: types.ResourceDefinition{URI: "toon://project/{slug}/status", Name: "status"},
+		Handler:    func(uri string) ([]types.ResourceContent, error) { return nil, nil },
+		Paths:      func(uri string) []string { return []string{file} },
+	})
+	s.RegisterTool(types.Tool{
+		Definition: types.ToolDefinition{Name: "touch", InputSchema: types.InputSchema{Type: "object"}},
+		Handler: func(map[string]any) (*types.ToolResult, error) {
+			return h.TextResult("ok"), os.WriteFile(file, []byte("status: in-progress\n"), 0o644)
+		},
+	})
+	return s, file
+}
+
+func TestSubscribeNotifiesAfterToolWrite(t *testing.T) {
+	s, _ := watchedServer(t)
+	in, lines := stdioPipe(t, s)
+
+	io.WriteString(in, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`+"\n")
+	init := next(t, lines)
+	caps := init["result"].(map[string]any)["capabilities"].(map[string]any)
+	if caps["resources"].(map[string]any)["subscribe"] != true {
+		t.Errorf("resources.subscribe not advertised: %v", caps)
+	}
+
+	io.WriteString(in, `{"jsonrpc":"2.0","id":2,"method":"resources/subscribe","params":{"uri":"toon://project/app/status"}}`+"\n")
+	if resp := next(t, lines); resp["id"] != float64(2) || resp["error"] != nil {
+		t.Fatalf("subscribe response = %v", resp)
+	}
+
+	io.WriteString(in, `{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"touch"}}`+"\n")
+	if resp := next(t, lines); resp["id"] != float64(3) {
+		t.Fatalf("expected tool result, got %v", resp)
+	}
+	msg := next(t, lines)
+	if msg["method"] != "notifications/resources/updated" {
+		t.Fatalf("expected resource update, got %v", msg)
+	}
+	if uri := msg["params"].(map[string]any)["uri"]; uri != "toon://project/app/status" {
+		t.Errorf("updated uri = %v", uri)
+	}
+}
+
+func TestResourceUpdatesQueuedOnSessionWriter(t *testing.T) {
+	s, _ := watchedServer(t)
+	stream := newRecorder()
+	initialize(t, s, stream, `{}`)
+	req := &recorder{session: stream.session} // a later request on the same session
+	call := func(id int, method, params string) {
+		s.HandleRequest(&types.JSONRPCRequest{JSONRPC: "2.0", ID: id, Method: method, Params: []byte(params)}, req)
+	}
+	call(2, "resources/subscribe", `{"uri":"toon://project/app/status"}`)
+	call(3, "tools/call", `{"name":"touch"}`)
+	s.RegisterTool(dummyTool("later"))
+
+	want := []string{"notifications/resources/updated", "notifications/tools/list_changed"}
+	var got []string
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		stream.mu.Lock()
+		got = got[:0]
+		for _, n := range stream.notifications {
+			got = append(got, n.Method)
+		}
+		stream.mu.Unlock()
+		if len(got) >= len(want) {
+			break
+		}
+	}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("session notifications = %v, want %v", got, want)
+	}
+	if len(req.notifications) != 0 {
+		t.Errorf("request writer got %v", req.notifications)
+	}
+}
+
+func TestUnsubscribeStopsWatching(t *testing.T) {
+	s, _ := watchedServer(t)
+	w := newRecorder()
+	call := func(id int, method, params string) {
+		s.HandleRequest(&types.JSONRPCRequest{JSONRPC: "2.0", ID: id, Method: method, Params: []byte(params)}, w)
+	}
+	call(1, "resources/subscribe", `{"uri":"toon://project/app/status"}`)
+	if got := w.Session().Subscriptions(); len(got) != 1 {
+		t.Fatalf("subscriptions = %v", got)
+	}
+	call(2, "resources/unsubscribe", `{"uri":"toon://project/app/status"}`)
+	if got := w.Session().Subscriptions(); len(got) != 0 {
+		t.Fatalf("subscriptions after unsubscribe = %v", got)
+	}
+	call(3, "tools/call", `{"name":"touch"}`)
+	if len(w.notifications) != 0 {
+		t.Errorf("unexpected notifications: %v", w.notifications)
+	}
+}
+
+func TestSubscribeUnknownResource(t *testing.T) {
+	s, _ := watchedServer(t)
+	w := newRecorder()
+	s.HandleRequest(&types.JSONRPCRequest{
+		JSONRPC: "2.0", ID: 1, Method: "resources/subscribe", Params: []byte(`{"uri":"toon://nope"}`),
+	}, w)
+	if w.last(t).Error == nil {
+		t.Error("expected error for unknown resource")
+	}
+}
@@ -0,0 +1,74 @@
+package transport_test
+
+import (
+	"testing"
+
+	"github.com/orchestra-mcp/mcp/src/transport"
+	"github.com/orchestra-mcp/mcp/src/types"
+)
+
+func templatedServer() *transport.MCPServer {
+	s := transport.New("s", "1.0")
+	s.RegisterResources([]types.Resource{
+		{
+			Definition: types.ResourceDefinition{URI: "toon://project/{slug}/status", Name: "status"},
+			Handler: func(uri string) ([]types.ResourceContent, error) {
+				return []types.ResourceContent{{URI: uri, Text: "ok"}}, nil
+			},
+			List: func() []types.ResourceDefinition {
+				return []types.ResourceDefinition{
+					{URI: "toon://project/b/status", Name: "b"},
+					{URI: "toon://project/a/status", Name: "a"},
+				}
+			},
+		},
+		{
+			Definition: types.ResourceDefinition{URI: "toon://project/{slug}/prd", Name: "prd"},
+			Handler:    func(uri string) ([]types.ResourceContent, error) { return nil, nil },
+		},
+		{
+			Definition: types.ResourceDefinition{URI: "docs://readme", Name: "readme"},
+			Handler:    func(uri string) ([]types.ResourceContent, error) { return nil, nil },
+		},
+	})
+	return s
+}
+
+func TestResourcesListExpandsTemplates(t *testing.T) {
+	got := templatedServer().GetResources()
+	want := []string{"docs://readme", "toon://project/a/status", "toon://project/b/status"}
+	if len(got) != len(want) {
+		t.Fatalf("resources = %+v", got)
+	}
+	for i, uri := range want {
+		if got[i].URI != uri {
+			t.Errorf("resources[%d] = %q, want %q", i, got[i].URI, uri)
+		}
+	}
+}
+
+func TestResourceTemplatesList(t *testing.T) {
+	s := templatedServer()
+	w := newRecorder()
+	s.HandleRequest(&types.JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "resources/templates/list"}, w)
+	res, ok := w.last(t).Result.(types.ListResourceTemplatesResult)
+	if !ok {
+		t.Fatalf("result = %#v", w.last(t))
+	}
+	if len(res.ResourceTemplates) != 2 || res.ResourceTemplates[0].URITemplate != "toon://project/{slug}/prd" {
+		t.Errorf("templates = %+v", res.ResourceTemplates)
+	}
+}
+
+func TestReadTemplatedResource(t *testing.T) {
+	s := templatedServer()
+	w := newRecorder()
+	s.HandleRequest(&types.JSONRPCRequest{
+		JSONRPC: "2.0", ID: 1, Method: "resources/read",
+		Params: []byte(`{"uri":"toon://project/a/status"}`),
+	}, w)
+	res, ok := w.last(t).Result.(types.ReadResourceResult)
+	if !ok || len(res.Contents) != 1 || res.Contents[0].URI != "toon://project/a/status" {
+		t.Errorf("read = %#v", w.last(t))
+	}
+}